@@ -4,17 +4,38 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
 	yaml "gopkg.in/yaml.v2"
 )
 
 // AppConfig holds the application configuration.
 type AppConfig struct {
 	ServiceName string     `yaml:"service_name"`
+	HTTP        HTTP       `yaml:"http"`
+	GRPC        GRPC       `yaml:"grpc"`
 	Databases   Databases  `yaml:"databases"`
 	Pickup      Pickup     `yaml:"pickup"`
 	WorkerPool  WorkerPool `yaml:"worker_pool"`
-	ShelfSpace  ShelfSpace `yaml:"shelf_space"`
+	Queue       Queue      `yaml:"queue"`
+	Leader      Leader     `yaml:"leader"`
+	Supervisor  Supervisor `yaml:"supervisor"`
+	Cooking     Cooking    `yaml:"cooking"`
+	Scheduling  Scheduling `yaml:"scheduling"`
+	Events      Events     `yaml:"events"`
+	Webhooks    Webhooks   `yaml:"webhooks"`
+	Outbox      Outbox     `yaml:"outbox"`
+	Shelves     []Shelf    `yaml:"shelves"`
+	Kitchens    []Kitchen  `yaml:"kitchens"`
+	Drivers     []Driver   `yaml:"drivers"`
+
+	filePath string // yaml file configuration was loaded from
 }
 
 // LoadConfig loads configuration from yaml files.
@@ -40,42 +61,303 @@ func (a *AppConfig) LoadConfig(filePath string) error {
 		return err
 	}
 
+	a.filePath = configFile
 	return nil
 }
 
+// FilePath returns the yaml file configuration was loaded from.
+func (a *AppConfig) FilePath() string {
+	return a.filePath
+}
+
+// HTTP holds HTTP server information.
+type HTTP struct {
+	Address string `yaml:"address"` // listen address ex: ":8080"
+}
+
+// GetBaseURL returns the URL the HTTP server can be reached at locally.
+func (h *HTTP) GetBaseURL() string {
+	// ":8080" => "http://localhost:8080"
+	if strings.HasPrefix(h.Address, ":") {
+		return fmt.Sprintf("http://localhost%s", h.Address)
+	}
+
+	return fmt.Sprintf("http://%s", h.Address)
+}
+
+// GRPC holds gRPC server information.
+type GRPC struct {
+	Address string `yaml:"address"` // listen address ex: ":9090"
+}
+
 // Databases holds database connection information.
 type Databases struct {
 	MySQL MySQL `yaml:"mysql"`
+	Redis Redis `yaml:"redis"`
 }
 
 // MySQL holds master and slave SQL connection urls.
 type MySQL struct {
 	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
 	Database string `yaml:"database"`
 }
 
 // GetConnectionString returns MySQL connection string.
 func (m *MySQL) GetConnectionString() string {
-	// Fetch required environment variables.
-	connectionStr := fmt.Sprintf("%s:@tcp(localhost:3306)/%s?charset=utf8&parseTime=True&loc=Local", m.Username, m.Database)
+	connectionStr := fmt.Sprintf(
+		"%s:%s@tcp(%s:%d)/%s?charset=utf8&parseTime=True&loc=Local", m.Username, m.Password, m.Host, m.Port, m.Database)
 
 	return connectionStr
 }
 
+// Redis holds Redis connection pool information.
+type Redis struct {
+	Address     string `yaml:"address"`      // ex: ":6379"
+	MaxIdle     int    `yaml:"max_idle"`     // max idle connections in pool
+	MaxActive   int    `yaml:"max_active"`   // max connections in pool
+	IdleTimeout int    `yaml:"idle_timeout"` // seconds before an idle connection is closed
+}
+
 // Pickup holds pickup information.
 type Pickup struct {
-	Mean float64 `yaml:"mean"` // mean for poisson distribution
+	Mean     float64 `yaml:"mean"`     // mean for poisson distribution
+	Strategy string  `yaml:"strategy"` // courier matching strategy, enum: ['matched', 'fifo']
 }
 
 // WorkerPool holds max worker count.
 type WorkerPool struct {
-	MaxWorkers int `yaml:"max_workers"` // num of max workers.
+	MaxWorkers int `yaml:"max_workers"` // num of max workers, each cooks one order at a time.
+}
+
+// Queue holds how orders wait in Redis for workers to pull them off.
+type Queue struct {
+	Backend       string `yaml:"backend"`        // enum: ['list', 'stream'], stream requires Redis 6.2+
+	ConsumerGroup string `yaml:"consumer_group"` // consumer group workers of every instance read streams as
+	MaxLength     int    `yaml:"max_length"`     // approximate number of orders kept on a stream
+	BlockTimeout  int    `yaml:"block_timeout"`  // milliseconds a worker waits on empty streams
+	ClaimTimeout  int    `yaml:"claim_timeout"`  // seconds before an unacknowledged order is taken over, orders cooking are kept claimed
+}
+
+// GetStreamOptions returns how workers share the order streams.
+func (q *Queue) GetStreamOptions() entity.StreamOptions {
+	return entity.StreamOptions{
+		ConsumerGroup: q.ConsumerGroup,
+		MaxLength:     q.MaxLength,
+		BlockTimeout:  time.Duration(q.BlockTimeout) * time.Millisecond,
+		ClaimTimeout:  time.Duration(q.ClaimTimeout) * time.Second,
+	}
+}
+
+// Leader holds how instances elect the one singleton jobs run on.
+type Leader struct {
+	Key          string `yaml:"key"`           // Redis key holding the leader's lease ex: "Leader"
+	LeaseTimeout int    `yaml:"lease_timeout"` // seconds before another instance takes over from a dead leader
+}
+
+// Supervisor holds how worker goroutines that panic are restarted.
+type Supervisor struct {
+	InitialBackoff     int `yaml:"initial_backoff"`      // seconds before the first restart, doubled on every crash in a row
+	MaxBackoff         int `yaml:"max_backoff"`          // max seconds between restarts, workers running longer are stable again
+	CrashLoopThreshold int `yaml:"crash_loop_threshold"` // crashes in a row before a worker fails the health check
+}
+
+// Cooking holds the preparation stage orders go through before they are shelved.
+type Cooking struct {
+	Stations     int            `yaml:"stations"`      // orders cooked at once per kitchen on each instance
+	PrepTimes    map[string]int `yaml:"prep_times"`    // seconds to cook an order per temp ex: {hot: 5}
+	RequeueAfter int            `yaml:"requeue_after"` // seconds an order cooks before it is queued again, ex: its instance stopped
+}
+
+// getPrepTimes returns the prep time of each order temperature.
+func (c *Cooking) getPrepTimes() map[entity.OrderTemp]int {
+	prepTimes := make(map[entity.OrderTemp]int)
+	for temp, prepTime := range c.PrepTimes {
+		prepTimes[entity.OrderTemp(temp)] = prepTime
+	}
+
+	return prepTimes
+}
+
+// Scheduling holds how scheduled orders are released to the order queue.
+type Scheduling struct {
+	LeadTime int `yaml:"lead_time"` // seconds of slack before an order's prep time, ex: 300
+}
+
+// Events holds how events are streamed to subscribers.
+type Events struct {
+	BufferSize int `yaml:"buffer_size"` // recent events kept to replay on resume, 0 disables replay
+}
+
+// Webhooks holds how order status changes are delivered to webhook subscriptions.
+type Webhooks struct {
+	Workers        int `yaml:"workers"`         // deliveries sent at once
+	MaxAttempts    int `yaml:"max_attempts"`    // attempts before a delivery is marked failed
+	InitialBackoff int `yaml:"initial_backoff"` // seconds before the first retry, doubled on every retry
+	MaxBackoff     int `yaml:"max_backoff"`     // max seconds between retries
+	Timeout        int `yaml:"timeout"`         // seconds to wait on a subscription's response
 }
 
-// ShelfSpace holds capacity of each type of shelf.
-type ShelfSpace struct {
-	Hot      int `yaml:"hot"`
-	Cold     int `yaml:"cold"`
-	Frozen   int `yaml:"frozen"`
-	Overflow int `yaml:"overflow"`
+// Outbox holds where order events recorded in the outbox are relayed to.
+type Outbox struct {
+	Sinks           []string `yaml:"sinks"`             // enum: ['redis_stream', 'webhook', 'log']
+	BatchSize       int      `yaml:"batch_size"`        // events relayed per poll
+	MaxAttempts     int      `yaml:"max_attempts"`      // attempts before an event is parked and later events move on
+	Stream          string   `yaml:"stream"`            // Redis stream of the redis_stream sink ex: "Events"
+	StreamMaxLength int      `yaml:"stream_max_length"` // approximate number of events kept on the stream
+}
+
+// Shelf holds the definition of a shelf in the kitchen's shelf catalog.
+type Shelf struct {
+	Name          string   `yaml:"name"`           // ex: "hot", "ambient", "warm-holding"
+	Capacity      int      `yaml:"capacity"`       // max orders on the shelf
+	Temps         []string `yaml:"temps"`          // accepted order temperatures ex: ["hot", "warm"]
+	DecayModifier float64  `yaml:"decay_modifier"` // multiplies decay rate of orders on the shelf, defaults to 1
+	Overflow      bool     `yaml:"overflow"`       // only used once dedicated shelves are full
+	Reserved      int      `yaml:"reserved"`       // space kept for express and vip orders
+}
+
+// GetShelfCatalog returns the shelf catalog built from the shelf definitions.
+func (a *AppConfig) GetShelfCatalog() (*entity.ShelfCatalog, error) {
+	return newShelfCatalog(a.Shelves)
+}
+
+// newShelfCatalog returns the shelf catalog built from shelf definitions.
+func newShelfCatalog(shelfDefinitions []Shelf) (*entity.ShelfCatalog, error) {
+	var shelves []entity.Shelf
+	for _, shelf := range shelfDefinitions {
+		var temps []entity.OrderTemp
+		for _, temp := range shelf.Temps {
+			temps = append(temps, entity.OrderTemp(temp))
+		}
+
+		decayModifier := shelf.DecayModifier
+		if decayModifier == 0 {
+			decayModifier = 1
+		}
+
+		shelves = append(shelves, entity.Shelf{
+			Type:          entity.ShelfType(shelf.Name),
+			Capacity:      shelf.Capacity,
+			Temps:         temps,
+			DecayModifier: decayModifier,
+			Overflow:      shelf.Overflow,
+			Reserved:      shelf.Reserved,
+		})
+	}
+
+	return entity.NewShelfCatalog(shelves)
+}
+
+// defaultShelves returns the shelf definitions of the default shelf catalog.
+func defaultShelves() []Shelf {
+	var shelves []Shelf
+	for _, shelf := range entity.DefaultShelfCatalog().Shelves {
+		var temps []string
+		for _, temp := range shelf.Temps {
+			temps = append(temps, string(temp))
+		}
+
+		shelves = append(shelves, Shelf{
+			Name:          string(shelf.Type),
+			Capacity:      shelf.Capacity,
+			Temps:         temps,
+			DecayModifier: shelf.DecayModifier,
+			Overflow:      shelf.Overflow,
+			Reserved:      shelf.Reserved,
+		})
+	}
+
+	return shelves
+}
+
+// Kitchen holds a kitchen location.
+type Kitchen struct {
+	UUID            string  `yaml:"uuid"`
+	Name            string  `yaml:"name"`             // ex: "downtown"
+	Shelves         []Shelf `yaml:"shelves"`          // defaults to the top level shelves
+	CookingStations int     `yaml:"cooking_stations"` // defaults to the top level cooking stations
+}
+
+// GetKitchens returns the configured kitchens, or the default
+// kitchen with the top level shelves if none are configured.
+func (a *AppConfig) GetKitchens() ([]*entity.Kitchen, error) {
+	if len(a.Kitchens) == 0 {
+		shelfCatalog, err := a.GetShelfCatalog()
+		if err != nil {
+			return nil, err
+		}
+
+		kitchen := entity.Kitchen{
+			UUID:            entity.DefaultKitchenUUID,
+			Name:            "default",
+			ShelfCatalog:    shelfCatalog,
+			CookingStations: a.Cooking.Stations,
+			PrepTimes:       a.Cooking.getPrepTimes(),
+		}
+		return []*entity.Kitchen{&kitchen}, nil
+	}
+
+	var kitchens []*entity.Kitchen
+	for _, kitchenDefinition := range a.Kitchens {
+		kitchenUUID, err := guuid.FromString(kitchenDefinition.UUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				exception.ErrInvalidInput, "kitchen %s uuid is invalid - uuid: %s", kitchenDefinition.Name, kitchenDefinition.UUID)
+		}
+
+		shelves := kitchenDefinition.Shelves
+		if len(shelves) == 0 {
+			shelves = a.Shelves
+		}
+
+		shelfCatalog, err := newShelfCatalog(shelves)
+		if err != nil {
+			return nil, errors.Wrapf(err, "kitchen %s has invalid shelves", kitchenDefinition.Name)
+		}
+
+		cookingStations := kitchenDefinition.CookingStations
+		if cookingStations == 0 {
+			cookingStations = a.Cooking.Stations
+		}
+
+		kitchens = append(kitchens, &entity.Kitchen{
+			UUID:            kitchenUUID,
+			Name:            kitchenDefinition.Name,
+			ShelfCatalog:    shelfCatalog,
+			CookingStations: cookingStations,
+			PrepTimes:       a.Cooking.getPrepTimes(),
+		})
+	}
+
+	return kitchens, nil
+}
+
+// Driver holds the credentials of a driver app.
+type Driver struct {
+	UUID  string `yaml:"uuid"`
+	Name  string `yaml:"name"`  // ex: "alice"
+	Token string `yaml:"token"` // secret, override with KITCHEN_DRIVERS_<NAME>_TOKEN
+}
+
+// GetDrivers returns the drivers allowed to connect to the driver channel.
+func (a *AppConfig) GetDrivers() ([]*entity.Driver, error) {
+	var drivers []*entity.Driver
+	for _, driverDefinition := range a.Drivers {
+		driverUUID, err := guuid.FromString(driverDefinition.UUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				exception.ErrInvalidInput, "driver %s uuid is invalid - uuid: %s", driverDefinition.Name, driverDefinition.UUID)
+		}
+
+		drivers = append(drivers, &entity.Driver{
+			UUID:  driverUUID,
+			Name:  driverDefinition.Name,
+			Token: driverDefinition.Token,
+		})
+	}
+
+	return drivers, nil
 }
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kitchen-delivery/entity"
+
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Precedence(t *testing.T) {
+	os.Setenv("KITCHEN_DATABASES_MYSQL_HOST", "mysql.internal")
+	os.Setenv("KITCHEN_WORKER_POOL_MAX_WORKERS", "10")
+	os.Setenv("KITCHEN_SHELVES_COLD_CAPACITY", "30")
+	os.Setenv("KITCHEN_SHELVES_HOT_CAPACITY", "30")
+	defer os.Unsetenv("KITCHEN_DATABASES_MYSQL_HOST")
+	defer os.Unsetenv("KITCHEN_WORKER_POOL_MAX_WORKERS")
+	defer os.Unsetenv("KITCHEN_SHELVES_COLD_CAPACITY")
+	defer os.Unsetenv("KITCHEN_SHELVES_HOT_CAPACITY")
+
+	cfg := AppConfig{}
+	err := cfg.Load([]string{
+		"--config", "development.yaml", "--worker-pool-max-workers", "20", "--shelf-capacity", "hot=40"})
+	assert.Nil(t, err)
+
+	// Flags override environment variables.
+	assert.Equal(t, 20, cfg.WorkerPool.MaxWorkers)
+	assert.Equal(t, 40, cfg.Shelves[0].Capacity)
+	// Environment variables override the yaml file.
+	assert.Equal(t, "mysql.internal", cfg.Databases.MySQL.Host)
+	assert.Equal(t, 30, cfg.Shelves[1].Capacity)
+	// The yaml file overrides defaults.
+	assert.Equal(t, 15, cfg.Shelves[2].Capacity)
+	assert.Equal(t, "matched", cfg.Pickup.Strategy)
+}
+
+func TestLoad_InvalidOverride(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml", "--shelf-capacity", "hot=ten"})
+	assert.NotNil(t, err)
+
+	err = cfg.Load([]string{"--config", "development.yaml", "--shelf-capacity", "ambient=10"})
+	assert.NotNil(t, err)
+}
+
+func TestLoad_ListAndMapOverrides(t *testing.T) {
+	os.Setenv("KITCHEN_OUTBOX_SINKS", "redis_stream, log")
+	defer os.Unsetenv("KITCHEN_OUTBOX_SINKS")
+
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml", "--cooking-prep-times", "hot=5,cold=1"})
+	assert.Nil(t, err)
+
+	// Lists are comma separated and maps are replaced as a whole.
+	assert.Equal(t, []string{"redis_stream", "log"}, cfg.Outbox.Sinks)
+	assert.Equal(t, map[string]int{"hot": 5, "cold": 1}, cfg.Cooking.PrepTimes)
+
+	err = cfg.Load([]string{"--config", "development.yaml", "--cooking-prep-times", "hot=five"})
+	assert.NotNil(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+	assert.Nil(t, cfg.Validate())
+
+	cfg.Shelves[0].Capacity = -1
+	cfg.WorkerPool.MaxWorkers = 0
+	cfg.Databases.MySQL.Database = ""
+	cfg.Pickup.Strategy = "random"
+
+	// Every problem is reported together.
+	err = cfg.Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"databases.mysql.database: is required",
+		"pickup.strategy: must be one of [matched fifo], got \"random\"",
+		"worker_pool.max_workers: must be greater than 0, got 0",
+		"shelves[0].capacity: must be greater than 0, got -1",
+	}, validationErr.Problems)
+}
+
+func TestValidate_Queue(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+
+	// Stream options are only required by the stream backend.
+	cfg.Queue.ConsumerGroup = ""
+	assert.Nil(t, cfg.Validate())
+
+	cfg.Queue.Backend = "stream"
+	cfg.Queue.ClaimTimeout = 0
+	err = cfg.Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"queue.consumer_group: is required",
+		"queue.claim_timeout: must be greater than 0, got 0",
+	}, validationErr.Problems)
+
+	cfg.Queue.Backend = "kafka"
+	err = cfg.Validate()
+	validationErr, ok = err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"queue.backend: must be one of [list stream], got \"kafka\""}, validationErr.Problems)
+}
+
+func TestValidate_CookingStations(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+
+	// Every kitchen cooks a bounded number of orders at once, kitchens default to cooking.stations.
+	cfg.Cooking.Stations = 0
+	cfg.Kitchens = []Kitchen{{UUID: guuid.NewV4().String(), Name: "downtown", CookingStations: -1}}
+	err = cfg.Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"cooking.stations: must be greater than 0, got 0",
+		"kitchens[0].cooking_stations: must not be negative, got -1",
+	}, validationErr.Problems)
+}
+
+func TestValidate_CookingRequeueAfter(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+
+	// Orders are only queued again once they cook for longer than any prep time.
+	cfg.Cooking.RequeueAfter = 3
+	err = cfg.Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"cooking.requeue_after: must be greater than cooking.prep_times.hot (3), got 3",
+	}, validationErr.Problems)
+}
+
+func TestDiff(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+
+	newCfg := AppConfig{}
+	err = newCfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+	newCfg.Shelves[0].Capacity = 20
+	newCfg.Shelves = append(newCfg.Shelves, Shelf{Name: "ambient", Capacity: 10, Temps: []string{"ambient"}})
+	newCfg.WorkerPool.MaxWorkers = 2
+
+	assert.Equal(t, []string{
+		"worker_pool.max_workers: 5 => 2",
+		"shelves.hot.capacity: 15 => 20",
+		"shelves.ambient.capacity: <none> => 10",
+	}, cfg.Diff(newCfg))
+}
+
+func TestGetKitchens(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+
+	// Without kitchens every order goes to the default kitchen.
+	kitchens, err := cfg.GetKitchens()
+	assert.Nil(t, err)
+	assert.Len(t, kitchens, 1)
+	assert.Equal(t, entity.DefaultKitchenUUID, kitchens[0].UUID)
+
+	// Kitchens without shelves use the top level shelves.
+	cfg.Kitchens = []Kitchen{
+		{Name: "downtown", UUID: "8f1c5a1e-3c1f-4b8e-9a57-0c7f3f6f2a10"},
+		{Name: "airport", UUID: "1d3c0b7a-2a4e-4f7e-8f13-6d1c9b2e7c44", Shelves: []Shelf{
+			{Name: "hot", Capacity: 5, Temps: []string{"hot"}},
+		}},
+	}
+	kitchens, err = cfg.GetKitchens()
+	assert.Nil(t, err)
+	assert.Len(t, kitchens, 2)
+	assert.Len(t, kitchens[0].ShelfCatalog.Shelves, len(cfg.Shelves))
+	assert.Len(t, kitchens[1].ShelfCatalog.Shelves, 1)
+
+	// Only shelves of configured kitchens can be resized.
+	cfg = AppConfig{}
+	err = cfg.Load([]string{"--config", "development.yaml", "--shelf-capacity", "airport/hot=8"})
+	assert.NotNil(t, err)
+}
+
+func TestReload_InvalidWithSettingsKeptUntilRestart(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+	// Reserved space is only changed on restart.
+	cfg.Shelves[0].Reserved = 10
+
+	reloads := 0
+	reloader := NewReloader(cfg, []string{"--config", "development.yaml", "--shelf-capacity", "hot=5"})
+	reloader.OnReload(func(cfg AppConfig) {
+		reloads++
+	})
+
+	// The new capacity is valid on its own but smaller than the space the shelf still reserves.
+	reloader.Reload()
+	assert.Equal(t, 0, reloads)
+	assert.Equal(t, 15, reloader.cfg.Shelves[0].Capacity)
+
+	reloader.args = []string{"--config", "development.yaml", "--shelf-capacity", "hot=12"}
+	reloader.Reload()
+	assert.Equal(t, 1, reloads)
+	assert.Equal(t, 12, reloader.cfg.Shelves[0].Capacity)
+	assert.Equal(t, 10, reloader.cfg.Shelves[0].Reserved)
+}
+
+func TestCopy(t *testing.T) {
+	cfg := AppConfig{}
+	err := cfg.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+	cfg.Drivers = []Driver{{UUID: guuid.NewV4().String(), Name: "alice", Token: "secret"}}
+	cfg.Kitchens = []Kitchen{{UUID: guuid.NewV4().String(), Name: "downtown", Shelves: cfg.Shelves}}
+
+	// Changes to a copy never reach configuration handed to reload callbacks.
+	copied := cfg.copy()
+	copied.Shelves[0].Capacity = 1
+	copied.Shelves[0].Temps[0] = "warm"
+	copied.Kitchens[0].Shelves[0].Temps[0] = "warm"
+	copied.Drivers[0].Token = "changed"
+	copied.Cooking.PrepTimes["hot"] = 10
+	copied.Outbox.Sinks[0] = "redis_stream"
+
+	original := AppConfig{}
+	err = original.Load([]string{"--config", "development.yaml"})
+	assert.Nil(t, err)
+	assert.Equal(t, original.Shelves, cfg.Shelves)
+	assert.Equal(t, original.Shelves, cfg.Kitchens[0].Shelves)
+	assert.Equal(t, "secret", cfg.Drivers[0].Token)
+	assert.Equal(t, original.Cooking.PrepTimes, cfg.Cooking.PrepTimes)
+	assert.Equal(t, original.Outbox.Sinks, cfg.Outbox.Sinks)
+}
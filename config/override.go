@@ -0,0 +1,381 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kitchen-delivery/entity"
+
+	"github.com/pkg/errors"
+)
+
+// envPrefix is the prefix of every environment variable override,
+// ex: KITCHEN_DATABASES_MYSQL_HOST overrides databases.mysql.host.
+const envPrefix = "KITCHEN_"
+
+// DefaultConfigFile is the configuration file used when none is passed in.
+const DefaultConfigFile = "config/development.yaml"
+
+// Load loads configuration from a yaml file, environment variables and
+// command line flags. Flags take precedence over environment variables,
+// which take precedence over the yaml file, which takes precedence over defaults.
+// The yaml file is chosen with the --config flag or KITCHEN_CONFIG.
+// Shelves are defined in the yaml file, their capacities are overridden with
+// KITCHEN_SHELVES_<NAME>_CAPACITY or --shelf-capacity <name>=<capacity>, and
+// KITCHEN_KITCHENS_<KITCHEN>_SHELVES_<NAME>_CAPACITY or
+// --shelf-capacity <kitchen>/<name>=<capacity> for a kitchen's own shelves.
+// List settings are overridden with comma separated values ex: redis_stream,log
+// and map settings with comma separated key=value pairs ex: hot=5,cold=1.
+func (a *AppConfig) Load(args []string) error {
+	a.setDefaults()
+
+	flagSet := flag.NewFlagSet("kitchen-delivery", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Usage of %s:\n", flagSet.Name())
+		flagSet.PrintDefaults()
+		fmt.Fprint(flagSet.Output(), "\nShelves, kitchens and drivers are only defined in the yaml file.\n"+
+			"Shelf capacities are overridden with --shelf-capacity, kitchen uuids and cooking stations\n"+
+			"and driver tokens with environment variables ex: KITCHEN_KITCHENS_DOWNTOWN_COOKING_STATIONS.\n")
+	}
+	configFile := flagSet.String("config", DefaultConfigFile, "path to yaml configuration file")
+	shelfCapacities := shelfCapacityFlag{}
+	flagSet.Var(&shelfCapacities, "shelf-capacity", "shelf capacity as [<kitchen>/]<name>=<capacity>, can be repeated")
+
+	// Register a flag for every setting, ex: --databases-mysql-host.
+	settings := a.baseSettings()
+	flagValues := make(map[string]*string)
+	for _, setting := range settings {
+		flagValues[setting.path] = flagSet.String(setting.flagName(), "", setting.usage)
+	}
+
+	err := flagSet.Parse(args)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse flags")
+	}
+
+	// Only explicitly passed flags override other sources.
+	setFlags := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	// First, the yaml file.
+	filePath := *configFile
+	if envFilePath, ok := os.LookupEnv(envPrefix + "CONFIG"); ok && !setFlags["config"] {
+		filePath = envFilePath
+	}
+
+	err = a.LoadConfig(filePath)
+	if err != nil {
+		return err
+	}
+
+	// Second, environment variables.
+	// Shelf settings are only known once the yaml file is loaded.
+	for _, setting := range a.settings() {
+		value, ok := os.LookupEnv(setting.envName())
+		if !ok {
+			continue
+		}
+
+		err = setting.set(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid environment variable %s", setting.envName())
+		}
+	}
+
+	// Last, command line flags.
+	for _, setting := range settings {
+		if !setFlags[setting.flagName()] {
+			continue
+		}
+
+		err = setting.set(*flagValues[setting.path])
+		if err != nil {
+			return errors.Wrapf(err, "invalid flag --%s", setting.flagName())
+		}
+	}
+	for _, shelfCapacity := range shelfCapacities {
+		setting, ok := a.getSetting(shelfCapacity.path())
+		if !ok {
+			return fmt.Errorf("invalid flag --shelf-capacity, %s is not a shelf", shelfCapacity.name)
+		}
+
+		err = setting.set(shelfCapacity.capacity)
+		if err != nil {
+			return errors.Wrap(err, "invalid flag --shelf-capacity")
+		}
+	}
+
+	return nil
+}
+
+// shelfCapacity is a shelf capacity override.
+type shelfCapacity struct {
+	name     string
+	capacity string
+}
+
+// path returns the setting path of the shelf's capacity.
+func (s *shelfCapacity) path() string {
+	// "downtown/hot" => "kitchens.downtown.shelves.hot.capacity"
+	parts := strings.SplitN(s.name, "/", 2)
+	if len(parts) == 2 {
+		return fmt.Sprintf("kitchens.%s.%s", parts[0], shelfCapacityPath(parts[1]))
+	}
+
+	return shelfCapacityPath(s.name)
+}
+
+// shelfCapacityFlag collects --shelf-capacity <name>=<capacity> flags.
+type shelfCapacityFlag []shelfCapacity
+
+func (f *shelfCapacityFlag) String() string {
+	return fmt.Sprintf("%v", *f)
+}
+
+func (f *shelfCapacityFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("must be <name>=<capacity>, got %q", value)
+	}
+
+	*f = append(*f, shelfCapacity{name: parts[0], capacity: parts[1]})
+	return nil
+}
+
+// shelfCapacityPath returns the setting path of a shelf's capacity.
+func shelfCapacityPath(shelfName string) string {
+	return fmt.Sprintf("shelves.%s.capacity", shelfName)
+}
+
+// setDefaults sets values used when a setting is not configured anywhere.
+func (a *AppConfig) setDefaults() {
+	a.HTTP.Address = ":8080"
+	a.GRPC.Address = ":9090"
+	a.Databases.MySQL.Host = "localhost"
+	a.Databases.MySQL.Port = 3306
+	a.Databases.Redis.Address = ":6379"
+	a.Databases.Redis.MaxIdle = 5
+	a.Databases.Redis.MaxActive = 5
+	a.Databases.Redis.IdleTimeout = 20
+	a.Queue.Backend = string(entity.QueueBackendList)
+	a.Queue.ConsumerGroup = "workers"
+	a.Queue.MaxLength = 100000
+	a.Queue.BlockTimeout = 1000
+	a.Queue.ClaimTimeout = 60
+	a.Leader.Key = "Leader"
+	a.Leader.LeaseTimeout = 15
+	a.Supervisor.InitialBackoff = 1
+	a.Supervisor.MaxBackoff = 60
+	a.Supervisor.CrashLoopThreshold = 5
+	a.Events.BufferSize = 1000
+	a.Webhooks.Workers = 4
+	a.Webhooks.MaxAttempts = 8
+	a.Webhooks.InitialBackoff = 5
+	a.Webhooks.MaxBackoff = 3600
+	a.Webhooks.Timeout = 10
+	a.Outbox.Sinks = []string{string(entity.OutboxSinkWebhook)}
+	a.Outbox.BatchSize = 100
+	a.Outbox.MaxAttempts = 10
+	a.Outbox.Stream = "Events"
+	a.Outbox.StreamMaxLength = 10000
+	a.Shelves = defaultShelves()
+}
+
+// setting is a single overridable configuration value.
+type setting struct {
+	path  string      // yaml path ex: "databases.mysql.host"
+	usage string      // flag description
+	value interface{} // pointer to the field in AppConfig
+}
+
+// settings returns every setting that can be overridden.
+func (a *AppConfig) settings() []setting {
+	settings := a.baseSettings()
+	for i := range a.Shelves {
+		shelf := &a.Shelves[i]
+		settings = append(settings, setting{
+			shelfCapacityPath(shelf.Name), fmt.Sprintf("%s shelf capacity", shelf.Name), &shelf.Capacity})
+	}
+
+	// Kitchens with their own shelves ex: "kitchens.downtown.shelves.hot.capacity".
+	for i := range a.Kitchens {
+		kitchen := &a.Kitchens[i]
+		settings = append(settings, setting{
+			fmt.Sprintf("kitchens.%s.uuid", kitchen.Name), fmt.Sprintf("%s kitchen uuid", kitchen.Name), &kitchen.UUID})
+		settings = append(settings, setting{
+			fmt.Sprintf("kitchens.%s.cooking_stations", kitchen.Name),
+			fmt.Sprintf("%s kitchen cooking stations", kitchen.Name),
+			&kitchen.CookingStations,
+		})
+		for j := range kitchen.Shelves {
+			shelf := &kitchen.Shelves[j]
+			settings = append(settings, setting{
+				fmt.Sprintf("kitchens.%s.%s", kitchen.Name, shelfCapacityPath(shelf.Name)),
+				fmt.Sprintf("%s kitchen %s shelf capacity", kitchen.Name, shelf.Name),
+				&shelf.Capacity,
+			})
+		}
+	}
+
+	// Driver tokens are secrets so they are usually set with environment variables,
+	// ex: "drivers.alice.token" => KITCHEN_DRIVERS_ALICE_TOKEN.
+	for i := range a.Drivers {
+		driver := &a.Drivers[i]
+		settings = append(settings, setting{
+			fmt.Sprintf("drivers.%s.token", driver.Name), fmt.Sprintf("%s driver token", driver.Name), &driver.Token})
+	}
+
+	return settings
+}
+
+// baseSettings returns every setting that does not depend on the yaml file.
+func (a *AppConfig) baseSettings() []setting {
+	return []setting{
+		{"service_name", "service name", &a.ServiceName},
+		{"http.address", "HTTP listen address", &a.HTTP.Address},
+		{"grpc.address", "gRPC listen address", &a.GRPC.Address},
+		{"databases.mysql.username", "MySQL username", &a.Databases.MySQL.Username},
+		{"databases.mysql.password", "MySQL password", &a.Databases.MySQL.Password},
+		{"databases.mysql.host", "MySQL host", &a.Databases.MySQL.Host},
+		{"databases.mysql.port", "MySQL port", &a.Databases.MySQL.Port},
+		{"databases.mysql.database", "MySQL database name", &a.Databases.MySQL.Database},
+		{"databases.redis.address", "Redis address", &a.Databases.Redis.Address},
+		{"databases.redis.max_idle", "Redis max idle connections", &a.Databases.Redis.MaxIdle},
+		{"databases.redis.max_active", "Redis max active connections", &a.Databases.Redis.MaxActive},
+		{"databases.redis.idle_timeout", "Redis idle connection timeout in seconds", &a.Databases.Redis.IdleTimeout},
+		{"pickup.mean", "mean seconds between courier arrivals", &a.Pickup.Mean},
+		{"pickup.strategy", "courier matching strategy", &a.Pickup.Strategy},
+		{"worker_pool.max_workers", "number of order workers", &a.WorkerPool.MaxWorkers},
+		{"queue.backend", "order queue backend", &a.Queue.Backend},
+		{"queue.consumer_group", "consumer group order workers read streams as", &a.Queue.ConsumerGroup},
+		{"queue.max_length", "approximate number of orders kept on a stream", &a.Queue.MaxLength},
+		{"queue.block_timeout", "milliseconds order workers wait on empty streams", &a.Queue.BlockTimeout},
+		{"queue.claim_timeout", "seconds before an unacknowledged order is taken over", &a.Queue.ClaimTimeout},
+		{"leader.key", "Redis key holding the leader's lease", &a.Leader.Key},
+		{"leader.lease_timeout", "seconds before another instance takes over from a dead leader", &a.Leader.LeaseTimeout},
+		{"supervisor.initial_backoff", "seconds before a crashed worker is first restarted", &a.Supervisor.InitialBackoff},
+		{"supervisor.max_backoff", "max seconds between restarts of a crashed worker", &a.Supervisor.MaxBackoff},
+		{"supervisor.crash_loop_threshold", "worker crashes in a row before the health check fails", &a.Supervisor.CrashLoopThreshold},
+		{"cooking.stations", "orders cooked at once per kitchen on each instance", &a.Cooking.Stations},
+		{"cooking.prep_times", "seconds to cook an order per temp as <temp>=<seconds>,...", &a.Cooking.PrepTimes},
+		{"cooking.requeue_after", "seconds an order cooks before it is queued again", &a.Cooking.RequeueAfter},
+		{"scheduling.lead_time", "seconds scheduled orders are released before their prep time", &a.Scheduling.LeadTime},
+		{"events.buffer_size", "recent events replayed to subscribers that resume", &a.Events.BufferSize},
+		{"webhooks.workers", "number of webhook delivery workers", &a.Webhooks.Workers},
+		{"webhooks.max_attempts", "attempts before a webhook delivery fails", &a.Webhooks.MaxAttempts},
+		{"webhooks.initial_backoff", "seconds before the first webhook delivery retry", &a.Webhooks.InitialBackoff},
+		{"webhooks.max_backoff", "max seconds between webhook delivery retries", &a.Webhooks.MaxBackoff},
+		{"webhooks.timeout", "seconds to wait on a webhook response", &a.Webhooks.Timeout},
+		{"outbox.sinks", "comma separated sinks outbox events are relayed to", &a.Outbox.Sinks},
+		{"outbox.batch_size", "outbox events relayed per poll", &a.Outbox.BatchSize},
+		{"outbox.max_attempts", "attempts before an outbox event is parked", &a.Outbox.MaxAttempts},
+		{"outbox.stream", "Redis stream outbox events are relayed to", &a.Outbox.Stream},
+		{"outbox.stream_max_length", "approximate number of events kept on the outbox stream", &a.Outbox.StreamMaxLength},
+	}
+}
+
+// getSetting returns the setting at a yaml path.
+func (a *AppConfig) getSetting(path string) (*setting, bool) {
+	for _, setting := range a.settings() {
+		if setting.path == path {
+			return &setting, true
+		}
+	}
+
+	return nil, false
+}
+
+// envName returns the environment variable name of a setting.
+func (s *setting) envName() string {
+	// "databases.mysql.host" => "KITCHEN_DATABASES_MYSQL_HOST"
+	// "shelves.warm-holding.capacity" => "KITCHEN_SHELVES_WARM_HOLDING_CAPACITY"
+	return envPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(s.path))
+}
+
+// flagName returns the command line flag name of a setting.
+func (s *setting) flagName() string {
+	// "worker_pool.max_workers" => "worker-pool-max-workers"
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.path)
+}
+
+// String returns the setting's current value.
+func (s *setting) String() string {
+	switch field := s.value.(type) {
+	case *string:
+		return *field
+	case *int:
+		return strconv.Itoa(*field)
+	case *float64:
+		return strconv.FormatFloat(*field, 'f', -1, 64)
+	case *[]string:
+		return strings.Join(*field, ",")
+	case *map[string]int:
+		// Keys are sorted so unchanged maps are never reported as changed.
+		var pairs []string
+		for key, value := range *field {
+			pairs = append(pairs, fmt.Sprintf("%s=%d", key, value))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprintf("%v", s.value)
+	}
+}
+
+// set parses a string value into the setting's field.
+func (s *setting) set(value string) error {
+	switch field := s.value.(type) {
+	case *string:
+		*field = value
+	case *int:
+		intValue, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer, got %q", s.path, value)
+		}
+		*field = intValue
+	case *float64:
+		floatValue, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number, got %q", s.path, value)
+		}
+		*field = floatValue
+	case *[]string:
+		// "redis_stream, log" => ["redis_stream", "log"]
+		var values []string
+		for _, listValue := range strings.Split(value, ",") {
+			if listValue = strings.TrimSpace(listValue); listValue != "" {
+				values = append(values, listValue)
+			}
+		}
+		*field = values
+	case *map[string]int:
+		// "hot=5,cold=1" => {hot: 5, cold: 1}, the map is replaced as a whole.
+		values := make(map[string]int)
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("%s must be <key>=<integer>,..., got %q", s.path, value)
+			}
+			intValue, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+			if err != nil {
+				return fmt.Errorf("%s must be <key>=<integer>,..., got %q", s.path, value)
+			}
+			values[strings.TrimSpace(parts[0])] = intValue
+		}
+		*field = values
+	default:
+		return fmt.Errorf("%s has unsupported type %T", s.path, s.value)
+	}
+
+	return nil
+}
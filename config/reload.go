@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// reloadPollInterval is how often the config file is checked for changes.
+const reloadPollInterval = 5 * time.Second
+
+// noValue is shown in a diff for a setting that was added or removed.
+const noValue = "<none>"
+
+// Diff returns the settings that differ between two configurations,
+// ex: "shelves.hot.capacity: 15 => 20".
+func (a *AppConfig) Diff(other AppConfig) []string {
+	var changes []string
+
+	for _, setting := range a.settings() {
+		newValue := noValue
+		if otherSetting, ok := other.getSetting(setting.path); ok {
+			newValue = otherSetting.String()
+		}
+		if setting.String() == newValue {
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%s: %s => %s", setting.path, setting.String(), newValue))
+	}
+
+	// Settings that only exist in the other configuration ex: a new shelf.
+	for _, otherSetting := range other.settings() {
+		if _, ok := a.getSetting(otherSetting.path); ok {
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%s: %s => %s", otherSetting.path, noValue, otherSetting.String()))
+	}
+
+	return changes
+}
+
+// isReloadable returns true if a change to a setting is applied without a restart,
+// changes to any other setting are logged but ignored until restart.
+func (r *Reloader) isReloadable(change string, cfg AppConfig) bool {
+	path := strings.SplitN(change, ":", 2)[0]
+	if path == "worker_pool.max_workers" || path == "cooking.stations" || path == "webhooks.workers" {
+		return true
+	}
+
+	// Shelves can be resized but adding or removing shelves requires a restart.
+	_, isCurrentSetting := r.cfg.getSetting(path)
+	_, isNewSetting := cfg.getSetting(path)
+	// Kitchens can add or remove cooking stations the same way.
+	isResize := strings.HasSuffix(path, ".capacity") || strings.HasSuffix(path, ".cooking_stations")
+	return isResize && isCurrentSetting && isNewSetting
+}
+
+// Reloader reloads configuration when its yaml file changes
+// or the process receives SIGHUP.
+type Reloader struct {
+	args      []string
+	lock      sync.Mutex
+	cfg       AppConfig
+	modTime   time.Time
+	callbacks []func(cfg AppConfig)
+}
+
+// NewReloader returns a reloader of configuration loaded with args.
+func NewReloader(cfg AppConfig, args []string) *Reloader {
+	r := &Reloader{
+		args: args,
+		cfg:  cfg,
+	}
+	r.modTime, _ = r.getModTime()
+
+	return r
+}
+
+// OnReload registers a callback called with the new configuration after each reload.
+func (r *Reloader) OnReload(callback func(cfg AppConfig)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.callbacks = append(r.callbacks, callback)
+}
+
+// Run watches for configuration changes until the service stops.
+func (r *Reloader) Run() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	ticker := time.NewTicker(reloadPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hangup:
+			log.Print("config | received SIGHUP, reloading configuration")
+			r.Reload()
+		case <-ticker.C:
+			if r.hasFileChanged() {
+				log.Print("config | config file changed, reloading configuration")
+				r.Reload()
+			}
+		}
+	}
+}
+
+// Reload loads and validates configuration, invalid
+// configuration is logged and the current configuration is kept.
+func (r *Reloader) Reload() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.modTime, _ = r.getModTime()
+
+	cfg := AppConfig{}
+	err := cfg.Load(r.args)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		log.Printf("config | keeping current configuration, failed to reload - err: %s", err)
+		return
+	}
+
+	changes := r.cfg.Diff(cfg)
+	if len(changes) == 0 {
+		log.Print("config | reloaded configuration, nothing changed")
+		return
+	}
+
+	// Only reloadable settings are applied, on a copy so configuration
+	// handed to callbacks is never modified underneath them.
+	newCfg := r.cfg.copy()
+	var applied, ignored, failed []string
+	for _, change := range changes {
+		if !r.isReloadable(change, cfg) {
+			ignored = append(ignored, change)
+			continue
+		}
+
+		path := strings.SplitN(change, ":", 2)[0]
+		err = newCfg.setFrom(cfg, path)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s - err: %s", change, err))
+			continue
+		}
+		applied = append(applied, change)
+	}
+
+	// Applied settings must also be valid together with the settings kept
+	// until restart, ex: a shelf resized below the space it reserves.
+	err = newCfg.Validate()
+	if err != nil {
+		log.Printf("config | keeping current configuration, "+
+			"reloaded settings are not valid with settings that need a restart - err: %s", err)
+		return
+	}
+
+	for _, change := range applied {
+		log.Printf("config | applied %s", change)
+	}
+	for _, change := range ignored {
+		log.Printf("config | ignored %s, restart to apply", change)
+	}
+	for _, change := range failed {
+		log.Printf("config | failed to apply %s", change)
+	}
+	r.cfg = newCfg
+
+	for _, callback := range r.callbacks {
+		callback(r.cfg)
+	}
+}
+
+// hasFileChanged returns true if the config file was modified since the last reload.
+func (r *Reloader) hasFileChanged() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	modTime, err := r.getModTime()
+	if err != nil {
+		return false
+	}
+
+	return modTime.After(r.modTime)
+}
+
+// setFrom sets a setting to its value in other configuration.
+func (a *AppConfig) setFrom(other AppConfig, path string) error {
+	setting, ok := a.getSetting(path)
+	if !ok {
+		return fmt.Errorf("%s is not a setting of the current configuration", path)
+	}
+
+	otherSetting, ok := other.getSetting(path)
+	if !ok {
+		return fmt.Errorf("%s is not a setting of the reloaded configuration", path)
+	}
+
+	return setting.set(otherSetting.String())
+}
+
+// copy returns a copy of configuration that shares no lists or maps with the original.
+func (a *AppConfig) copy() AppConfig {
+	cfg := *a
+
+	cfg.Shelves = copyShelves(a.Shelves)
+
+	cfg.Kitchens = make([]Kitchen, len(a.Kitchens))
+	for i, kitchen := range a.Kitchens {
+		cfg.Kitchens[i] = kitchen
+		cfg.Kitchens[i].Shelves = copyShelves(kitchen.Shelves)
+	}
+
+	cfg.Drivers = make([]Driver, len(a.Drivers))
+	copy(cfg.Drivers, a.Drivers)
+
+	cfg.Cooking.PrepTimes = make(map[string]int, len(a.Cooking.PrepTimes))
+	for temp, prepTime := range a.Cooking.PrepTimes {
+		cfg.Cooking.PrepTimes[temp] = prepTime
+	}
+
+	cfg.Outbox.Sinks = make([]string, len(a.Outbox.Sinks))
+	copy(cfg.Outbox.Sinks, a.Outbox.Sinks)
+
+	return cfg
+}
+
+// copyShelves returns a copy of shelves that shares no temps with the original.
+func copyShelves(shelves []Shelf) []Shelf {
+	copiedShelves := make([]Shelf, len(shelves))
+	for i, shelf := range shelves {
+		copiedShelves[i] = shelf
+		copiedShelves[i].Temps = make([]string, len(shelf.Temps))
+		copy(copiedShelves[i].Temps, shelf.Temps)
+	}
+
+	return copiedShelves
+}
+
+// getModTime returns when the config file was last modified.
+func (r *Reloader) getModTime() (time.Time, error) {
+	fileInfo, err := os.Stat(r.cfg.FilePath())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return fileInfo.ModTime(), nil
+}
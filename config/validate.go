@@ -0,0 +1,244 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kitchen-delivery/entity"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// ValidationError holds every problem found in a configuration.
+type ValidationError struct {
+	Problems []string // ex: "shelves[0].capacity: must be greater than 0, got -1"
+}
+
+// Error returns all problems, one per line.
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf(
+		"invalid configuration, %d problem(s):\n  %s", len(v.Problems), strings.Join(v.Problems, "\n  "))
+}
+
+// Validate checks every configuration field and returns
+// a *ValidationError listing all problems found.
+func (a *AppConfig) Validate() error {
+	v := &ValidationError{}
+
+	v.requireString("service_name", a.ServiceName)
+	v.requireString("http.address", a.HTTP.Address)
+	v.requireString("grpc.address", a.GRPC.Address)
+
+	// MySQL
+	v.requireString("databases.mysql.username", a.Databases.MySQL.Username)
+	v.requireString("databases.mysql.host", a.Databases.MySQL.Host)
+	v.requireString("databases.mysql.database", a.Databases.MySQL.Database)
+	if a.Databases.MySQL.Port <= 0 || a.Databases.MySQL.Port > 65535 {
+		v.add("databases.mysql.port", "must be between 1 and 65535, got %d", a.Databases.MySQL.Port)
+	}
+
+	// Redis
+	v.requireString("databases.redis.address", a.Databases.Redis.Address)
+	v.requirePositive("databases.redis.max_active", a.Databases.Redis.MaxActive)
+	if a.Databases.Redis.MaxIdle < 0 {
+		v.add("databases.redis.max_idle", "must not be negative, got %d", a.Databases.Redis.MaxIdle)
+	}
+	if a.Databases.Redis.MaxIdle > a.Databases.Redis.MaxActive {
+		v.add("databases.redis.max_idle", "must not be greater than max_active (%d), got %d",
+			a.Databases.Redis.MaxActive, a.Databases.Redis.MaxIdle)
+	}
+	if a.Databases.Redis.IdleTimeout < 0 {
+		v.add("databases.redis.idle_timeout", "must not be negative, got %d", a.Databases.Redis.IdleTimeout)
+	}
+
+	// Pickup
+	if a.Pickup.Mean <= 0 {
+		v.add("pickup.mean", "must be greater than 0, got %v", a.Pickup.Mean)
+	}
+	if !entity.AllPickupStrategies[entity.PickupStrategy(a.Pickup.Strategy)] {
+		v.add("pickup.strategy", "must be one of [matched fifo], got %q", a.Pickup.Strategy)
+	}
+
+	// Workers
+	v.requirePositive("worker_pool.max_workers", a.WorkerPool.MaxWorkers)
+
+	// Queue
+	if !entity.AllQueueBackends[entity.QueueBackend(a.Queue.Backend)] {
+		v.add("queue.backend", "must be one of [list stream], got %q", a.Queue.Backend)
+	}
+	if a.Queue.Backend == string(entity.QueueBackendStream) {
+		v.requireString("queue.consumer_group", a.Queue.ConsumerGroup)
+		v.requirePositive("queue.max_length", a.Queue.MaxLength)
+		v.requirePositive("queue.block_timeout", a.Queue.BlockTimeout)
+		v.requirePositive("queue.claim_timeout", a.Queue.ClaimTimeout)
+	}
+
+	// Leader
+	v.requireString("leader.key", a.Leader.Key)
+	v.requirePositive("leader.lease_timeout", a.Leader.LeaseTimeout)
+
+	// Supervisor
+	v.requirePositive("supervisor.initial_backoff", a.Supervisor.InitialBackoff)
+	v.requirePositive("supervisor.crash_loop_threshold", a.Supervisor.CrashLoopThreshold)
+	if a.Supervisor.MaxBackoff < a.Supervisor.InitialBackoff {
+		v.add("supervisor.max_backoff", "must not be less than initial_backoff (%d), got %d",
+			a.Supervisor.InitialBackoff, a.Supervisor.MaxBackoff)
+	}
+
+	// Cooking
+	v.requirePositive("cooking.stations", a.Cooking.Stations)
+	v.requirePositive("cooking.requeue_after", a.Cooking.RequeueAfter)
+	for temp, prepTime := range a.Cooking.PrepTimes {
+		if prepTime < 0 {
+			v.add("cooking.prep_times."+temp, "must not be negative, got %d", prepTime)
+		}
+		// Orders still cooking would be cooked twice.
+		if a.Cooking.RequeueAfter > 0 && prepTime >= a.Cooking.RequeueAfter {
+			v.add("cooking.requeue_after", "must be greater than cooking.prep_times.%s (%d), got %d",
+				temp, prepTime, a.Cooking.RequeueAfter)
+		}
+	}
+
+	// Scheduling
+	if a.Scheduling.LeadTime < 0 {
+		v.add("scheduling.lead_time", "must not be negative, got %d", a.Scheduling.LeadTime)
+	}
+
+	// Events
+	if a.Events.BufferSize < 0 {
+		v.add("events.buffer_size", "must not be negative, got %d", a.Events.BufferSize)
+	}
+
+	// Webhooks
+	v.requirePositive("webhooks.workers", a.Webhooks.Workers)
+	v.requirePositive("webhooks.max_attempts", a.Webhooks.MaxAttempts)
+	v.requirePositive("webhooks.initial_backoff", a.Webhooks.InitialBackoff)
+	v.requirePositive("webhooks.timeout", a.Webhooks.Timeout)
+	if a.Webhooks.MaxBackoff < a.Webhooks.InitialBackoff {
+		v.add("webhooks.max_backoff", "must not be less than initial_backoff (%d), got %d",
+			a.Webhooks.InitialBackoff, a.Webhooks.MaxBackoff)
+	}
+
+	// Outbox
+	outboxSinks := make(map[string]bool)
+	for i, sink := range a.Outbox.Sinks {
+		path := fmt.Sprintf("outbox.sinks[%d]", i)
+		if !entity.AllOutboxSinkTypes[entity.OutboxSinkType(sink)] {
+			v.add(path, "must be one of [redis_stream webhook log], got %q", sink)
+		}
+		if outboxSinks[sink] {
+			v.add(path, "sink %q is defined more than once", sink)
+		}
+		outboxSinks[sink] = true
+	}
+	v.requirePositive("outbox.batch_size", a.Outbox.BatchSize)
+	v.requirePositive("outbox.max_attempts", a.Outbox.MaxAttempts)
+	if outboxSinks[string(entity.OutboxSinkRedisStream)] {
+		v.requireString("outbox.stream", a.Outbox.Stream)
+		v.requirePositive("outbox.stream_max_length", a.Outbox.StreamMaxLength)
+	}
+
+	// Shelves
+	if len(a.Shelves) == 0 {
+		v.add("shelves", "at least one shelf is required")
+	}
+	v.validateShelves("shelves", a.Shelves)
+
+	// Kitchens
+	kitchenNames := make(map[string]bool)
+	kitchenUUIDs := make(map[string]bool)
+	for i, kitchen := range a.Kitchens {
+		path := fmt.Sprintf("kitchens[%d]", i)
+
+		v.requireString(path+".name", kitchen.Name)
+		if kitchenNames[kitchen.Name] {
+			v.add(path+".name", "kitchen %q is defined more than once", kitchen.Name)
+		}
+		kitchenNames[kitchen.Name] = true
+
+		if _, err := guuid.FromString(kitchen.UUID); err != nil {
+			v.add(path+".uuid", "must be a valid uuid, got %q", kitchen.UUID)
+		}
+		if kitchenUUIDs[kitchen.UUID] {
+			v.add(path+".uuid", "kitchen uuid %q is used more than once", kitchen.UUID)
+		}
+		kitchenUUIDs[kitchen.UUID] = true
+
+		v.validateShelves(path+".shelves", kitchen.Shelves)
+		if kitchen.CookingStations < 0 {
+			v.add(path+".cooking_stations", "must not be negative, got %d", kitchen.CookingStations)
+		}
+	}
+
+	// Drivers
+	driverNames := make(map[string]bool)
+	driverUUIDs := make(map[string]bool)
+	for i, driver := range a.Drivers {
+		path := fmt.Sprintf("drivers[%d]", i)
+
+		v.requireString(path+".name", driver.Name)
+		if driverNames[driver.Name] {
+			v.add(path+".name", "driver %q is defined more than once", driver.Name)
+		}
+		driverNames[driver.Name] = true
+
+		if _, err := guuid.FromString(driver.UUID); err != nil {
+			v.add(path+".uuid", "must be a valid uuid, got %q", driver.UUID)
+		}
+		if driverUUIDs[driver.UUID] {
+			v.add(path+".uuid", "driver uuid %q is used more than once", driver.UUID)
+		}
+		driverUUIDs[driver.UUID] = true
+
+		v.requireString(path+".token", driver.Token)
+	}
+
+	if len(v.Problems) > 0 {
+		return v
+	}
+
+	return nil
+}
+
+// validateShelves checks shelf definitions at a yaml path.
+func (v *ValidationError) validateShelves(path string, shelves []Shelf) {
+	shelfNames := make(map[string]bool)
+	for i, shelf := range shelves {
+		shelfPath := fmt.Sprintf("%s[%d]", path, i)
+
+		v.requireString(shelfPath+".name", shelf.Name)
+		if shelfNames[shelf.Name] {
+			v.add(shelfPath+".name", "shelf %q is defined more than once", shelf.Name)
+		}
+		shelfNames[shelf.Name] = true
+
+		v.requirePositive(shelfPath+".capacity", shelf.Capacity)
+		if len(shelf.Temps) == 0 {
+			v.add(shelfPath+".temps", "at least one temp is required")
+		}
+		// An invalid capacity is already reported on its own.
+		if shelf.Reserved < 0 || (shelf.Capacity > 0 && shelf.Reserved > shelf.Capacity) {
+			v.add(shelfPath+".reserved", "must be between 0 and capacity (%d), got %d", shelf.Capacity, shelf.Reserved)
+		}
+		if shelf.DecayModifier < 0 {
+			v.add(shelfPath+".decay_modifier", "must not be negative, got %v", shelf.DecayModifier)
+		}
+	}
+}
+
+// add records a problem with the field at a yaml path.
+func (v *ValidationError) add(path string, format string, args ...interface{}) {
+	v.Problems = append(v.Problems, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (v *ValidationError) requireString(path string, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(path, "is required")
+	}
+}
+
+func (v *ValidationError) requirePositive(path string, value int) {
+	if value <= 0 {
+		v.add(path, "must be greater than 0, got %d", value)
+	}
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// Courier is a driver dispatched to pick up an order.
+type Courier struct {
+	UUID        guuid.UUID
+	KitchenUUID guuid.UUID // kitchen the courier arrived at, optional for matched pickups
+	OrderUUID   guuid.UUID // order the courier was dispatched for
+	ArrivedAt   time.Time  // time the courier arrived at the kitchen
+}
+
+// String returns a prettified string representation of a courier.
+func (c *Courier) String() string {
+	courierString := fmt.Sprintf("Courier: %s, OrderUUID: %s", c.UUID, c.OrderUUID)
+	return courierString
+}
+
+// PickupStrategy is courier matching strategy enum.
+type PickupStrategy string
+
+var (
+	// PickupStrategyMatched is for when a courier only picks up
+	// the order they were dispatched for.
+	PickupStrategyMatched = PickupStrategy("matched")
+	// PickupStrategyFIFO is for when a courier picks up the next
+	// available order on the shelves.
+	PickupStrategyFIFO = PickupStrategy("fifo")
+)
+
+// AllPickupStrategies holds all pickup strategies
+// and is used to validate configuration.
+// We use a hashmap for O(1) look up.
+var AllPickupStrategies = map[PickupStrategy]bool{
+	PickupStrategyMatched: true,
+	PickupStrategyFIFO:    true,
+}
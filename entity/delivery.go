@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// Delivery is an order that was dropped off to a customer.
+type Delivery struct {
+	UUID            guuid.UUID
+	OrderUUID       guuid.UUID
+	ShelfOrderUUID  guuid.UUID
+	OrderAge        time.Duration // time since the order was placed on a shelf
+	Value           float64       // value of the order at delivery time
+	NormalizedValue float64       // value relative to shelf life, ex: 1.0 is fresh
+	DeliveredAt     time.Time
+}
+
+// IsAcceptable returns true if the order still had value when it was delivered.
+func (d *Delivery) IsAcceptable() bool {
+	return d.Value > 0
+}
+
+// String returns a prettified string representation of a delivery.
+func (d *Delivery) String() string {
+	deliveryString := fmt.Sprintf(
+		"OrderUUID: %s, Value: %.2f, NormalizedValue: %.2f", d.OrderUUID, d.Value, d.NormalizedValue)
+	return deliveryString
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// Driver is a driver allowed to connect to the driver channel.
+type Driver struct {
+	UUID  guuid.UUID
+	Name  string // ex: "alice"
+	Token string // secret the driver app authenticates with
+}
+
+// Authenticate returns true if a token is the driver's token.
+func (d *Driver) Authenticate(token string) bool {
+	// Tokens are compared in constant time so they cannot be guessed from response times.
+	return d.Token != "" && subtle.ConstantTimeCompare([]byte(d.Token), []byte(token)) == 1
+}
+
+// String returns a prettified string representation of a driver, without its token.
+func (d *Driver) String() string {
+	driverString := fmt.Sprintf("Driver: %s, Name: %s", d.UUID, d.Name)
+	return driverString
+}
@@ -0,0 +1,18 @@
+package endpoint
+
+// DriverMessageJSON holds a message pushed to a driver app on the driver channel.
+type DriverMessageJSON struct {
+	Type      string           `json:"type"` // enum: ['connected', 'order', 'not_ready', 'order.wasted', 'order.cancelled', 'order.evicted', 'error']
+	OrderUUID string           `json:"orderUUID,omitempty"`
+	Order     *DriverOrderJSON `json:"order,omitempty"` // only set on 'order' messages
+	Reason    string           `json:"reason,omitempty"`
+}
+
+// DriverOrderJSON holds the details of an order handed to a driver.
+type DriverOrderJSON struct {
+	UUID        string `json:"uuid"`
+	KitchenUUID string `json:"kitchenUUID"`
+	Name        string `json:"name"`
+	Temp        string `json:"temp"`
+	Priority    string `json:"priority"`
+}
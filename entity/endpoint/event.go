@@ -0,0 +1,16 @@
+package endpoint
+
+import "time"
+
+// EventJSON holds an event for event stream responses.
+type EventJSON struct {
+	ID          uint64    `json:"id"`
+	Type        string    `json:"type"`
+	KitchenUUID string    `json:"kitchenUUID"`
+	OrderUUID   string    `json:"orderUUID"`
+	ShelfType   string    `json:"shelfType,omitempty"`
+	FromStatus  string    `json:"fromStatus,omitempty"`
+	ToStatus    string    `json:"toStatus"`
+	Reason      string    `json:"reason"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
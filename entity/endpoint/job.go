@@ -0,0 +1,18 @@
+package endpoint
+
+import "time"
+
+// JobStatusJSON holds how a scheduled job has been running for job responses.
+type JobStatusJSON struct {
+	Name         string     `json:"name"`
+	Schedule     string     `json:"schedule"`
+	LeaderOnly   bool       `json:"leaderOnly"`
+	Running      bool       `json:"running"`
+	Runs         int        `json:"runs"`
+	Failures     int        `json:"failures"`
+	Skipped      int        `json:"skipped"`
+	LastRunAt    *time.Time `json:"lastRunAt,omitempty"`
+	LastDuration string     `json:"lastDuration,omitempty"` // ex: "1.5s"
+	LastError    string     `json:"lastError,omitempty"`
+	NextRunAt    *time.Time `json:"nextRunAt,omitempty"`
+}
@@ -0,0 +1,26 @@
+package endpoint
+
+import "time"
+
+// MenuItemRequest holds an HTTP create or update menu item request
+// with url encoded values.
+type MenuItemRequest struct {
+	UUID      string `json:"uuid"` // optional and used for idempotency on creation endpoint
+	Name      string `json:"name"`
+	Temp      string `json:"temp"`
+	ShelfLife string `json:"shelfLife"`
+	DecayRate string `json:"decayRate"`
+	PrepTime  string `json:"prepTime"` // optional seconds to cook, defaults to the kitchen's prep time
+}
+
+// MenuItemJSON holds a menu item for menu responses.
+type MenuItemJSON struct {
+	UUID      string    `json:"uuid"`
+	Name      string    `json:"name"`
+	Temp      string    `json:"temp"`
+	ShelfLife int       `json:"shelfLife"`
+	DecayRate float64   `json:"decayRate"`
+	PrepTime  int       `json:"prepTime"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
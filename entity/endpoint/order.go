@@ -1,13 +1,20 @@
 package endpoint
 
+import "time"
+
 // CreateOrderRequest holds an HTTP create order request
 // with url encoded values.
 type CreateOrderRequest struct {
-	UUID      string `json:"uuid"` // optional and used for idempotency on creation endpoint
-	Name      string `json:"name"`
-	Temp      string `json:"temp"`
-	ShelfLife string `json:"shelfLife"`
-	DecayRate string `json:"decayRate"`
+	UUID         string `json:"uuid"`         // optional and used for idempotency on creation endpoint
+	KitchenUUID  string `json:"kitchenUUID"`  // optional and defaults to the default kitchen
+	MenuItemUUID string `json:"menuItemUUID"` // optional, fields not passed in are taken from the menu item
+	Name         string `json:"name"`
+	Temp         string `json:"temp"`
+	ShelfLife    string `json:"shelfLife"`
+	DecayRate    string `json:"decayRate"`
+	PrepTime     string `json:"prepTime"` // optional seconds to cook, defaults to the kitchen's prep time
+	Priority     string `json:"priority"` // optional, enum: ['standard', 'express', 'vip'], defaults to standard
+	ReadyAt      string `json:"readyAt"`  // optional RFC 3339 time the customer wants the order, ex: "2020-06-01T19:00:00Z"
 }
 
 // OrderJSON holds the order json from input.json.
@@ -16,4 +23,35 @@ type OrderJSON struct {
 	Temp      string  `json:"temp"`
 	ShelfLife int     `json:"shelfLife"`
 	DecayRate float64 `json:"decayRate"`
+	PrepTime  int     `json:"prepTime"` // optional seconds to cook
+	Priority  string  `json:"priority"` // optional, defaults to standard
+}
+
+// ScheduledOrderJSON holds an order held off of the order queue
+// for scheduled order responses.
+type ScheduledOrderJSON struct {
+	OrderUUID   string    `json:"orderUUID"`
+	KitchenUUID string    `json:"kitchenUUID"`
+	Status      string    `json:"status"`
+	ReadyAt     time.Time `json:"readyAt"`
+	ReleaseAt   time.Time `json:"releaseAt"`
+}
+
+// OrderEventJSON holds an order status transition
+// for order history responses.
+type OrderEventJSON struct {
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// DeliveryJSON holds the condition of an order at delivery time.
+type DeliveryJSON struct {
+	OrderUUID       string    `json:"orderUUID"`
+	OrderAgeMs      int64     `json:"orderAgeMs"`
+	Value           float64   `json:"value"`
+	NormalizedValue float64   `json:"normalizedValue"`
+	IsAcceptable    bool      `json:"isAcceptable"`
+	DeliveredAt     time.Time `json:"deliveredAt"`
 }
@@ -0,0 +1,30 @@
+package endpoint
+
+import "time"
+
+// CreateParentOrderRequest holds an HTTP create parent order request with a json body.
+type CreateParentOrderRequest struct {
+	UUID        string               `json:"uuid"`        // optional and used for idempotency on creation endpoint
+	KitchenUUID string               `json:"kitchenUUID"` // optional and defaults to the default kitchen
+	Priority    string               `json:"priority"`    // optional and defaults to standard
+	Items       []CreateOrderRequest `json:"items"`       // line items, each either from a menu item or with all fields
+}
+
+// ParentOrderJSON holds a parent order and the status of its items
+// for parent order responses.
+type ParentOrderJSON struct {
+	UUID        string                `json:"uuid"`
+	KitchenUUID string                `json:"kitchenUUID"`
+	Status      string                `json:"status"`
+	Items       []ParentOrderItemJSON `json:"items"`
+	WastedItems []string              `json:"wastedItems"` // uuids of items that were wasted
+	CreatedAt   time.Time             `json:"createdAt"`
+}
+
+// ParentOrderItemJSON holds a line item of a parent order.
+type ParentOrderItemJSON struct {
+	UUID   string `json:"uuid"`
+	Name   string `json:"name"`
+	Temp   string `json:"temp"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,19 @@
+package endpoint
+
+// PickupOrderRequest holds an HTTP pickup order request
+// with url encoded values.
+type PickupOrderRequest struct {
+	CourierUUID string `json:"courierUUID"` // optional and generated if not passed
+	KitchenUUID string `json:"kitchenUUID"` // kitchen the courier arrived at
+	OrderUUID   string `json:"orderUUID"`   // order the courier was dispatched for
+	ArrivedAt   string `json:"arrivedAt"`   // optional unix timestamp in milliseconds
+}
+
+// PickupStatsJSON holds pickup wait times of a strategy
+// for simulation reports.
+type PickupStatsJSON struct {
+	Strategy         string `json:"strategy"`
+	NumOfPickups     int    `json:"numOfPickups"`
+	AvgFoodWaitMs    int64  `json:"avgFoodWaitMs"`
+	AvgCourierWaitMs int64  `json:"avgCourierWaitMs"`
+}
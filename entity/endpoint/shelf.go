@@ -0,0 +1,29 @@
+package endpoint
+
+import "time"
+
+// ShelfJSON holds a shelf and the orders on it for shelf responses.
+type ShelfJSON struct {
+	Type      string           `json:"type"`
+	Temps     []string         `json:"temps"`
+	Overflow  bool             `json:"overflow"`
+	Capacity  int              `json:"capacity"`
+	Reserved  int              `json:"reserved"`  // space kept for express and vip orders
+	Occupancy int              `json:"occupancy"` // orders ready for pickup on the shelf
+	Available int              `json:"available"`
+	Orders    []ShelfOrderJSON `json:"orders"`
+	TakenAt   time.Time        `json:"takenAt"`
+}
+
+// ShelfOrderJSON holds an order on a shelf for shelf responses.
+type ShelfOrderJSON struct {
+	OrderUUID       string    `json:"orderUUID"`
+	Name            string    `json:"name"`
+	Temp            string    `json:"temp"`
+	Priority        string    `json:"priority"`
+	Status          string    `json:"status"`
+	PlacedAt        time.Time `json:"placedAt"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	Value           float64   `json:"value"`
+	NormalizedValue float64   `json:"normalizedValue"`
+}
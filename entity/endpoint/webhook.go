@@ -0,0 +1,41 @@
+package endpoint
+
+import "time"
+
+// WebhookSubscriptionRequest holds an HTTP create webhook subscription request
+// with url encoded values.
+type WebhookSubscriptionRequest struct {
+	UUID        string `json:"uuid"` // optional and used for idempotency on creation endpoint
+	URL         string `json:"url"`
+	Secret      string `json:"secret"`
+	KitchenUUID string `json:"kitchenUUID"` // optional, every kitchen if empty
+	EventTypes  string `json:"eventTypes"`  // optional comma separated event types ex: "order.shelved,order.wasted"
+}
+
+// WebhookSubscriptionJSON holds a webhook subscription for webhook responses,
+// the secret is never sent back.
+type WebhookSubscriptionJSON struct {
+	UUID        string    `json:"uuid"`
+	URL         string    `json:"url"`
+	KitchenUUID string    `json:"kitchenUUID,omitempty"`
+	EventTypes  []string  `json:"eventTypes"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// WebhookDeliveryJSON holds a webhook delivery for delivery log responses.
+type WebhookDeliveryJSON struct {
+	UUID             string     `json:"uuid"`
+	SubscriptionUUID string     `json:"subscriptionUUID"`
+	EventType        string     `json:"eventType"`
+	OrderUUID        string     `json:"orderUUID"`
+	Payload          string     `json:"payload"`
+	Status           string     `json:"status"`
+	Attempts         int        `json:"attempts"`
+	NextAttemptAt    *time.Time `json:"nextAttemptAt,omitempty"` // only set on pending deliveries
+	LastStatusCode   int        `json:"lastStatusCode,omitempty"`
+	LastError        string     `json:"lastError,omitempty"`
+	LastAttemptAt    *time.Time `json:"lastAttemptAt,omitempty"`
+	DeliveredAt      *time.Time `json:"deliveredAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
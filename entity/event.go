@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// Event is something that happened to an order at a kitchen,
+// published on the event bus to every subscriber of the kitchen or order.
+type Event struct {
+	ID          uint64 // increases with every event published, used to resume a stream
+	Type        EventType
+	KitchenUUID guuid.UUID
+	OrderUUID   guuid.UUID
+	ShelfType   ShelfType // shelf the order is on or was taken off of, empty if never shelved
+	FromStatus  OrderStatus
+	ToStatus    OrderStatus
+	Reason      string
+	OccurredAt  time.Time
+}
+
+// NewEvent returns the event of an order event at a kitchen,
+// the event bus assigns its ID once it is published.
+func NewEvent(kitchenUUID guuid.UUID, shelfType ShelfType, orderEvent OrderEvent) (*Event, error) {
+	eventType, ok := eventTypesByStatus[orderEvent.ToStatus]
+	if !ok {
+		return nil, fmt.Errorf("order status %s has no event type", orderEvent.ToStatus)
+	}
+
+	return &Event{
+		Type:        eventType,
+		KitchenUUID: kitchenUUID,
+		OrderUUID:   orderEvent.OrderUUID,
+		ShelfType:   shelfType,
+		FromStatus:  orderEvent.FromStatus,
+		ToStatus:    orderEvent.ToStatus,
+		Reason:      orderEvent.Reason,
+		OccurredAt:  orderEvent.CreatedAt,
+	}, nil
+}
+
+// String returns a prettified string representation of an event.
+func (e *Event) String() string {
+	eventString := fmt.Sprintf(
+		"ID: %d, Type: %s, KitchenUUID: %s, OrderUUID: %s", e.ID, e.Type, e.KitchenUUID, e.OrderUUID)
+	return eventString
+}
+
+// EventType is event type enum.
+type EventType string
+
+var (
+	// EventTypeOrderCreated is for when an order is received.
+	EventTypeOrderCreated = EventType("order.created")
+	// EventTypeOrderShelved is for when an order is placed on a shelf.
+	EventTypeOrderShelved = EventType("order.shelved")
+	// EventTypeOrderPickedUp is for when an order is taken off of its shelf by a driver.
+	EventTypeOrderPickedUp = EventType("order.picked_up")
+	// EventTypeOrderWasted is for when an order is dropped as waste after TTL has expired.
+	EventTypeOrderWasted = EventType("order.wasted")
+	// EventTypeOrderCancelled is for when a customer cancels an order.
+	EventTypeOrderCancelled = EventType("order.cancelled")
+	// EventTypeOrderEvicted is for when an order is dropped by the kitchen to make room.
+	EventTypeOrderEvicted = EventType("order.evicted")
+)
+
+// AllEventTypes holds every event type.
+var AllEventTypes = map[EventType]bool{
+	EventTypeOrderCreated:   true,
+	EventTypeOrderShelved:   true,
+	EventTypeOrderPickedUp:  true,
+	EventTypeOrderWasted:    true,
+	EventTypeOrderCancelled: true,
+	EventTypeOrderEvicted:   true,
+}
+
+// eventTypesByStatus holds the event type published when an order reaches a status.
+// Orders are never moved between shelves once placed, orders that do not fit
+// on any shelf are evicted instead.
+var eventTypesByStatus = map[OrderStatus]EventType{
+	OrderStatusReceived:       EventTypeOrderCreated,
+	OrderStatusReadyForPickup: EventTypeOrderShelved,
+	OrderStatusPickedUp:       EventTypeOrderPickedUp,
+	OrderStatusWasted:         EventTypeOrderWasted,
+	OrderStatusCancelled:      EventTypeOrderCancelled,
+	OrderStatusEvicted:        EventTypeOrderEvicted,
+}
+
+// EventFilter narrows the events a subscriber receives,
+// empty fields match every event.
+type EventFilter struct {
+	KitchenUUID guuid.UUID
+	OrderUUID   guuid.UUID
+}
+
+// Matches returns true if an event passes the filter.
+func (f *EventFilter) Matches(event Event) bool {
+	nullUUID := guuid.NullUUID{}
+	doesKitchenMatch := f.KitchenUUID == nullUUID.UUID || f.KitchenUUID == event.KitchenUUID
+	doesOrderMatch := f.OrderUUID == nullUUID.UUID || f.OrderUUID == event.OrderUUID
+	return doesKitchenMatch && doesOrderMatch
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// JobStatus holds how a scheduled job has been running.
+type JobStatus struct {
+	Name         string
+	Schedule     string // ex: "every 5s" or "*/5 * * * *"
+	LeaderOnly   bool   // only runs on the instance elected leader
+	Running      bool
+	Runs         int       // runs started
+	Failures     int       // runs that returned an error, panicked or timed out
+	Skipped      int       // runs skipped as the previous run was still going
+	LastRunAt    time.Time // zero until the job first runs
+	LastDuration time.Duration
+	LastError    string // empty if the last run succeeded
+	NextRunAt    time.Time
+}
+
+// String returns a prettified string representation of a job status.
+func (j *JobStatus) String() string {
+	return fmt.Sprintf(
+		"Name: %s, Schedule: %s, Running: %t, Runs: %d, Failures: %d, LastError: %s",
+		j.Name, j.Schedule, j.Running, j.Runs, j.Failures, j.LastError)
+}
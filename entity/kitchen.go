@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// DefaultKitchenUUID is the kitchen of orders that do not name a kitchen
+// and of orders placed before kitchens were introduced.
+var DefaultKitchenUUID = guuid.FromStringOrNil("00000000-0000-0000-0000-000000000001")
+
+// Kitchen is a kitchen location with its own shelves and order queue.
+type Kitchen struct {
+	UUID            guuid.UUID
+	Name            string // ex: "downtown"
+	ShelfCatalog    *ShelfCatalog
+	CookingStations int               // orders cooked at once
+	PrepTimes       map[OrderTemp]int // seconds to cook an order per temp, orders of other temps are not cooked
+}
+
+// GetPrepTime returns how long an order is cooked before it is placed on a shelf.
+func (k *Kitchen) GetPrepTime(order Order) time.Duration {
+	prepTime := order.PrepTime
+	if prepTime == 0 {
+		prepTime = k.PrepTimes[order.Temp]
+	}
+
+	return time.Second * time.Duration(prepTime)
+}
+
+// String returns a prettified string representation of a kitchen.
+func (k *Kitchen) String() string {
+	kitchenString := fmt.Sprintf("Kitchen: %s, Name: %s", k.UUID, k.Name)
+	return kitchenString
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// MenuItemNamespace namespaces menu item uuids generated from item names
+// so seeding the menu catalog more than once creates each item once.
+var MenuItemNamespace = guuid.FromStringOrNil("6f0b3c52-1d4e-4c8a-9e55-2a7d8f0c1b93")
+
+// MenuItem is an item on the menu that orders are created from.
+type MenuItem struct {
+	UUID      guuid.UUID
+	Name      string    // ex: "Cheeze Pizza"
+	Temp      OrderTemp // temperature, any temp accepted by the shelf catalog ex: 'hot'
+	ShelfLife int       // shelf life in seconds
+	DecayRate float64   // decay rate ex: 0.45
+	PrepTime  int       // seconds to cook, 0 uses the kitchen's prep time for the item's temp
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewMenuItemUUID returns the uuid of a menu item seeded by name.
+func NewMenuItemUUID(name string) guuid.UUID {
+	return guuid.NewV5(MenuItemNamespace, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Validate verifies that a menu item is valid.
+func (m *MenuItem) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return errors.Wrap(exception.ErrInvalidInput, "name is required")
+	}
+
+	if !IsValidOrderTemp(m.Temp) {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "temp value is invalid, temp: %s", m.Temp)
+	}
+
+	if m.ShelfLife <= 0 {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "shelf life must be greater than 0, shelf life: %d", m.ShelfLife)
+	}
+
+	if m.DecayRate < 0 {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "decay rate must not be negative, decay rate: %f", m.DecayRate)
+	}
+
+	if m.PrepTime < 0 {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "prep time must not be negative, prep time: %d", m.PrepTime)
+	}
+
+	return nil
+}
+
+// String returns a prettified string representation of a menu item.
+func (m *MenuItem) String() string {
+	menuItemString := fmt.Sprintf(
+		"MenuItem: %s, Name: %s, Temp: %s, ShelfLife: %d, DecayRate: %.2f",
+		m.UUID, m.Name, m.Temp, m.ShelfLife, m.DecayRate)
+	return menuItemString
+}
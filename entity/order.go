@@ -13,15 +13,21 @@ import (
 
 // Order is a kitchen order from a customer.
 type Order struct {
-	UUID      guuid.UUID
-	Name      string    // ex: "Cheeze Pizza"
-	Temp      OrderTemp // temperature, enum: ['hot', 'cold', 'frozen']
-	ShelfLife int       // shelf life in seconds
-	DecayRate float64   // decay rate ex: 0.45
-	CreatedAt time.Time // no updated at b/c this is an immutable table
+	UUID            guuid.UUID
+	KitchenUUID     guuid.UUID    // kitchen the order was placed at
+	MenuItemUUID    guuid.UUID    // menu item the order was created from, optional
+	ParentOrderUUID guuid.UUID    // parent order the order is a line item of, optional
+	Name            string        // ex: "Cheeze Pizza"
+	Temp            OrderTemp     // temperature, any temp accepted by the shelf catalog ex: 'hot'
+	Priority        OrderPriority // empty for standard orders
+	ShelfLife       int           // shelf life in seconds
+	DecayRate       float64       // decay rate ex: 0.45
+	PrepTime        int           // seconds to cook, 0 uses the kitchen's prep time for the order's temp
+	ReadyAt         time.Time     // when the customer wants the order, zero for orders wanted as soon as possible
+	CreatedAt       time.Time     // no updated at b/c this is an immutable table
 }
 
-// OrderTemp is order temperature enum.
+// OrderTemp is order temperature, valid temperatures come from the shelf catalog.
 type OrderTemp string
 
 var (
@@ -33,55 +39,133 @@ var (
 	OrderTempFrozen = OrderTemp("frozen")
 )
 
-// AllOrderTemp holds all order temperatures
+// allOrderTemp holds all order temperatures
 // and is used for validation prior to insertion
 // and validation after order retrieval.
-var AllOrderTemp = map[OrderTemp]bool{
+// It is replaced by the temperatures of the shelf catalogs in use, see IsValidOrderTemp.
+var allOrderTemp = map[OrderTemp]bool{
 	OrderTempHot:    true,
 	OrderTempCold:   true,
 	OrderTempFrozen: true,
 }
 
+// OrderPriority is order priority enum.
+type OrderPriority string
+
+var (
+	// OrderPriorityStandard is for orders with no special handling.
+	OrderPriorityStandard = OrderPriority("standard")
+	// OrderPriorityExpress is for orders worked on before standard orders.
+	OrderPriorityExpress = OrderPriority("express")
+	// OrderPriorityVIP is for orders worked on before every other order.
+	OrderPriorityVIP = OrderPriority("vip")
+)
+
+// AllOrderPriorities holds all order priorities
+// and is used for validation prior to insertion
+// and validation after order retrieval.
+var AllOrderPriorities = map[OrderPriority]bool{
+	OrderPriorityStandard: true,
+	OrderPriorityExpress:  true,
+	OrderPriorityVIP:      true,
+}
+
+// OrderPrioritiesByRank holds all order priorities, highest priority first.
+var OrderPrioritiesByRank = []OrderPriority{
+	OrderPriorityVIP,
+	OrderPriorityExpress,
+	OrderPriorityStandard,
+}
+
+// orderPriorityRank ranks order priorities, higher ranks are worked on first.
+var orderPriorityRank = map[OrderPriority]int{
+	OrderPriorityStandard: 0,
+	OrderPriorityExpress:  1,
+	OrderPriorityVIP:      2,
+}
+
+// IsHigherThan returns true if orders of this priority are worked on before orders of the other priority.
+func (p OrderPriority) IsHigherThan(other OrderPriority) bool {
+	return orderPriorityRank[p] > orderPriorityRank[other]
+}
+
 // Validate verifies that an order is valid.
 func (o *Order) Validate() error {
-	_, ok := AllOrderTemp[o.Temp]
-	if !ok {
+	if !IsValidOrderTemp(o.Temp) {
 		return errors.Wrapf(
 			exception.ErrInvalidInput, "temp value is invalid, temp: %s", o.Temp)
 	}
 
+	if o.Priority != "" && !AllOrderPriorities[o.Priority] {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "priority value is invalid, priority: %s", o.Priority)
+	}
+
+	if o.PrepTime < 0 {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "prep time must not be negative, prep time: %d", o.PrepTime)
+	}
+
 	return nil
 }
 
 // String returns a prettified string representation of an order.
 func (o *Order) String() string {
-	orderString := fmt.Sprintf("Name: %s, Temp: %s", o.Name, o.Temp)
+	orderString := fmt.Sprintf("Name: %s, Temp: %s, Priority: %s", o.Name, o.Temp, o.Priority)
 	return orderString
 }
 
-// GetShelfType returns shelf name based on order temp.
-func (o *Order) GetShelfType() ShelfType {
-	switch o.Temp {
-	case OrderTempHot:
-		return HotShelf
-	case OrderTempCold:
-		return ColdShelf
-	case OrderTempFrozen:
-		return FrozenShelf
-	default:
-		return OverflowShelf
+// IsScheduled returns true if the customer wants the order at a later time.
+func (o *Order) IsScheduled() bool {
+	return !o.ReadyAt.IsZero()
+}
+
+// GetValue returns the value of the order after it has aged.
+// An order is waste once its value reaches zero.
+func (o *Order) GetValue(orderAge time.Duration) float64 {
+	return o.GetValueOnShelf(orderAge, 1.0)
+}
+
+// GetValueOnShelf returns the value of the order after it has aged
+// on a shelf that multiplies its decay rate by decayModifier.
+func (o *Order) GetValueOnShelf(orderAge time.Duration, decayModifier float64) float64 {
+	// value = (shelfLife - orderAge) - (decayRate * decayModifier * orderAge)
+	orderAgeSeconds := orderAge.Seconds()
+	value := (float64(o.ShelfLife) - orderAgeSeconds) - (o.DecayRate * decayModifier * orderAgeSeconds)
+	return value
+}
+
+// GetNormalizedValue returns the value of the order after it has aged
+// relative to its shelf life, ex: 1.0 is fresh and 0.0 is waste.
+func (o *Order) GetNormalizedValue(orderAge time.Duration) float64 {
+	return o.GetNormalizedValueOnShelf(orderAge, 1.0)
+}
+
+// GetNormalizedValueOnShelf returns the value of the order after it has aged on a shelf
+// that multiplies its decay rate by decayModifier relative to its shelf life.
+func (o *Order) GetNormalizedValueOnShelf(orderAge time.Duration, decayModifier float64) float64 {
+	if o.ShelfLife == 0 {
+		return 0
 	}
+
+	return o.GetValueOnShelf(orderAge, decayModifier) / float64(o.ShelfLife)
 }
 
 // GetTTL returns the ttl for the order.
 func (o *Order) GetTTL() int {
+	return o.GetTTLOnShelf(1.0)
+}
+
+// GetTTLOnShelf returns the ttl for the order on a shelf
+// that multiplies its decay rate by decayModifier.
+func (o *Order) GetTTLOnShelf(decayModifier float64) int {
 	// Calculate time to live in seconds based on formula.
 	// Remember an order is waste after the "value" becomes zero.
 	// This leads the formula to be reduced to:
-	// => orderAge = shelfLife / (1 + decayRate)
+	// => orderAge = shelfLife / (1 + decayRate * decayModifier)
 	// We're given shelfLife and decayRate so we can solve for
 	// how old an order can get before we consider it as waste.
-	expirationTime := float64(o.ShelfLife) / (1.0 + o.DecayRate)
+	expirationTime := float64(o.ShelfLife) / (1.0 + o.DecayRate*decayModifier)
 	ttl := int(math.Floor(expirationTime))
 	return ttl
 }
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// OrderEvent is a transition of an order from one status to another.
+type OrderEvent struct {
+	UUID       guuid.UUID
+	OrderUUID  guuid.UUID
+	FromStatus OrderStatus // empty for the first event of an order
+	ToStatus   OrderStatus
+	Reason     string
+	CreatedAt  time.Time
+}
+
+// orderStatusTransitions is the order state machine.
+// It maps a status to the statuses an order can move to next.
+// Transitions only move forward so an order enters each status at most once.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatus(""):           {OrderStatusReceived},
+	OrderStatusReceived:       {OrderStatusScheduled, OrderStatusQueued, OrderStatusCancelled},
+	OrderStatusScheduled:      {OrderStatusQueued, OrderStatusCancelled},
+	OrderStatusQueued:         {OrderStatusCooking, OrderStatusReadyForPickup, OrderStatusCancelled, OrderStatusEvicted},
+	OrderStatusCooking:        {OrderStatusReadyForPickup, OrderStatusCancelled, OrderStatusEvicted},
+	OrderStatusReadyForPickup: {OrderStatusPickedUp, OrderStatusWasted, OrderStatusCancelled, OrderStatusEvicted},
+	OrderStatusPickedUp:       {OrderStatusOutForDelivery},
+	OrderStatusOutForDelivery: {OrderStatusDelivered},
+}
+
+// CanTransitionTo returns true if an order can move from this status to the next status.
+func (s OrderStatus) CanTransitionTo(nextStatus OrderStatus) bool {
+	for _, allowedStatus := range orderStatusTransitions[s] {
+		if allowedStatus == nextStatus {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewOrderEvent returns an order event if the transition is allowed by the order state machine.
+func NewOrderEvent(orderUUID guuid.UUID, fromStatus OrderStatus, toStatus OrderStatus, reason string) (*OrderEvent, error) {
+	if !fromStatus.CanTransitionTo(toStatus) {
+		return nil, errors.Wrapf(
+			exception.ErrInvalidResourceState, "order cannot move from %q to %q", fromStatus, toStatus)
+	}
+
+	// An order enters each status at most once, so we derive the event uuid
+	// from the order and status. This keeps retried transitions idempotent.
+	orderEvent := OrderEvent{
+		UUID:       guuid.NewV5(orderUUID, string(toStatus)),
+		OrderUUID:  orderUUID,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+
+	return &orderEvent, nil
+}
+
+// String returns a prettified string representation of an order event.
+func (o *OrderEvent) String() string {
+	orderEventString := fmt.Sprintf("FromStatus: %s, ToStatus: %s, Reason: %s", o.FromStatus, o.ToStatus, o.Reason)
+	return orderEventString
+}
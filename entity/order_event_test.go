@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	// Every legal transition of the order state machine, any other transition is illegal.
+	legalTransitions := []struct {
+		from OrderStatus
+		to   OrderStatus
+	}{
+		{"", OrderStatusReceived},
+		{OrderStatusReceived, OrderStatusScheduled},
+		{OrderStatusReceived, OrderStatusQueued},
+		{OrderStatusReceived, OrderStatusCancelled},
+		{OrderStatusScheduled, OrderStatusQueued},
+		{OrderStatusScheduled, OrderStatusCancelled},
+		{OrderStatusQueued, OrderStatusCooking},
+		{OrderStatusQueued, OrderStatusReadyForPickup},
+		{OrderStatusQueued, OrderStatusCancelled},
+		{OrderStatusQueued, OrderStatusEvicted},
+		{OrderStatusCooking, OrderStatusReadyForPickup},
+		{OrderStatusCooking, OrderStatusCancelled},
+		{OrderStatusCooking, OrderStatusEvicted},
+		{OrderStatusReadyForPickup, OrderStatusPickedUp},
+		{OrderStatusReadyForPickup, OrderStatusWasted},
+		{OrderStatusReadyForPickup, OrderStatusCancelled},
+		{OrderStatusReadyForPickup, OrderStatusEvicted},
+		{OrderStatusPickedUp, OrderStatusOutForDelivery},
+		{OrderStatusOutForDelivery, OrderStatusDelivered},
+	}
+
+	isLegal := make(map[OrderStatus]map[OrderStatus]bool)
+	for _, transition := range legalTransitions {
+		if isLegal[transition.from] == nil {
+			isLegal[transition.from] = make(map[OrderStatus]bool)
+		}
+		isLegal[transition.from][transition.to] = true
+	}
+
+	statuses := []OrderStatus{""}
+	for status := range AllOrderStatuses {
+		statuses = append(statuses, status)
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			assert.Equal(t, isLegal[from][to], from.CanTransitionTo(to), "%q => %q", from, to)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// OutboxEvent is an event recorded in the same DB transaction as the order status change
+// it describes, so it is relayed to the outbox sinks even if the service crashes right after
+// the change is committed. Events are relayed at least once, consumers drop duplicates by dedup ID.
+type OutboxEvent struct {
+	DedupID     guuid.UUID // the same every time the event is relayed, ex: the uuid of its order event
+	Event       Event      // its ID increases with every event recorded, events are relayed in that order
+	Attempts    int        // failed attempts to relay the event
+	LastError   string     // why the last attempt failed
+	PublishedAt time.Time  // zero until every sink accepted the event
+	FailedAt    time.Time  // zero unless the event is parked after too many failed attempts, it is not relayed again
+	CreatedAt   time.Time
+}
+
+// NewOutboxEvent returns the outbox event of an order event at a kitchen,
+// order events without an event type are not relayed.
+func NewOutboxEvent(kitchenUUID guuid.UUID, shelfType ShelfType, orderEvent OrderEvent) (*OutboxEvent, error) {
+	event, err := NewEvent(kitchenUUID, shelfType, orderEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Order events are only recorded once per order and status,
+	// so their uuid is the same however many times the event is relayed.
+	return &OutboxEvent{
+		DedupID: orderEvent.UUID,
+		Event:   *event,
+	}, nil
+}
+
+// String returns a prettified string representation of an outbox event.
+func (o *OutboxEvent) String() string {
+	outboxEventString := fmt.Sprintf("DedupID: %s, %s, Attempts: %d", o.DedupID, o.Event.String(), o.Attempts)
+	return outboxEventString
+}
+
+// OutboxSinkType is outbox sink enum.
+type OutboxSinkType string
+
+var (
+	// OutboxSinkRedisStream is for appending events to a Redis stream.
+	OutboxSinkRedisStream = OutboxSinkType("redis_stream")
+	// OutboxSinkWebhook is for calling back the webhook subscriptions of events.
+	OutboxSinkWebhook = OutboxSinkType("webhook")
+	// OutboxSinkLog is for writing events to the service log.
+	OutboxSinkLog = OutboxSinkType("log")
+)
+
+// AllOutboxSinkTypes holds every outbox sink.
+var AllOutboxSinkTypes = map[OutboxSinkType]bool{
+	OutboxSinkRedisStream: true,
+	OutboxSinkWebhook:     true,
+	OutboxSinkLog:         true,
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ParentOrder is a customer order made up of line items,
+// every item is an order placed on the shelf for its temperature.
+type ParentOrder struct {
+	UUID         guuid.UUID
+	KitchenUUID  guuid.UUID                 // kitchen every item is prepared at
+	Items        []Order                    // line items of the order
+	ItemStatuses map[guuid.UUID]OrderStatus // status of every item by order uuid, set when fetched
+	CreatedAt    time.Time
+}
+
+// orderStatusProgress ranks the statuses an order moves through on its way to
+// a customer so a parent order is only as far along as its slowest item.
+var orderStatusProgress = map[OrderStatus]int{
+	OrderStatusReceived:       0,
+	OrderStatusScheduled:      1,
+	OrderStatusQueued:         2,
+	OrderStatusCooking:        3,
+	OrderStatusReadyForPickup: 4,
+	OrderStatusPickedUp:       5,
+	OrderStatusOutForDelivery: 6,
+	OrderStatusDelivered:      7,
+}
+
+// Validate verifies that a parent order and its items are valid.
+func (p *ParentOrder) Validate() error {
+	if len(p.Items) == 0 {
+		return errors.Wrap(exception.ErrInvalidInput, "parent order must have at least one item")
+	}
+
+	for _, item := range p.Items {
+		if item.ParentOrderUUID != p.UUID {
+			return errors.Wrapf(
+				exception.ErrInvalidInput, "item %s belongs to parent order %s", item.UUID, item.ParentOrderUUID)
+		}
+		if item.KitchenUUID != p.KitchenUUID {
+			return errors.Wrapf(
+				exception.ErrInvalidInput, "item %s is prepared at kitchen %s", item.UUID, item.KitchenUUID)
+		}
+
+		err := item.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetStatus returns the status of a parent order from the status of its items.
+// A wasted, evicted or cancelled item is reported on the parent order before the rest of
+// its items, which are cancelled with it. Otherwise the parent order has the status of
+// its least progressed item, ex: it is only ready for pickup once every item is on a shelf.
+func (p *ParentOrder) GetStatus() OrderStatus {
+	for _, failedStatus := range []OrderStatus{OrderStatusWasted, OrderStatusEvicted, OrderStatusCancelled} {
+		if len(p.GetItemsWithStatus(failedStatus)) > 0 {
+			return failedStatus
+		}
+	}
+
+	var status OrderStatus
+	for i, item := range p.Items {
+		itemStatus := p.ItemStatuses[item.UUID]
+		if i == 0 || orderStatusProgress[itemStatus] < orderStatusProgress[status] {
+			status = itemStatus
+		}
+	}
+
+	return status
+}
+
+// GetItemsWithStatus returns the items of a parent order with a status.
+func (p *ParentOrder) GetItemsWithStatus(status OrderStatus) []Order {
+	var items []Order
+	for _, item := range p.Items {
+		if p.ItemStatuses[item.UUID] == status {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// String returns a prettified string representation of a parent order.
+func (p *ParentOrder) String() string {
+	parentOrderString := fmt.Sprintf("ParentOrder: %s, Items: %d", p.UUID, len(p.Items))
+	for _, item := range p.Items {
+		parentOrderString += fmt.Sprintf(", [%s]", item.String())
+	}
+
+	return parentOrderString
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// Pickup is a record of a courier collecting an order off of a shelf.
+type Pickup struct {
+	UUID            guuid.UUID
+	KitchenUUID     guuid.UUID
+	CourierUUID     guuid.UUID
+	OrderUUID       guuid.UUID
+	ShelfOrderUUID  guuid.UUID
+	Strategy        PickupStrategy
+	FoodWaitTime    time.Duration // time the order sat on a shelf
+	CourierWaitTime time.Duration // time the courier waited for an order
+	PickedUpAt      time.Time
+}
+
+// String returns a prettified string representation of a pickup.
+func (p *Pickup) String() string {
+	pickupString := fmt.Sprintf(
+		"Strategy: %s, FoodWaitTime: %s, CourierWaitTime: %s", p.Strategy, p.FoodWaitTime, p.CourierWaitTime)
+	return pickupString
+}
+
+// PickupStats holds aggregated pickup wait times for a strategy
+// so strategies can be compared against each other.
+type PickupStats struct {
+	Strategy           PickupStrategy
+	NumOfPickups       int
+	AvgFoodWaitTime    time.Duration
+	AvgCourierWaitTime time.Duration
+}
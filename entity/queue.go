@@ -1,6 +1,14 @@
 package entity
 
-import "github.com/gomodule/redigo/redis"
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
 
 // Queues holds Redis queues.
 type Queues struct {
@@ -12,6 +20,230 @@ type Queues struct {
 
 // Queue holds queue name and redis connection.
 type Queue struct {
-	Name string
-	Pool *redis.Pool
+	Name     string
+	Pool     *redis.Pool
+	ReadPool *redis.Pool   // orders are pulled on its connections if set, so blocking reads never hold up Pool
+	Backend  QueueBackend  // how orders are stored in Redis, a list per queue if empty
+	Stream   StreamOptions // only used by the stream backend
+
+	consumerGroups sync.Map // names of the streams the consumer group is known to exist on
+}
+
+// StreamOptions holds how workers share the Redis streams of the stream backend.
+type StreamOptions struct {
+	ConsumerGroup string        // workers of every service instance read as one consumer group
+	MaxLength     int           // approximate max number of orders waiting on a stream
+	BlockTimeout  time.Duration // how long a read waits for an order before giving up
+	ClaimTimeout  time.Duration // how long an order is unacknowledged before another worker takes it over
+}
+
+// QueueBackend is queue backend enum.
+type QueueBackend string
+
+var (
+	// QueueBackendList is for queues stored as Redis lists, orders are gone once popped.
+	QueueBackendList = QueueBackend("list")
+	// QueueBackendStream is for queues stored as Redis streams read by a consumer group,
+	// orders are only gone once a worker acknowledges them.
+	QueueBackendStream = QueueBackend("stream")
+)
+
+// AllQueueBackends holds every queue backend.
+var AllQueueBackends = map[QueueBackend]bool{
+	QueueBackendList:   true,
+	QueueBackendStream: true,
+}
+
+// QueuedOrder is an order pulled off of a queue, acknowledged once a worker is done with it.
+type QueuedOrder struct {
+	OrderUUID   guuid.UUID
+	KitchenUUID guuid.UUID // kitchen whose queue the order was pulled off of
+	QueueName   string
+	EntryID     string // ID of the stream entry, empty for the list backend
+}
+
+// GetKitchenQueueName returns the name of a kitchen's queue for orders of a priority,
+// ex: "Order:{kitchenUUID}" for standard orders and "Order:{kitchenUUID}:vip" for vip orders.
+func (q *Queue) GetKitchenQueueName(kitchenUUID guuid.UUID, priority OrderPriority) string {
+	// The default kitchen keeps the original queue name
+	// so orders queued before kitchens existed are still worked on.
+	queueName := q.Name
+	if kitchenUUID != DefaultKitchenUUID {
+		queueName = fmt.Sprintf("%s:%s", q.Name, kitchenUUID.String())
+	}
+
+	// Standard orders keep the original queue name
+	// so orders queued before priorities existed are still worked on.
+	if priority == "" || priority == OrderPriorityStandard {
+		return q.withBackendSuffix(queueName)
+	}
+
+	return q.withBackendSuffix(fmt.Sprintf("%s:%s", queueName, priority))
+}
+
+// getKitchensQueueNames returns the names of the queues of kitchens, highest priority first
+// and in the order of the kitchens for queues of the same priority, along with the kitchen of each queue.
+func (q *Queue) getKitchensQueueNames(kitchenUUIDs []guuid.UUID) ([]string, map[string]guuid.UUID) {
+	var queueNames []string
+	queueKitchens := make(map[string]guuid.UUID)
+	for _, priority := range OrderPrioritiesByRank {
+		for _, kitchenUUID := range kitchenUUIDs {
+			queueName := q.GetKitchenQueueName(kitchenUUID, priority)
+			queueNames = append(queueNames, queueName)
+			queueKitchens[queueName] = kitchenUUID
+		}
+	}
+
+	return queueNames, queueKitchens
+}
+
+// withBackendSuffix keeps streams apart from lists of the same queue,
+// ex: "Order:vip" => "Order:vip:stream", as Redis keys only hold one type.
+func (q *Queue) withBackendSuffix(queueName string) string {
+	if q.Backend == QueueBackendStream {
+		return queueName + ":stream"
+	}
+
+	return queueName
+}
+
+// Push places an order on its kitchen's queue for its priority.
+func (q *Queue) Push(kitchenUUID guuid.UUID, priority OrderPriority, orderUUID guuid.UUID) error {
+	redisConn := q.Pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return redisConn.Err()
+	}
+
+	queueName := q.GetKitchenQueueName(kitchenUUID, priority)
+	if q.Backend == QueueBackendStream {
+		return q.pushToStream(redisConn, queueName, orderUUID)
+	}
+
+	_, err := redisConn.Do("LPUSH", queueName, orderUUID.String())
+	return err
+}
+
+// Pull pulls the highest priority order off of the queues of kitchens, nil if every queue is empty.
+// Kitchens earlier on the list go first for orders of the same priority.
+// The consumer names the worker pulling the order.
+func (q *Queue) Pull(kitchenUUIDs []guuid.UUID, consumer string) (*QueuedOrder, error) {
+	pool := q.Pool
+	if q.ReadPool != nil {
+		pool = q.ReadPool
+	}
+
+	redisConn := pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return nil, redisConn.Err()
+	}
+
+	queueNames, queueKitchens := q.getKitchensQueueNames(kitchenUUIDs)
+	if q.Backend == QueueBackendStream {
+		queuedOrder, err := q.pullFromStreams(redisConn, queueNames, consumer)
+		if queuedOrder != nil {
+			queuedOrder.KitchenUUID = queueKitchens[queuedOrder.QueueName]
+		}
+		return queuedOrder, err
+	}
+
+	for _, queueName := range queueNames {
+		orderUUIDObj, err := redisConn.Do("RPOP", queueName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch order uuid from order queue %s", queueName)
+		}
+		if orderUUIDObj == nil {
+			// Nothing in the queue to pull and work on.
+			continue
+		}
+
+		orderUUIDStr := string(orderUUIDObj.([]uint8))
+		orderUUID, err := guuid.FromString(orderUUIDStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "order uuid got corrupted on order queue %s", queueName)
+		}
+
+		return &QueuedOrder{OrderUUID: orderUUID, KitchenUUID: queueKitchens[queueName], QueueName: queueName}, nil
+	}
+
+	return nil, nil
+}
+
+// Ack acknowledges that a worker is done with an order it pulled off of a queue.
+// Orders of the list backend are gone once pulled, so there is nothing to acknowledge.
+func (q *Queue) Ack(queuedOrder QueuedOrder) error {
+	if q.Backend != QueueBackendStream {
+		return nil
+	}
+
+	redisConn := q.Pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return redisConn.Err()
+	}
+
+	return q.ackStreamEntries(redisConn, queuedOrder.QueueName, queuedOrder.EntryID)
+}
+
+// Release gives back an order a worker could not finish so it is pulled again. Orders of the list
+// backend are pushed back on their queue, as they are gone once pulled. Stream entries are left
+// unacknowledged and marked idle, so the next worker that pulls off of the stream takes them over.
+func (q *Queue) Release(queuedOrder QueuedOrder, consumer string) error {
+	redisConn := q.Pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return redisConn.Err()
+	}
+
+	if q.Backend == QueueBackendStream {
+		return q.releaseStreamEntries(redisConn, queuedOrder.QueueName, consumer, queuedOrder.EntryID)
+	}
+
+	// Pushed on the end orders are pulled off of first, as the order was already waiting its turn.
+	_, err := redisConn.Do("RPUSH", queuedOrder.QueueName, queuedOrder.OrderUUID.String())
+	return err
+}
+
+// KeepClaimed resets how long an order pulled by a consumer has gone unacknowledged,
+// so no other worker takes it over while it is still being worked on.
+// Orders of the list backend are never taken over, so there is nothing to keep.
+func (q *Queue) KeepClaimed(queuedOrder QueuedOrder, consumer string) error {
+	if q.Backend != QueueBackendStream {
+		return nil
+	}
+
+	redisConn := q.Pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return redisConn.Err()
+	}
+
+	_, err := redisConn.Do(
+		"XCLAIM", queuedOrder.QueueName, q.Stream.ConsumerGroup, consumer, 0, queuedOrder.EntryID, "JUSTID")
+	if err != nil {
+		return errors.Wrapf(err, "failed to keep order %s claimed on stream %s", queuedOrder.OrderUUID, queuedOrder.QueueName)
+	}
+
+	return nil
+}
+
+// Remove takes an order that no worker pulled yet off of its kitchen's queue. Streams are only
+// read in order, so entries of removed orders are left on the stream and acknowledged once
+// a worker pulls them, as workers skip cancelled orders.
+func (q *Queue) Remove(kitchenUUID guuid.UUID, priority OrderPriority, orderUUID guuid.UUID) error {
+	if q.Backend == QueueBackendStream {
+		return nil
+	}
+
+	redisConn := q.Pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return redisConn.Err()
+	}
+
+	queueName := q.GetKitchenQueueName(kitchenUUID, priority)
+
+	_, err := redisConn.Do("LREM", queueName, 0, orderUUID.String())
+	return err
 }
@@ -0,0 +1,246 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// orderUUIDField is the field of a stream entry holding the queued order's uuid.
+const orderUUIDField = "orderUUID"
+
+// streamEntry is an entry read off of a stream.
+type streamEntry struct {
+	id     string
+	fields map[string]string // nil if the entry was deleted after it was read
+}
+
+// pushToStream appends an order to a stream trimmed to about its max length.
+func (q *Queue) pushToStream(redisConn redis.Conn, streamName string, orderUUID guuid.UUID) error {
+	_, err := redisConn.Do(
+		"XADD", streamName, "MAXLEN", "~", q.Stream.MaxLength, "*", orderUUIDField, orderUUID.String())
+	return err
+}
+
+// pullFromStreams reads the highest priority order off of streams as a consumer of the consumer group.
+// Orders a dead worker never acknowledged are taken over first, then new orders are read off of
+// every stream at once. If every stream is empty the read blocks until an order comes in
+// or the block timeout runs out.
+func (q *Queue) pullFromStreams(redisConn redis.Conn, streamNames []string, consumer string) (*QueuedOrder, error) {
+	err := q.createConsumerGroups(redisConn, streamNames)
+	if err != nil {
+		return nil, err
+	}
+
+	queuedOrder, err := q.readOrClaim(redisConn, streamNames, consumer)
+	if err != nil && strings.Contains(err.Error(), "NOGROUP") {
+		// The stream was deleted along with its consumer group, it is created again on the next pull.
+		for _, streamName := range streamNames {
+			q.consumerGroups.Delete(streamName)
+		}
+	}
+
+	return queuedOrder, err
+}
+
+// readOrClaim takes over an order a dead worker never acknowledged,
+// or reads a new one off of the highest priority stream.
+func (q *Queue) readOrClaim(redisConn redis.Conn, streamNames []string, consumer string) (*QueuedOrder, error) {
+	claimTimeout := q.Stream.ClaimTimeout.Nanoseconds() / 1e6
+	for _, streamName := range streamNames {
+		reply, err := redis.Values(redisConn.Do(
+			"XAUTOCLAIM", streamName, q.Stream.ConsumerGroup, consumer, claimTimeout, "0-0", "COUNT", 1))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to claim stale orders of stream %s", streamName)
+		}
+		if len(reply) < 2 {
+			continue
+		}
+
+		entries, err := parseStreamEntries(reply[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse claimed orders of stream %s", streamName)
+		}
+
+		queuedOrder, err := q.toQueuedOrder(redisConn, streamName, entries)
+		if err != nil || queuedOrder != nil {
+			return queuedOrder, err
+		}
+	}
+
+	return q.readStreams(redisConn, streamNames, consumer)
+}
+
+// readStreams reads at most one new order off of each stream in one blocking read and returns
+// the highest priority one. Orders read off of lower priority streams are released to be taken
+// over right away.
+func (q *Queue) readStreams(redisConn redis.Conn, streamNames []string, consumer string) (*QueuedOrder, error) {
+	args := redis.Args{"GROUP", q.Stream.ConsumerGroup, consumer, "COUNT", 1}
+	args = args.Add("BLOCK", q.Stream.BlockTimeout.Nanoseconds()/1e6)
+	args = args.Add("STREAMS").AddFlat(streamNames)
+	for range streamNames {
+		args = args.Add(">")
+	}
+
+	reply, err := redis.Values(redisConn.Do("XREADGROUP", args...))
+	if err == redis.ErrNil {
+		// Nothing in the streams to pull and work on.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read orders of streams %v", streamNames)
+	}
+
+	entriesByStream := make(map[string][]streamEntry)
+	for _, streamReply := range reply {
+		stream, err := redis.Values(streamReply, nil)
+		if err != nil || len(stream) != 2 {
+			return nil, errors.Errorf("unexpected stream reply %v", streamReply)
+		}
+
+		streamName, err := redis.String(stream[0], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		entriesByStream[streamName], err = parseStreamEntries(stream[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse orders of stream %s", streamName)
+		}
+	}
+
+	var queuedOrder *QueuedOrder
+	for _, streamName := range streamNames {
+		entries := entriesByStream[streamName]
+		if len(entries) == 0 {
+			continue
+		}
+
+		if queuedOrder != nil {
+			// If this fails every order read is taken over once the claim timeout runs out.
+			entryIDs := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				entryIDs = append(entryIDs, entry.id)
+			}
+			err = q.releaseStreamEntries(redisConn, streamName, consumer, entryIDs...)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		queuedOrder, err = q.toQueuedOrder(redisConn, streamName, entries)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return queuedOrder, nil
+}
+
+// toQueuedOrder returns the order of the first entry read off of a stream,
+// entries that do not hold an order are acknowledged so they are never read again.
+func (q *Queue) toQueuedOrder(redisConn redis.Conn, streamName string, entries []streamEntry) (*QueuedOrder, error) {
+	for _, entry := range entries {
+		orderUUID, err := guuid.FromString(entry.fields[orderUUIDField])
+		if err != nil {
+			err = q.ackStreamEntries(redisConn, streamName, entry.id)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return &QueuedOrder{OrderUUID: orderUUID, QueueName: streamName, EntryID: entry.id}, nil
+	}
+
+	return nil, nil
+}
+
+// releaseStreamEntries marks entries read by a consumer as idle for the claim timeout,
+// so the next worker that pulls off of the stream takes them over.
+func (q *Queue) releaseStreamEntries(redisConn redis.Conn, streamName string, consumer string, entryIDs ...string) error {
+	args := redis.Args{streamName, q.Stream.ConsumerGroup, consumer, 0}.AddFlat(entryIDs)
+	args = args.Add("IDLE", q.Stream.ClaimTimeout.Nanoseconds()/1e6, "JUSTID")
+
+	_, err := redisConn.Do("XCLAIM", args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to release orders of stream %s", streamName)
+	}
+
+	return nil
+}
+
+// ackStreamEntries acknowledges entries of a stream and deletes them,
+// so a stream only holds orders that are waiting or being worked on.
+func (q *Queue) ackStreamEntries(redisConn redis.Conn, streamName string, entryIDs ...string) error {
+	args := redis.Args{streamName, q.Stream.ConsumerGroup}.AddFlat(entryIDs)
+	_, err := redisConn.Do("XACK", args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to acknowledge orders of stream %s", streamName)
+	}
+
+	_, err = redisConn.Do("XDEL", redis.Args{streamName}.AddFlat(entryIDs)...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete orders of stream %s", streamName)
+	}
+
+	return nil
+}
+
+// createConsumerGroups creates the consumer group on streams that do not have it yet,
+// along with the streams themselves. The group starts at the beginning of a stream,
+// so orders pushed before any worker ran are still read. Each stream's group is only
+// created once, so pulls do not pay for it.
+func (q *Queue) createConsumerGroups(redisConn redis.Conn, streamNames []string) error {
+	for _, streamName := range streamNames {
+		if _, ok := q.consumerGroups.Load(streamName); ok {
+			continue
+		}
+
+		_, err := redisConn.Do("XGROUP", "CREATE", streamName, q.Stream.ConsumerGroup, "0", "MKSTREAM")
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return errors.Wrapf(err, "failed to create consumer group of stream %s", streamName)
+		}
+
+		q.consumerGroups.Store(streamName, true)
+	}
+
+	return nil
+}
+
+// parseStreamEntries parses a list of stream entries, ex: [[id, [field, value, ...]], ...].
+func parseStreamEntries(reply interface{}) ([]streamEntry, error) {
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []streamEntry
+	for _, value := range values {
+		entry, err := redis.Values(value, nil)
+		if err != nil || len(entry) != 2 {
+			return nil, errors.Errorf("unexpected stream entry %v", value)
+		}
+
+		id, err := redis.String(entry[0], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Entries deleted after they were read have no fields.
+		var fields map[string]string
+		if entry[1] != nil {
+			fields, err = redis.StringMap(entry[1], nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		entries = append(entries, streamEntry{id: id, fields: fields})
+	}
+
+	return entries, nil
+}
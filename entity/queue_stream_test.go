@@ -0,0 +1,433 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStreamEntry is an entry of a fake stream.
+type fakeStreamEntry struct {
+	seq    int
+	fields []interface{}
+}
+
+// fakePendingEntry is an entry read by a consumer that is not acknowledged yet.
+type fakePendingEntry struct {
+	consumer    string
+	deliveredAt time.Time
+}
+
+// fakeStream is a stream with one consumer group.
+type fakeStream struct {
+	entries       []fakeStreamEntry
+	lastSeq       int
+	lastDelivered int
+	hasGroup      bool
+	pending       map[int]*fakePendingEntry
+}
+
+// fakeStreamConn is a Redis connection holding streams in memory. It implements the stream commands
+// the stream backend sends, never blocks and only advances its clock when told to.
+type fakeStreamConn struct {
+	mu       sync.Mutex
+	now      time.Time
+	streams  map[string]*fakeStream
+	commands []string         // every command sent, ex: "XREADGROUP GROUP workers worker-0 ..."
+	failOn   map[string]error // commands that fail, ex: {"XCLAIM": err}
+}
+
+func newFakeStreamConn() *fakeStreamConn {
+	return &fakeStreamConn{
+		now:     time.Now(),
+		streams: make(map[string]*fakeStream),
+		failOn:  make(map[string]error),
+	}
+}
+
+// newFakeStreamQueue returns an order queue with the stream backend on top of a fake Redis connection.
+func newFakeStreamQueue(conn *fakeStreamConn) *Queue {
+	pool := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return conn, nil
+		},
+	}
+
+	return &Queue{
+		Name:    "Order",
+		Pool:    pool,
+		Backend: QueueBackendStream,
+		Stream: StreamOptions{
+			ConsumerGroup: "workers",
+			MaxLength:     1000,
+			BlockTimeout:  time.Second,
+			ClaimTimeout:  30 * time.Second,
+		},
+	}
+}
+
+func (c *fakeStreamConn) Close() error {
+	return nil
+}
+
+func (c *fakeStreamConn) Err() error {
+	return nil
+}
+
+func (c *fakeStreamConn) Send(commandName string, args ...interface{}) error {
+	return errors.New("not supported")
+}
+
+func (c *fakeStreamConn) Flush() error {
+	return nil
+}
+
+func (c *fakeStreamConn) Receive() (interface{}, error) {
+	return nil, errors.New("not supported")
+}
+
+// advance moves the clock of the connection forward.
+func (c *fakeStreamConn) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// getCommands returns every command sent starting with a name.
+func (c *fakeStreamConn) getCommands(commandName string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var commands []string
+	for _, command := range c.commands {
+		if strings.HasPrefix(command, commandName+" ") {
+			commands = append(commands, command)
+		}
+	}
+
+	return commands
+}
+
+// countEntries returns how many entries a stream holds and how many of them are pending.
+func (c *fakeStreamConn) countEntries(streamName string) (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stream := c.getStream(streamName)
+	return len(stream.entries), len(stream.pending)
+}
+
+func (c *fakeStreamConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	// The pool sends an empty command when a connection is closed.
+	if commandName == "" {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	strArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		strArgs = append(strArgs, fmt.Sprint(arg))
+	}
+	c.commands = append(c.commands, commandName+" "+strings.Join(strArgs, " "))
+	if err := c.failOn[commandName]; err != nil {
+		return nil, err
+	}
+
+	switch commandName {
+	case "XGROUP":
+		stream := c.getStream(strArgs[1])
+		if stream.hasGroup {
+			return nil, redis.Error("BUSYGROUP Consumer Group name already exists")
+		}
+		stream.hasGroup = true
+		return "OK", nil
+	case "XADD":
+		stream := c.getStream(strArgs[0])
+		stream.lastSeq++
+		stream.entries = append(stream.entries, fakeStreamEntry{
+			seq:    stream.lastSeq,
+			fields: []interface{}{[]byte(strArgs[len(strArgs)-2]), []byte(strArgs[len(strArgs)-1])},
+		})
+		return []byte(toEntryID(stream.lastSeq)), nil
+	case "XREADGROUP":
+		return c.readGroup(strArgs)
+	case "XAUTOCLAIM":
+		return c.autoClaim(strArgs)
+	case "XCLAIM":
+		return c.claim(strArgs)
+	case "XACK":
+		stream := c.getStream(strArgs[0])
+		for _, entryID := range strArgs[2:] {
+			delete(stream.pending, toSeq(entryID))
+		}
+		return int64(len(strArgs) - 2), nil
+	case "XDEL":
+		stream := c.getStream(strArgs[0])
+		for _, entryID := range strArgs[1:] {
+			for i, entry := range stream.entries {
+				if entry.seq == toSeq(entryID) {
+					stream.entries = append(stream.entries[:i], stream.entries[i+1:]...)
+					break
+				}
+			}
+		}
+		return int64(len(strArgs) - 1), nil
+	}
+
+	return nil, errors.Errorf("unknown command %s", commandName)
+}
+
+// readGroup reads new entries, ex: GROUP workers worker-0 COUNT 1 BLOCK 1000 STREAMS a b > >.
+func (c *fakeStreamConn) readGroup(args []string) (interface{}, error) {
+	consumer := args[2]
+	count, _ := strconv.Atoi(args[4])
+
+	var streamNames []string
+	for i, arg := range args {
+		if arg == "STREAMS" {
+			streamNames = args[i+1 : i+1+(len(args)-i-1)/2]
+		}
+	}
+
+	var reply []interface{}
+	for _, streamName := range streamNames {
+		stream := c.getStream(streamName)
+		if !stream.hasGroup {
+			return nil, redis.Error("NOGROUP No such key or consumer group")
+		}
+
+		var entries []interface{}
+		for _, entry := range stream.entries {
+			if entry.seq <= stream.lastDelivered || len(entries) == count {
+				continue
+			}
+
+			stream.lastDelivered = entry.seq
+			stream.pending[entry.seq] = &fakePendingEntry{consumer: consumer, deliveredAt: c.now}
+			entries = append(entries, toEntryReply(entry))
+		}
+		if len(entries) > 0 {
+			reply = append(reply, []interface{}{[]byte(streamName), entries})
+		}
+	}
+
+	// Blocking reads time out right away.
+	if len(reply) == 0 {
+		return nil, nil
+	}
+
+	return reply, nil
+}
+
+// autoClaim takes over idle pending entries, ex: stream workers worker-1 30000 0-0 COUNT 1.
+func (c *fakeStreamConn) autoClaim(args []string) (interface{}, error) {
+	stream := c.getStream(args[0])
+	consumer := args[2]
+	minIdle, _ := strconv.Atoi(args[3])
+	count, _ := strconv.Atoi(args[6])
+
+	var seqs []int
+	for seq := range stream.pending {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+
+	var entries []interface{}
+	for _, seq := range seqs {
+		pending := stream.pending[seq]
+		if c.now.Sub(pending.deliveredAt) < time.Duration(minIdle)*time.Millisecond || len(entries) == count {
+			continue
+		}
+
+		for _, entry := range stream.entries {
+			if entry.seq == seq {
+				pending.consumer = consumer
+				pending.deliveredAt = c.now
+				entries = append(entries, toEntryReply(entry))
+			}
+		}
+	}
+
+	return []interface{}{[]byte("0-0"), entries, []interface{}{}}, nil
+}
+
+// claim changes the owner of pending entries, ex: stream workers worker-0 0 1-0 IDLE 30000 JUSTID.
+func (c *fakeStreamConn) claim(args []string) (interface{}, error) {
+	stream := c.getStream(args[0])
+	consumer := args[2]
+
+	idle := time.Duration(0)
+	var entryIDs []interface{}
+	for i := 4; i < len(args); i++ {
+		switch args[i] {
+		case "IDLE":
+			ms, _ := strconv.Atoi(args[i+1])
+			idle = time.Duration(ms) * time.Millisecond
+			i++
+		case "JUSTID":
+		default:
+			entryIDs = append(entryIDs, args[i])
+		}
+	}
+
+	var claimed []interface{}
+	for _, entryID := range entryIDs {
+		pending, ok := stream.pending[toSeq(entryID.(string))]
+		if !ok {
+			continue
+		}
+
+		pending.consumer = consumer
+		pending.deliveredAt = c.now.Add(-idle)
+		claimed = append(claimed, []byte(entryID.(string)))
+	}
+
+	return claimed, nil
+}
+
+func (c *fakeStreamConn) getStream(streamName string) *fakeStream {
+	stream, ok := c.streams[streamName]
+	if !ok {
+		stream = &fakeStream{pending: make(map[int]*fakePendingEntry)}
+		c.streams[streamName] = stream
+	}
+
+	return stream
+}
+
+func toEntryID(seq int) string {
+	return fmt.Sprintf("%d-0", seq)
+}
+
+func toSeq(entryID string) int {
+	seq, _ := strconv.Atoi(strings.TrimSuffix(entryID, "-0"))
+	return seq
+}
+
+func toEntryReply(entry fakeStreamEntry) interface{} {
+	return []interface{}{[]byte(toEntryID(entry.seq)), entry.fields}
+}
+
+func TestStreamQueue_PushPullAck(t *testing.T) {
+	conn := newFakeStreamConn()
+	queue := newFakeStreamQueue(conn)
+
+	kitchenUUID := guuid.NewV4()
+	standardOrderUUID := guuid.NewV4()
+	vipOrderUUID := guuid.NewV4()
+	assert.Nil(t, queue.Push(DefaultKitchenUUID, OrderPriorityStandard, standardOrderUUID))
+	assert.Nil(t, queue.Push(kitchenUUID, OrderPriorityVIP, vipOrderUUID))
+
+	// Every stream of every kitchen is read at once, the vip order of the second kitchen goes first.
+	queuedOrder, err := queue.Pull([]guuid.UUID{DefaultKitchenUUID, kitchenUUID}, "worker-0")
+	assert.Nil(t, err)
+	assert.Equal(t, vipOrderUUID, queuedOrder.OrderUUID)
+	assert.Equal(t, kitchenUUID, queuedOrder.KitchenUUID)
+	assert.Equal(t, queue.GetKitchenQueueName(kitchenUUID, OrderPriorityVIP), queuedOrder.QueueName)
+
+	readCommands := conn.getCommands("XREADGROUP")
+	assert.Len(t, readCommands, 1)
+	assert.Contains(t, readCommands[0], "BLOCK")
+	for _, priority := range OrderPrioritiesByRank {
+		assert.Contains(t, readCommands[0], queue.GetKitchenQueueName(DefaultKitchenUUID, priority))
+		assert.Contains(t, readCommands[0], queue.GetKitchenQueueName(kitchenUUID, priority))
+	}
+
+	// The standard order read along with it is released and taken over by the next pull.
+	queuedOrder2, err := queue.Pull([]guuid.UUID{DefaultKitchenUUID, kitchenUUID}, "worker-1")
+	assert.Nil(t, err)
+	assert.Equal(t, standardOrderUUID, queuedOrder2.OrderUUID)
+	assert.Equal(t, DefaultKitchenUUID, queuedOrder2.KitchenUUID)
+
+	// Acknowledged orders are deleted from their stream.
+	assert.Nil(t, queue.Ack(*queuedOrder))
+	entries, pending := conn.countEntries(queuedOrder.QueueName)
+	assert.Equal(t, 0, entries)
+	assert.Equal(t, 0, pending)
+
+	queuedOrder, err = queue.Pull([]guuid.UUID{DefaultKitchenUUID, kitchenUUID}, "worker-0")
+	assert.Nil(t, err)
+	assert.Nil(t, queuedOrder)
+}
+
+func TestStreamQueue_Claim(t *testing.T) {
+	conn := newFakeStreamConn()
+	queue := newFakeStreamQueue(conn)
+
+	orderUUID := guuid.NewV4()
+	assert.Nil(t, queue.Push(DefaultKitchenUUID, OrderPriorityStandard, orderUUID))
+
+	queuedOrder, err := queue.Pull([]guuid.UUID{DefaultKitchenUUID}, "worker-0")
+	assert.Nil(t, err)
+	assert.Equal(t, orderUUID, queuedOrder.OrderUUID)
+
+	// Orders kept claimed while they cook are not taken over.
+	conn.advance(20 * time.Second)
+	assert.Nil(t, queue.KeepClaimed(*queuedOrder, "worker-0"))
+	conn.advance(20 * time.Second)
+	claimedOrder, err := queue.Pull([]guuid.UUID{DefaultKitchenUUID}, "worker-1")
+	assert.Nil(t, err)
+	assert.Nil(t, claimedOrder)
+
+	// Orders of a dead worker are taken over once the claim timeout runs out.
+	conn.advance(10 * time.Second)
+	claimedOrder, err = queue.Pull([]guuid.UUID{DefaultKitchenUUID}, "worker-1")
+	assert.Nil(t, err)
+	assert.Equal(t, queuedOrder.EntryID, claimedOrder.EntryID)
+
+	// Released orders are taken over right away.
+	assert.Nil(t, queue.Release(*claimedOrder, "worker-1"))
+	claimedOrder, err = queue.Pull([]guuid.UUID{DefaultKitchenUUID}, "worker-2")
+	assert.Nil(t, err)
+	assert.Equal(t, queuedOrder.EntryID, claimedOrder.EntryID)
+}
+
+func TestStreamQueue_ReleaseError(t *testing.T) {
+	conn := newFakeStreamConn()
+	queue := newFakeStreamQueue(conn)
+
+	assert.Nil(t, queue.Push(DefaultKitchenUUID, OrderPriorityVIP, guuid.NewV4()))
+	assert.Nil(t, queue.Push(DefaultKitchenUUID, OrderPriorityStandard, guuid.NewV4()))
+	conn.failOn["XCLAIM"] = errors.New("connection reset")
+
+	queuedOrder, err := queue.Pull([]guuid.UUID{DefaultKitchenUUID}, "worker-0")
+	assert.Error(t, err)
+	assert.Nil(t, queuedOrder)
+
+	// Both orders are taken over once the claim timeout runs out.
+	delete(conn.failOn, "XCLAIM")
+	conn.advance(30 * time.Second)
+	queuedOrder, err = queue.Pull([]guuid.UUID{DefaultKitchenUUID}, "worker-1")
+	assert.Nil(t, err)
+	assert.NotNil(t, queuedOrder)
+
+	conn.failOn["XCLAIM"] = errors.New("connection reset")
+	assert.Error(t, queue.Release(*queuedOrder, "worker-1"))
+}
+
+func TestStreamQueue_Remove(t *testing.T) {
+	conn := newFakeStreamConn()
+	queue := newFakeStreamQueue(conn)
+
+	orderUUID := guuid.NewV4()
+	assert.Nil(t, queue.Push(DefaultKitchenUUID, OrderPriorityStandard, orderUUID))
+
+	// Streams are not scanned for removed orders, workers skip them once they are pulled.
+	assert.Nil(t, queue.Remove(DefaultKitchenUUID, OrderPriorityStandard, orderUUID))
+	assert.Empty(t, conn.getCommands("XRANGE"))
+
+	queuedOrder, err := queue.Pull([]guuid.UUID{DefaultKitchenUUID}, "worker-0")
+	assert.Nil(t, err)
+	assert.Equal(t, orderUUID, queuedOrder.OrderUUID)
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// ScheduledOrder is an order held off of the order queue until it is released
+// so it is cooked and shelved in time for when the customer wants it.
+type ScheduledOrder struct {
+	OrderUUID   guuid.UUID
+	KitchenUUID guuid.UUID
+	OrderStatus OrderStatus // scheduled until the order is released or cancelled
+	ReadyAt     time.Time   // when the customer wants the order
+	ReleaseAt   time.Time   // when the order is placed on the order queue
+	Version     int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewScheduledOrder returns the schedule of an order that is released early enough
+// to be cooked by its ready time with lead time to spare.
+func NewScheduledOrder(order Order, prepTime time.Duration, leadTime time.Duration) ScheduledOrder {
+	return ScheduledOrder{
+		OrderUUID:   order.UUID,
+		KitchenUUID: order.KitchenUUID,
+		OrderStatus: OrderStatusScheduled,
+		ReadyAt:     order.ReadyAt,
+		ReleaseAt:   order.ReadyAt.Add(-prepTime).Add(-leadTime),
+		Version:     0,
+	}
+}
+
+// String returns a prettified string representation of a scheduled order.
+func (s *ScheduledOrder) String() string {
+	scheduledOrderString := fmt.Sprintf(
+		"OrderUUID: %s, OrderStatus: %s, ReadyAt: %s, ReleaseAt: %s",
+		s.OrderUUID, s.OrderStatus, s.ReadyAt.Format(time.RFC3339), s.ReleaseAt.Format(time.RFC3339))
+	return scheduledOrderString
+}
@@ -0,0 +1,177 @@
+package entity
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+)
+
+// Shelf is a shelf definition in a kitchen's shelf catalog.
+type Shelf struct {
+	Type          ShelfType
+	Capacity      int         // max orders on the shelf at any given time
+	Temps         []OrderTemp // order temperatures the shelf accepts
+	DecayModifier float64     // multiplies an order's decay rate while on the shelf ex: 2.0
+	Overflow      bool        // only used once the shelves dedicated to a temperature are full
+	Reserved      int         // space kept for express and vip orders, standard orders move on once only reserved space is left
+}
+
+// Accepts returns true if the shelf can hold orders of a temperature.
+func (s *Shelf) Accepts(temp OrderTemp) bool {
+	for _, shelfTemp := range s.Temps {
+		if shelfTemp == temp {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetCapacityFor returns how many orders can be on the shelf
+// when an order of a priority is placed on it.
+func (s *Shelf) GetCapacityFor(priority OrderPriority) int {
+	if priority.IsHigherThan(OrderPriorityStandard) {
+		return s.Capacity
+	}
+
+	return s.Capacity - s.Reserved
+}
+
+// String returns a prettified string representation of a shelf.
+func (s *Shelf) String() string {
+	shelfString := fmt.Sprintf(
+		"ShelfType: %s, Capacity: %d, Reserved: %d, Temps: %v, DecayModifier: %.2f, Overflow: %t",
+		s.Type, s.Capacity, s.Reserved, s.Temps, s.DecayModifier, s.Overflow)
+	return shelfString
+}
+
+// ShelfCatalog holds every shelf of a kitchen in placement order.
+type ShelfCatalog struct {
+	Shelves []Shelf
+}
+
+// NewShelfCatalog returns a validated shelf catalog.
+func NewShelfCatalog(shelves []Shelf) (*ShelfCatalog, error) {
+	if len(shelves) == 0 {
+		return nil, errors.Wrap(exception.ErrInvalidInput, "shelf catalog must have at least one shelf")
+	}
+
+	shelfTypes := make(map[ShelfType]bool)
+	for _, shelf := range shelves {
+		if shelf.Type == "" {
+			return nil, errors.Wrap(exception.ErrInvalidInput, "shelf type is required")
+		}
+		if shelfTypes[shelf.Type] {
+			return nil, errors.Wrapf(exception.ErrInvalidInput, "shelf type %s is defined twice", shelf.Type)
+		}
+		if shelf.Capacity <= 0 {
+			return nil, errors.Wrapf(exception.ErrInvalidInput, "shelf %s capacity must be greater than 0", shelf.Type)
+		}
+		if shelf.Reserved < 0 || shelf.Reserved > shelf.Capacity {
+			return nil, errors.Wrapf(exception.ErrInvalidInput, "shelf %s reserved space must be between 0 and its capacity", shelf.Type)
+		}
+		if len(shelf.Temps) == 0 {
+			return nil, errors.Wrapf(exception.ErrInvalidInput, "shelf %s must accept at least one temp", shelf.Type)
+		}
+		if shelf.DecayModifier <= 0 {
+			return nil, errors.Wrapf(exception.ErrInvalidInput, "shelf %s decay modifier must be greater than 0", shelf.Type)
+		}
+
+		shelfTypes[shelf.Type] = true
+	}
+
+	return &ShelfCatalog{Shelves: shelves}, nil
+}
+
+// DefaultShelfCatalog returns the hot, cold, frozen and overflow shelves.
+func DefaultShelfCatalog() *ShelfCatalog {
+	return &ShelfCatalog{
+		Shelves: []Shelf{
+			{Type: HotShelf, Capacity: 15, Temps: []OrderTemp{OrderTempHot}, DecayModifier: 1},
+			{Type: ColdShelf, Capacity: 15, Temps: []OrderTemp{OrderTempCold}, DecayModifier: 1},
+			{Type: FrozenShelf, Capacity: 15, Temps: []OrderTemp{OrderTempFrozen}, DecayModifier: 1},
+			{
+				Type:          OverflowShelf,
+				Capacity:      20,
+				Temps:         []OrderTemp{OrderTempHot, OrderTempCold, OrderTempFrozen},
+				DecayModifier: 1,
+				Overflow:      true,
+			},
+		},
+	}
+}
+
+// GetShelf returns a shelf by type.
+func (c *ShelfCatalog) GetShelf(shelfType ShelfType) (*Shelf, bool) {
+	for i := range c.Shelves {
+		if c.Shelves[i].Type == shelfType {
+			return &c.Shelves[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// GetShelvesForTemp returns the shelves that accept an order temperature,
+// dedicated shelves come before overflow shelves.
+func (c *ShelfCatalog) GetShelvesForTemp(temp OrderTemp) []Shelf {
+	var dedicatedShelves, overflowShelves []Shelf
+	for _, shelf := range c.Shelves {
+		if !shelf.Accepts(temp) {
+			continue
+		}
+
+		if shelf.Overflow {
+			overflowShelves = append(overflowShelves, shelf)
+		} else {
+			dedicatedShelves = append(dedicatedShelves, shelf)
+		}
+	}
+
+	return append(dedicatedShelves, overflowShelves...)
+}
+
+// shelfCatalogsLock guards the order temperatures and shelf types of the
+// shelf catalogs in use, they are replaced when kitchens are reloaded.
+var shelfCatalogsLock sync.RWMutex
+
+// IsValidOrderTemp returns true if a shelf catalog in use accepts an order temperature.
+func IsValidOrderTemp(temp OrderTemp) bool {
+	shelfCatalogsLock.RLock()
+	defer shelfCatalogsLock.RUnlock()
+
+	return allOrderTemp[temp]
+}
+
+// IsValidShelfType returns true if a shelf catalog in use has a shelf type.
+func IsValidShelfType(shelfType ShelfType) bool {
+	shelfCatalogsLock.RLock()
+	defer shelfCatalogsLock.RUnlock()
+
+	return allShelfTypes[shelfType]
+}
+
+// UseShelfCatalogs makes the shelf types and order temperatures of catalogs
+// the only valid ones. It must be called before orders are handled and
+// again whenever the catalogs change.
+func UseShelfCatalogs(catalogs ...*ShelfCatalog) {
+	orderTemps := make(map[OrderTemp]bool)
+	shelfTypes := make(map[ShelfType]bool)
+	for _, catalog := range catalogs {
+		for _, shelf := range catalog.Shelves {
+			shelfTypes[shelf.Type] = true
+			for _, temp := range shelf.Temps {
+				orderTemps[temp] = true
+			}
+		}
+	}
+
+	shelfCatalogsLock.Lock()
+	defer shelfCatalogsLock.Unlock()
+
+	allOrderTemp = orderTemps
+	allShelfTypes = shelfTypes
+}
@@ -10,24 +10,28 @@ import (
 
 // ShelfOrder is an order placed on a shelf entity.
 type ShelfOrder struct {
-	UUID        guuid.UUID
-	OrderUUID   guuid.UUID
-	ShelfType   ShelfType
-	OrderStatus OrderStatus
-	Version     int
-	ExpiresAt   time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	UUID            guuid.UUID
+	OrderUUID       guuid.UUID
+	KitchenUUID     guuid.UUID
+	ParentOrderUUID guuid.UUID    // items of a parent order are only picked up together
+	Priority        OrderPriority // priority of the order, lower priority orders are evicted first
+	ShelfType       ShelfType
+	OrderStatus     OrderStatus
+	Version         int
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
-// Validate verifies that a shelf order has valid fields.
+// Validate verifies that a shelf order has valid fields. Shelves can be removed from
+// the shelf catalog while orders are stored on them, so any shelf type is accepted here
+// and ValidateShelfType checks the shelf type of new shelf orders.
 func (s *ShelfOrder) Validate() error {
 	var errorMsgs []string
 
 	// Check shelf type.
-	if _, ok := AllShelfTypes[s.ShelfType]; !ok {
-		msg := fmt.Sprintf("shelf type %s is invalid", s.ShelfType)
-		errorMsgs = append(errorMsgs, msg)
+	if s.ShelfType == "" {
+		errorMsgs = append(errorMsgs, "shelf type is required")
 	}
 
 	// Check order status.
@@ -36,6 +40,12 @@ func (s *ShelfOrder) Validate() error {
 		errorMsgs = append(errorMsgs, msg)
 	}
 
+	// Check order priority.
+	if _, ok := AllOrderPriorities[s.Priority]; !ok {
+		msg := fmt.Sprintf("order priority %s is invalid", s.Priority)
+		errorMsgs = append(errorMsgs, msg)
+	}
+
 	// If error msgs exist then we return a combination of them.
 	if len(errorMsgs) != 0 {
 		// Combine error messages if they exist.
@@ -46,13 +56,23 @@ func (s *ShelfOrder) Validate() error {
 	return nil
 }
 
+// ValidateShelfType verifies that a shelf order is on a shelf of the shelf catalog.
+func (s *ShelfOrder) ValidateShelfType() error {
+	if !IsValidShelfType(s.ShelfType) {
+		return fmt.Errorf("shelf type %s is invalid", s.ShelfType)
+	}
+
+	return nil
+}
+
 // String returns a prettified string representation of an order.
 func (s *ShelfOrder) String() string {
 	shelfOrderString := fmt.Sprintf("ShelfType: %s, OrderStatus: %s", s.ShelfType, s.OrderStatus)
 	return shelfOrderString
 }
 
-// ShelfType is the type of shelf to hold the food.
+// ShelfType is the type of shelf to hold the food,
+// valid shelf types come from the shelf catalog.
 type ShelfType string
 
 var (
@@ -66,10 +86,11 @@ var (
 	OverflowShelf = ShelfType("overflow")
 )
 
-// AllShelfTypes is all shelf types
+// allShelfTypes is all shelf types
 // and is used to verify if a shelf type is valid or not.
 // We use a hashmap for O(1) look up.
-var AllShelfTypes = map[ShelfType]bool{
+// It is replaced by the shelf types of the shelf catalogs in use, see IsValidShelfType.
+var allShelfTypes = map[ShelfType]bool{
 	HotShelf:      true,
 	ColdShelf:     true,
 	FrozenShelf:   true,
@@ -77,22 +98,47 @@ var AllShelfTypes = map[ShelfType]bool{
 }
 
 // OrderStatus is order status enum.
+// Allowed transitions between statuses are defined in order_event.go.
 type OrderStatus string
 
 var (
-	// OrderStatusReadyForPickup is for when an order is ready for picked up.
+	// OrderStatusReceived is for when an order is stored but not queued yet.
+	OrderStatusReceived = OrderStatus("received")
+	// OrderStatusScheduled is for when an order is held off of the order queue until it is released.
+	OrderStatusScheduled = OrderStatus("scheduled")
+	// OrderStatusQueued is for when an order is waiting on the order queue.
+	OrderStatusQueued = OrderStatus("queued")
+	// OrderStatusCooking is for when an order is being prepared at a cooking station.
+	OrderStatusCooking = OrderStatus("cooking")
+	// OrderStatusReadyForPickup is for when an order is on a shelf and ready for pick up.
 	OrderStatusReadyForPickup = OrderStatus("ready_for_pickup")
 	// OrderStatusPickedUp is for when an order is picked up.
 	OrderStatusPickedUp = OrderStatus("picked_up")
+	// OrderStatusOutForDelivery is for when a driver has left the kitchen with an order.
+	OrderStatusOutForDelivery = OrderStatus("out_for_delivery")
+	// OrderStatusDelivered is for when an order is dropped off to a customer.
+	OrderStatusDelivered = OrderStatus("delivered")
 	// OrderStatusWasted is for when an order is dropped as waste after TTL has expired.
 	OrderStatusWasted = OrderStatus("wasted")
+	// OrderStatusCancelled is for when a customer cancels an order.
+	OrderStatusCancelled = OrderStatus("cancelled")
+	// OrderStatusEvicted is for when an order is dropped by the kitchen to make room.
+	OrderStatusEvicted = OrderStatus("evicted")
 )
 
 // AllOrderStatuses holds all order statuses
 // and is used for validation prior to insertion.
 // We use a hashmap for O(1) look up.
 var AllOrderStatuses = map[OrderStatus]bool{
+	OrderStatusReceived:       true,
+	OrderStatusScheduled:      true,
+	OrderStatusQueued:         true,
+	OrderStatusCooking:        true,
 	OrderStatusReadyForPickup: true,
-	OrderStatusWasted:         true,
 	OrderStatusPickedUp:       true,
+	OrderStatusOutForDelivery: true,
+	OrderStatusDelivered:      true,
+	OrderStatusWasted:         true,
+	OrderStatusCancelled:      true,
+	OrderStatusEvicted:        true,
 }
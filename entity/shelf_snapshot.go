@@ -0,0 +1,157 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShelvedOrder is an order together with its shelf order.
+type ShelvedOrder struct {
+	Order      Order
+	ShelfOrder ShelfOrder
+}
+
+// ShelfOrderFilter narrows the shelf orders returned by a shelf query,
+// empty fields match everything.
+type ShelfOrderFilter struct {
+	ShelfType ShelfType
+	Temps     []OrderTemp
+	Statuses  []OrderStatus
+}
+
+// ShelfSnapshot is what is on a shelf at a point in time.
+type ShelfSnapshot struct {
+	Shelf     Shelf
+	Occupancy int // orders ready for pickup taking up shelf space
+	Orders    []ShelfSnapshotOrder
+	TakenAt   time.Time
+}
+
+// ShelfSnapshotOrder is an order on a shelf with its value at the time of a snapshot.
+type ShelfSnapshotOrder struct {
+	ShelvedOrder
+	Value           float64 // value of the order at snapshot time
+	NormalizedValue float64 // value relative to shelf life, ex: 1.0 is fresh
+}
+
+// NewShelfSnapshot returns a snapshot of a shelf that ages its orders
+// with the shelf's decay modifier from when they were placed on the shelf.
+func NewShelfSnapshot(shelf Shelf, occupancy int, shelvedOrders []*ShelvedOrder, takenAt time.Time) ShelfSnapshot {
+	snapshot := ShelfSnapshot{
+		Shelf:     shelf,
+		Occupancy: occupancy,
+		Orders:    make([]ShelfSnapshotOrder, 0, len(shelvedOrders)),
+		TakenAt:   takenAt,
+	}
+
+	for _, shelvedOrder := range shelvedOrders {
+		orderAge := takenAt.Sub(shelvedOrder.ShelfOrder.CreatedAt)
+		value := shelvedOrder.Order.GetValueOnShelf(orderAge, shelf.DecayModifier)
+
+		normalizedValue := 0.0
+		if shelvedOrder.Order.ShelfLife != 0 {
+			normalizedValue = value / float64(shelvedOrder.Order.ShelfLife)
+		}
+
+		snapshot.Orders = append(snapshot.Orders, ShelfSnapshotOrder{
+			ShelvedOrder:    *shelvedOrder,
+			Value:           value,
+			NormalizedValue: normalizedValue,
+		})
+	}
+
+	return snapshot
+}
+
+// GetAvailable returns how many more orders fit on the shelf.
+func (s *ShelfSnapshot) GetAvailable() int {
+	if s.Occupancy >= s.Shelf.Capacity {
+		return 0
+	}
+
+	return s.Shelf.Capacity - s.Occupancy
+}
+
+// Sort orders the orders of a snapshot.
+func (s *ShelfSnapshot) Sort(sortOrder ShelfSortOrder) {
+	less := shelfSortFields[sortOrder.Field]
+	sort.SliceStable(s.Orders, func(i, j int) bool {
+		if sortOrder.Descending {
+			return less(s.Orders[j], s.Orders[i])
+		}
+		return less(s.Orders[i], s.Orders[j])
+	})
+}
+
+// String returns a prettified string representation of a shelf snapshot.
+func (s *ShelfSnapshot) String() string {
+	shelfSnapshotString := fmt.Sprintf(
+		"ShelfType: %s, Capacity: %d, Occupancy: %d, Orders: %d", s.Shelf.Type, s.Shelf.Capacity, s.Occupancy, len(s.Orders))
+	return shelfSnapshotString
+}
+
+// ShelfSortField is a field orders of a shelf snapshot are sorted by.
+type ShelfSortField string
+
+var (
+	// ShelfSortByExpiresAt sorts orders that expire soonest first.
+	ShelfSortByExpiresAt = ShelfSortField("expiresAt")
+	// ShelfSortByValue sorts orders with the lowest value first.
+	ShelfSortByValue = ShelfSortField("value")
+	// ShelfSortByName sorts orders by name.
+	ShelfSortByName = ShelfSortField("name")
+	// ShelfSortByTemp sorts orders by temperature.
+	ShelfSortByTemp = ShelfSortField("temp")
+	// ShelfSortByStatus sorts orders by status.
+	ShelfSortByStatus = ShelfSortField("status")
+	// ShelfSortByPlacedAt sorts orders placed on the shelf earliest first.
+	ShelfSortByPlacedAt = ShelfSortField("placedAt")
+)
+
+// shelfSortFields holds how orders compare for every sort field.
+var shelfSortFields = map[ShelfSortField]func(a, b ShelfSnapshotOrder) bool{
+	ShelfSortByExpiresAt: func(a, b ShelfSnapshotOrder) bool {
+		return a.ShelfOrder.ExpiresAt.Before(b.ShelfOrder.ExpiresAt)
+	},
+	ShelfSortByValue: func(a, b ShelfSnapshotOrder) bool {
+		return a.Value < b.Value
+	},
+	ShelfSortByName: func(a, b ShelfSnapshotOrder) bool {
+		return a.Order.Name < b.Order.Name
+	},
+	ShelfSortByTemp: func(a, b ShelfSnapshotOrder) bool {
+		return a.Order.Temp < b.Order.Temp
+	},
+	ShelfSortByStatus: func(a, b ShelfSnapshotOrder) bool {
+		return a.ShelfOrder.OrderStatus < b.ShelfOrder.OrderStatus
+	},
+	ShelfSortByPlacedAt: func(a, b ShelfSnapshotOrder) bool {
+		return a.ShelfOrder.CreatedAt.Before(b.ShelfOrder.CreatedAt)
+	},
+}
+
+// ShelfSortOrder is how orders of a shelf snapshot are sorted.
+type ShelfSortOrder struct {
+	Field      ShelfSortField
+	Descending bool
+}
+
+// DefaultShelfSortOrder sorts orders that expire soonest first.
+var DefaultShelfSortOrder = ShelfSortOrder{Field: ShelfSortByExpiresAt}
+
+// ParseShelfSortOrder parses a sort order, a leading "-" sorts
+// in descending order, ex: "-value" sorts the freshest orders first.
+func ParseShelfSortOrder(sortOrder string) (ShelfSortOrder, error) {
+	if sortOrder == "" {
+		return DefaultShelfSortOrder, nil
+	}
+
+	field := ShelfSortField(strings.TrimPrefix(sortOrder, "-"))
+	if _, ok := shelfSortFields[field]; !ok {
+		return ShelfSortOrder{}, fmt.Errorf("sort field %s is invalid", field)
+	}
+
+	return ShelfSortOrder{Field: field, Descending: strings.HasPrefix(sortOrder, "-")}, nil
+}
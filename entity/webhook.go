@@ -0,0 +1,175 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// WebhookSubscription is a partner endpoint called back when orders change status.
+type WebhookSubscription struct {
+	UUID        guuid.UUID
+	URL         string      // ex: "https://partner.example.com/callbacks"
+	Secret      string      // signs every delivery so the partner can verify it came from us
+	KitchenUUID guuid.UUID  // only events of this kitchen, every kitchen if unset
+	EventTypes  []EventType // only these event types, every event type if empty
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Validate verifies that a webhook subscription has valid fields.
+func (w *WebhookSubscription) Validate() error {
+	callbackURL, err := url.Parse(w.URL)
+	if err != nil || (callbackURL.Scheme != "http" && callbackURL.Scheme != "https") || callbackURL.Host == "" {
+		return errors.Wrapf(exception.ErrInvalidInput, "url must be an absolute http or https url, url: %s", w.URL)
+	}
+
+	if strings.TrimSpace(w.Secret) == "" {
+		return errors.Wrap(exception.ErrInvalidInput, "secret is required")
+	}
+
+	for _, eventType := range w.EventTypes {
+		if !AllEventTypes[eventType] {
+			return errors.Wrapf(exception.ErrInvalidInput, "event type is invalid, event type: %s", eventType)
+		}
+	}
+
+	return nil
+}
+
+// Matches returns true if an event is delivered to the subscription.
+func (w *WebhookSubscription) Matches(event Event) bool {
+	nullUUID := guuid.NullUUID{}
+	if w.KitchenUUID != nullUUID.UUID && w.KitchenUUID != event.KitchenUUID {
+		return false
+	}
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+
+	for _, eventType := range w.EventTypes {
+		if eventType == event.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns a prettified string representation of a webhook subscription, without its secret.
+func (w *WebhookSubscription) String() string {
+	webhookSubscriptionString := fmt.Sprintf(
+		"UUID: %s, URL: %s, KitchenUUID: %s, EventTypes: %v", w.UUID, w.URL, w.KitchenUUID, w.EventTypes)
+	return webhookSubscriptionString
+}
+
+// WebhookDelivery is an event sent, or to be sent, to a webhook subscription.
+// Deliveries are kept once they are done so they make up the delivery log.
+type WebhookDelivery struct {
+	UUID             guuid.UUID
+	SubscriptionUUID guuid.UUID
+	EventType        EventType
+	OrderUUID        guuid.UUID
+	Payload          string // JSON body posted to the subscription's url
+	Status           WebhookDeliveryStatus
+	Attempts         int
+	NextAttemptAt    time.Time // when a pending delivery is attempted next
+	LastStatusCode   int       // HTTP status code of the last attempt, 0 if there was no response
+	LastError        string    // why the last attempt failed
+	LastAttemptAt    time.Time // zero until the first attempt
+	DeliveredAt      time.Time // zero until the delivery succeeds
+	Version          int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// NewWebhookDelivery returns a pending delivery of an event's payload to a subscription.
+// The delivery uuid is derived from the event's dedup ID, so an event relayed more than once
+// is only delivered once to each subscription.
+func NewWebhookDelivery(subscriptionUUID guuid.UUID, event Event, dedupID guuid.UUID, payload string, now time.Time) WebhookDelivery {
+	return WebhookDelivery{
+		UUID:             guuid.NewV5(dedupID, subscriptionUUID.String()),
+		SubscriptionUUID: subscriptionUUID,
+		EventType:        event.Type,
+		OrderUUID:        event.OrderUUID,
+		Payload:          payload,
+		Status:           WebhookDeliveryStatusPending,
+		NextAttemptAt:    now,
+		Version:          0,
+	}
+}
+
+// Replay returns a new pending delivery of the same payload, the original delivery is kept in the log.
+func (w *WebhookDelivery) Replay(now time.Time) WebhookDelivery {
+	return WebhookDelivery{
+		UUID:             guuid.NewV4(),
+		SubscriptionUUID: w.SubscriptionUUID,
+		EventType:        w.EventType,
+		OrderUUID:        w.OrderUUID,
+		Payload:          w.Payload,
+		Status:           WebhookDeliveryStatusPending,
+		NextAttemptAt:    now,
+		Version:          0,
+	}
+}
+
+// String returns a prettified string representation of a webhook delivery.
+func (w *WebhookDelivery) String() string {
+	webhookDeliveryString := fmt.Sprintf(
+		"UUID: %s, SubscriptionUUID: %s, EventType: %s, OrderUUID: %s, Status: %s, Attempts: %d",
+		w.UUID, w.SubscriptionUUID, w.EventType, w.OrderUUID, w.Status, w.Attempts)
+	return webhookDeliveryString
+}
+
+// WebhookDeliveryStatus is webhook delivery status enum.
+type WebhookDeliveryStatus string
+
+var (
+	// WebhookDeliveryStatusPending is for deliveries waiting on their next attempt.
+	WebhookDeliveryStatusPending = WebhookDeliveryStatus("pending")
+	// WebhookDeliveryStatusDelivered is for deliveries the subscription accepted.
+	WebhookDeliveryStatusDelivered = WebhookDeliveryStatus("delivered")
+	// WebhookDeliveryStatusFailed is for deliveries that ran out of attempts.
+	WebhookDeliveryStatusFailed = WebhookDeliveryStatus("failed")
+)
+
+// AllWebhookDeliveryStatuses holds every webhook delivery status.
+var AllWebhookDeliveryStatuses = map[WebhookDeliveryStatus]bool{
+	WebhookDeliveryStatusPending:   true,
+	WebhookDeliveryStatusDelivered: true,
+	WebhookDeliveryStatusFailed:    true,
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 of a payload sent at a unix timestamp.
+// The timestamp is signed with the payload so a captured delivery cannot be replayed later.
+func SignWebhookPayload(secret string, timestamp int64, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", timestamp, payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetWebhookBackoff returns how long to wait before the next attempt of a delivery,
+// doubling with every failed attempt up to a max.
+func GetWebhookBackoff(attempts int, initialBackoff time.Duration, maxBackoff time.Duration) time.Duration {
+	backoff := initialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
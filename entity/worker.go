@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorkerStatus holds how a supervised worker goroutine has been crashing.
+type WorkerStatus struct {
+	Name               string // ex: "order-worker-0"
+	Crashes            int    // panics recovered since the service started
+	ConsecutiveCrashes int    // panics since the worker last ran long enough to be considered stable
+	Restarts           int
+	LastCrash          string // the panic value of the last crash
+	LastCrashAt        time.Time
+	CrashLooping       bool // crashed too many times in a row
+}
+
+// String returns a prettified string representation of a worker status.
+func (w *WorkerStatus) String() string {
+	return fmt.Sprintf("Name: %s, Crashes: %d, ConsecutiveCrashes: %d, LastCrash: %s",
+		w.Name, w.Crashes, w.ConsecutiveCrashes, w.LastCrash)
+}
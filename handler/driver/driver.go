@@ -0,0 +1,370 @@
+package driver
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+const (
+	// pingInterval is how often drivers are pinged so dropped connections are noticed.
+	pingInterval = 15 * time.Second
+	// pongWait is how long a driver has to answer a ping before it is disconnected.
+	pongWait = 2 * pingInterval
+	// writeWait is how long a message can take to be written to a driver.
+	writeWait = 10 * time.Second
+)
+
+// Messages sent by driver apps.
+const (
+	driverMessageArrived = "arrived"
+)
+
+// Messages pushed to driver apps, order updates use the event type ex: "order.wasted".
+const (
+	driverMessageConnected = "connected"
+	driverMessageOrder     = "order"
+	driverMessageNotReady  = "not_ready"
+	driverMessageError     = "error"
+)
+
+// pushedEventTypes holds the events of an assigned order pushed to its driver.
+var pushedEventTypes = map[entity.EventType]bool{
+	entity.EventTypeOrderWasted:    true,
+	entity.EventTypeOrderCancelled: true,
+	entity.EventTypeOrderEvicted:   true,
+}
+
+// Handler is Driver handler interface.
+type Handler interface {
+	HandleDriverChannel(w http.ResponseWriter, r *http.Request)
+}
+
+type driverHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+	upgrader websocket.Upgrader
+}
+
+// NewHandler creates a new HTTP driver handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
+	return &driverHandler{
+		cfg:      appConfig,
+		services: services,
+		upgrader: websocket.Upgrader{
+			// Driver apps are not browsers, they authenticate with credentials instead of cookies.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// driverSession is the state of a connected driver.
+type driverSession struct {
+	driver      *entity.Driver
+	kitchenUUID guuid.UUID
+	orderUUID   guuid.UUID // order assigned to the driver, optional
+	waiting     bool       // driver arrived before their order was ready
+}
+
+// HandleDriverChannel upgrades a driver's connection to a WebSocket,
+// ex: /drivers/ws?kitchenUUID={uuid}&orderUUID={uuid}.
+// Drivers authenticate with basic auth using their uuid and token. Once they send "arrived"
+// they are pushed their order as soon as it is ready, or "not_ready" while it is not.
+// Drivers with an assigned order are also pushed when it is wasted, cancelled or evicted.
+func (d *driverHandler) HandleDriverChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	driver, err := d.authenticate(r)
+	if err != nil {
+		msg := fmt.Sprintf("failed to authenticate driver - err: %s", err)
+		log.Println(msg)
+		w.Header().Set("WWW-Authenticate", `Basic realm="drivers"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(msg))
+		return
+	}
+
+	session, err := d.newSession(r, driver)
+	if err != nil {
+		switch errors.Cause(err) {
+		case exception.ErrNotFound:
+			msg := fmt.Sprintf("order not found - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(msg))
+			return
+		case exception.ErrInvalidInput:
+			msg := fmt.Sprintf("failed to handle driver request - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(msg))
+			return
+		default:
+			msg := fmt.Sprintf("failed to connect driver - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	// Subscribe before the upgrade so no update is missed between "connected" and "arrived".
+	filter := entity.EventFilter{KitchenUUID: session.kitchenUUID, OrderUUID: session.orderUUID}
+	subscription := d.services.EventBus.Subscribe(filter, 0)
+	defer d.services.EventBus.Unsubscribe(subscription)
+
+	// The upgrader writes the error response itself.
+	conn, err := d.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("failed to upgrade driver connection - driver: %s, err: %s", driver.String(), err)
+		return
+	}
+	defer conn.Close()
+
+	log.Printf("driver connected - %s", driver.String())
+	d.serve(conn, session, subscription)
+	log.Printf("driver disconnected - %s", driver.String())
+}
+
+// authenticate returns the driver whose basic auth credentials a request holds.
+func (d *driverHandler) authenticate(r *http.Request) (*entity.Driver, error) {
+	driverUUIDStr, token, ok := r.BasicAuth()
+	if !ok {
+		return nil, errors.Wrap(exception.ErrUnauthorized, "missing driver credentials")
+	}
+
+	driverUUID, err := guuid.FromString(driverUUIDStr)
+	if err != nil {
+		return nil, errors.Wrapf(exception.ErrUnauthorized, "driver uuid is invalid - uuid: %s", driverUUIDStr)
+	}
+
+	return d.services.Driver.Authenticate(driverUUID, token)
+}
+
+// newSession maps a driver channel request to the kitchen and order the driver is picking up.
+func (d *driverHandler) newSession(r *http.Request, driver *entity.Driver) (*driverSession, error) {
+	// Drivers that do not name a kitchen pick up from the default kitchen.
+	session := &driverSession{driver: driver, kitchenUUID: entity.DefaultKitchenUUID}
+	query := r.URL.Query()
+
+	if kitchenUUIDStr := query.Get("kitchenUUID"); kitchenUUIDStr != "" {
+		kitchenUUID, err := guuid.FromString(kitchenUUIDStr)
+		if err != nil {
+			return nil, errors.Wrapf(exception.ErrInvalidInput, "kitchen uuid is invalid - uuid: %s", kitchenUUIDStr)
+		}
+		session.kitchenUUID = kitchenUUID
+	}
+
+	if orderUUIDStr := query.Get("orderUUID"); orderUUIDStr != "" {
+		orderUUID, err := guuid.FromString(orderUUIDStr)
+		if err != nil {
+			return nil, errors.Wrapf(exception.ErrInvalidInput, "order uuid is invalid - uuid: %s", orderUUIDStr)
+		}
+
+		// Assigned orders are picked up from the kitchen they were placed at.
+		order, err := d.services.Order.GetOrder(orderUUID)
+		if err != nil {
+			return nil, err
+		}
+		session.orderUUID = order.UUID
+		session.kitchenUUID = order.KitchenUUID
+	}
+
+	return session, nil
+}
+
+// serve pushes messages to a connected driver until the driver disconnects.
+// Every write happens on this go-routine, a WebSocket connection only supports one writer.
+func (d *driverHandler) serve(conn *websocket.Conn, session *driverSession, subscription *service.Subscription) {
+	messages := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+	go readMessages(conn, messages, done)
+
+	pings := time.NewTicker(pingInterval)
+	defer pings.Stop()
+
+	err := writeMessage(conn, endpoint.DriverMessageJSON{
+		Type: driverMessageConnected, OrderUUID: optionalUUIDToJSON(session.orderUUID)})
+	if err != nil {
+		return
+	}
+
+	for {
+		var err error
+
+		select {
+		case message, ok := <-messages:
+			// The driver closed the connection.
+			if !ok {
+				return
+			}
+
+			err = d.handleMessage(conn, session, message)
+		case event, ok := <-subscription.Events:
+			// The driver fell behind, it reconnects and sends "arrived" again.
+			if !ok {
+				return
+			}
+
+			err = d.handleEvent(conn, session, event)
+		case <-pings.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+		}
+
+		if err != nil {
+			log.Printf("failed to write to driver - driver: %s, err: %s", session.driver.String(), err)
+			return
+		}
+	}
+}
+
+// readMessages forwards the text messages a driver sends until the connection is closed
+// or the driver is no longer served.
+func readMessages(conn *websocket.Conn, messages chan<- string, done <-chan struct{}) {
+	defer close(messages)
+
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		messageType, content, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		select {
+		case messages <- strings.TrimSpace(string(content)):
+		case <-done:
+			return
+		}
+	}
+}
+
+// handleMessage answers a message sent by a driver.
+func (d *driverHandler) handleMessage(conn *websocket.Conn, session *driverSession, message string) error {
+	switch message {
+	case driverMessageArrived:
+		return d.pickupOrder(conn, session)
+	default:
+		return writeMessage(conn, endpoint.DriverMessageJSON{
+			Type: driverMessageError, Reason: fmt.Sprintf("unknown message %q", message)})
+	}
+}
+
+// handleEvent pushes the order events a driver cares about.
+func (d *driverHandler) handleEvent(conn *websocket.Conn, session *driverSession, event entity.Event) error {
+	nullUUID := guuid.NullUUID{}
+
+	// Drivers waiting at the kitchen try again once an order is shelved.
+	if event.Type == entity.EventTypeOrderShelved && session.waiting {
+		return d.pickupOrder(conn, session)
+	}
+
+	if session.orderUUID == nullUUID.UUID || !pushedEventTypes[event.Type] {
+		return nil
+	}
+
+	// The assigned order will never be ready.
+	session.waiting = false
+	return writeMessage(conn, endpoint.DriverMessageJSON{
+		Type: string(event.Type), OrderUUID: event.OrderUUID.String(), Reason: event.Reason})
+}
+
+// pickupOrder hands a driver their assigned order, or the next order ready at the kitchen,
+// and tells them their order is not ready yet otherwise.
+func (d *driverHandler) pickupOrder(conn *websocket.Conn, session *driverSession) error {
+	nullUUID := guuid.NullUUID{}
+
+	var order *entity.Order
+	var err error
+	if session.orderUUID != nullUUID.UUID {
+		order, err = d.services.Order.PickupOrderByUUID(session.orderUUID)
+	} else {
+		order, err = d.services.Order.PickupOrder(session.kitchenUUID)
+	}
+	if err != nil {
+		if d.isOrderPending(session, err) {
+			session.waiting = true
+			return writeMessage(conn, endpoint.DriverMessageJSON{
+				Type: driverMessageNotReady, OrderUUID: optionalUUIDToJSON(session.orderUUID), Reason: err.Error()})
+		}
+
+		session.waiting = false
+		log.Printf("driver failed to pickup order - driver: %s, err: %s", session.driver.String(), err)
+		return writeMessage(conn, endpoint.DriverMessageJSON{
+			Type: driverMessageError, OrderUUID: optionalUUIDToJSON(session.orderUUID), Reason: err.Error()})
+	}
+
+	session.waiting = false
+	log.Printf("driver picked up order successfully - driver: %s, %s", session.driver.String(), order.String())
+
+	orderJSON := mapper.OrderToDriverOrderJSON(*order)
+	return writeMessage(conn, endpoint.DriverMessageJSON{
+		Type: driverMessageOrder, OrderUUID: orderJSON.UUID, Order: &orderJSON})
+}
+
+// isOrderPending returns true if a failed pickup can succeed once an order is shelved.
+func (d *driverHandler) isOrderPending(session *driverSession, err error) bool {
+	nullUUID := guuid.NullUUID{}
+
+	// Nothing is ready at the kitchen yet.
+	if session.orderUUID == nullUUID.UUID {
+		return errors.Cause(err) == exception.ErrNotFound
+	}
+
+	// The assigned order is ready later if it is still being prepared,
+	// it is never ready once it is wasted, cancelled or already picked up.
+	if errors.Cause(err) != exception.ErrInvalidResourceState && errors.Cause(err) != exception.ErrVersionInvalid {
+		return false
+	}
+
+	orderEvents, err := d.services.Order.GetOrderEvents(session.orderUUID)
+	if err != nil || len(orderEvents) == 0 {
+		return false
+	}
+
+	switch orderEvents[len(orderEvents)-1].ToStatus {
+	case entity.OrderStatusReceived, entity.OrderStatusScheduled, entity.OrderStatusQueued, entity.OrderStatusCooking:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeMessage writes a message to a driver as JSON.
+func writeMessage(conn *websocket.Conn, message endpoint.DriverMessageJSON) error {
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteJSON(message)
+}
+
+// optionalUUIDToJSON returns an empty string for unset uuids.
+func optionalUUIDToJSON(uuid guuid.UUID) string {
+	nullUUID := guuid.NullUUID{}
+	if uuid == nullUUID.UUID {
+		return ""
+	}
+
+	return uuid.String()
+}
@@ -0,0 +1,205 @@
+package driver
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDriver holds the credentials of the driver in development.yaml.
+var testDriver = struct {
+	UUID  string
+	Token string
+}{"5b7c1e2a-9d4f-4a6b-8c3e-0f1d2e3a4b5c", "dev-driver-token"}
+
+// newTestServer serves the driver channel with real services on top of mocked repositories.
+func newTestServer(t *testing.T, orderRepo repository.OrderRepository, shelfOrderRepo repository.ShelfOrderRepository,
+	orderEventRepo repository.OrderEventRepository) (*httptest.Server, service.EventBus) {
+	cfg := config.AppConfig{}
+	err := cfg.LoadConfig("../../config/development.yaml")
+	assert.Nil(t, err)
+
+	kitchenService, err := service.NewKitchenService(cfg)
+	assert.Nil(t, err)
+	driverService, err := service.NewDriverService(cfg)
+	assert.Nil(t, err)
+
+	eventBus := service.NewEventBus(cfg)
+	services := service.Services{
+		Kitchen:  kitchenService,
+		EventBus: eventBus,
+		Order:    service.NewOrderService(cfg, kitchenService, orderRepo, shelfOrderRepo, orderEventRepo, eventBus),
+		Driver:   driverService,
+	}
+
+	handler := NewHandler(cfg, services)
+	return httptest.NewServer(http.HandlerFunc(handler.HandleDriverChannel)), eventBus
+}
+
+// dialDriver connects a local driver client to the driver channel.
+func dialDriver(server *httptest.Server, query string, token string) (*websocket.Conn, *http.Response, error) {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/drivers/ws" + query
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(testDriver.UUID + ":" + token))
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+credentials)
+
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+// readDriverMessage reads the next message pushed to a driver client.
+func readDriverMessage(t *testing.T, conn *websocket.Conn) endpoint.DriverMessageJSON {
+	message := endpoint.DriverMessageJSON{}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	err := conn.ReadJSON(&message)
+	assert.Nil(t, err)
+
+	return message
+}
+
+func TestHandleDriverChannel_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server, _ := newTestServer(t, repository.NewMockOrderRepository(ctrl),
+		repository.NewMockShelfOrderRepository(ctrl), repository.NewMockOrderEventRepository(ctrl))
+	defer server.Close()
+
+	conn, resp, err := dialDriver(server, "", "wrong-token")
+	assert.Nil(t, conn)
+	assert.Equal(t, websocket.ErrBadHandshake, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestHandleDriverChannel_ArrivedBeforeOrderIsReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := repository.NewMockOrderRepository(ctrl)
+	shelfOrderRepo := repository.NewMockShelfOrderRepository(ctrl)
+	server, eventBus := newTestServer(t, orderRepo, shelfOrderRepo, repository.NewMockOrderEventRepository(ctrl))
+	defer server.Close()
+
+	order := entity.Order{
+		UUID:        guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		Name:        "Cheese Pizza",
+		Temp:        entity.OrderTempHot,
+	}
+	shelfOrder := entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   order.UUID,
+		KitchenUUID: entity.DefaultKitchenUUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+	}
+
+	gomock.InOrder(
+		shelfOrderRepo.EXPECT().GetOpenOrder(entity.DefaultKitchenUUID).Return(
+			nil, errors.Wrap(exception.ErrNotFound, "no orders on shelves")),
+		shelfOrderRepo.EXPECT().GetOpenOrder(entity.DefaultKitchenUUID).Return(&shelfOrder, nil),
+	)
+	shelfOrderRepo.EXPECT().UpdateOrderStatus(shelfOrder, gomock.Any()).Return(nil)
+	orderRepo.EXPECT().GetOrder(order.UUID).Return(&order, nil)
+
+	conn, _, err := dialDriver(server, "", testDriver.Token)
+	assert.Nil(t, err)
+	defer conn.Close()
+	assert.Equal(t, driverMessageConnected, readDriverMessage(t, conn).Type)
+
+	err = conn.WriteMessage(websocket.TextMessage, []byte(driverMessageArrived))
+	assert.Nil(t, err)
+	assert.Equal(t, driverMessageNotReady, readDriverMessage(t, conn).Type)
+
+	// The driver is pushed the order as soon as one is shelved.
+	eventBus.Publish(entity.Event{
+		Type:        entity.EventTypeOrderShelved,
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderUUID:   order.UUID,
+		ToStatus:    entity.OrderStatusReadyForPickup,
+	})
+
+	message := readDriverMessage(t, conn)
+	assert.Equal(t, driverMessageOrder, message.Type)
+	assert.Equal(t, order.UUID.String(), message.OrderUUID)
+	assert.Equal(t, &endpoint.DriverOrderJSON{
+		UUID:        order.UUID.String(),
+		KitchenUUID: entity.DefaultKitchenUUID.String(),
+		Name:        "Cheese Pizza",
+		Temp:        "hot",
+		Priority:    "standard",
+	}, message.Order)
+}
+
+func TestHandleDriverChannel_AssignedOrderCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := repository.NewMockOrderRepository(ctrl)
+	shelfOrderRepo := repository.NewMockShelfOrderRepository(ctrl)
+	orderEventRepo := repository.NewMockOrderEventRepository(ctrl)
+	server, eventBus := newTestServer(t, orderRepo, shelfOrderRepo, orderEventRepo)
+	defer server.Close()
+
+	order := entity.Order{UUID: guuid.NewV4(), KitchenUUID: entity.DefaultKitchenUUID, Temp: entity.OrderTempHot}
+	orderRepo.EXPECT().GetOrder(order.UUID).Return(&order, nil).AnyTimes()
+
+	// The assigned order is still cooking when the driver arrives.
+	shelfOrderRepo.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(
+		nil, errors.Wrap(exception.ErrNotFound, "order is not on a shelf"))
+	orderEventRepo.EXPECT().GetOrderEvents(order.UUID).Return([]*entity.OrderEvent{
+		{OrderUUID: order.UUID, ToStatus: entity.OrderStatusReceived},
+		{OrderUUID: order.UUID, FromStatus: entity.OrderStatusReceived, ToStatus: entity.OrderStatusQueued},
+		{OrderUUID: order.UUID, FromStatus: entity.OrderStatusQueued, ToStatus: entity.OrderStatusCooking},
+	}, nil)
+
+	conn, _, err := dialDriver(server, "?orderUUID="+order.UUID.String(), testDriver.Token)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	message := readDriverMessage(t, conn)
+	assert.Equal(t, driverMessageConnected, message.Type)
+	assert.Equal(t, order.UUID.String(), message.OrderUUID)
+
+	err = conn.WriteMessage(websocket.TextMessage, []byte(driverMessageArrived))
+	assert.Nil(t, err)
+	assert.Equal(t, driverMessageNotReady, readDriverMessage(t, conn).Type)
+
+	// Only updates of the assigned order are pushed.
+	eventBus.Publish(entity.Event{
+		Type:        entity.EventTypeOrderCancelled,
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderUUID:   guuid.NewV4(),
+		ToStatus:    entity.OrderStatusCancelled,
+	})
+	eventBus.Publish(entity.Event{
+		Type:        entity.EventTypeOrderCancelled,
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderUUID:   order.UUID,
+		FromStatus:  entity.OrderStatusCooking,
+		ToStatus:    entity.OrderStatusCancelled,
+		Reason:      "cancelled by customer",
+	})
+
+	assert.Equal(t, endpoint.DriverMessageJSON{
+		Type:      string(entity.EventTypeOrderCancelled),
+		OrderUUID: order.UUID.String(),
+		Reason:    "cancelled by customer",
+	}, readDriverMessage(t, conn))
+}
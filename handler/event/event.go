@@ -0,0 +1,160 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// keepAliveInterval is how often a comment is sent on an idle stream
+// so proxies do not close the connection.
+const keepAliveInterval = 15 * time.Second
+
+// Handler is Event handler interface.
+type Handler interface {
+	StreamEvents(w http.ResponseWriter, r *http.Request)
+}
+
+type eventHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+}
+
+// NewHandler creates a new HTTP event handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
+	return &eventHandler{
+		cfg:      appConfig,
+		services: services,
+	}
+}
+
+// StreamEvents streams order events as Server-Sent Events, ex: /events?kitchenUUID={uuid}&orderUUID={uuid}.
+// Clients that reconnect with a Last-Event-ID header, or a lastEventId query parameter,
+// are first sent the recent events they missed.
+func (e *eventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		msg := "streaming is not supported"
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	filter, lastEventID, err := e.extractEventQuery(r)
+	if err != nil {
+		msg := fmt.Sprintf("failed to handle events request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	subscription := e.services.EventBus.Subscribe(filter, lastEventID)
+	defer e.services.EventBus.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for _, event := range subscription.Replay {
+		err = writeEvent(w, event)
+		if err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			// The client fell behind, it resumes from its last event when it reconnects.
+			if !ok {
+				return
+			}
+
+			err = writeEvent(w, event)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// extractEventQuery maps an events request to an event filter and the last event the client saw.
+func (e *eventHandler) extractEventQuery(r *http.Request) (entity.EventFilter, uint64, error) {
+	filter := entity.EventFilter{}
+	query := r.URL.Query()
+
+	if kitchenUUIDStr := query.Get("kitchenUUID"); kitchenUUIDStr != "" {
+		kitchenUUID, err := guuid.FromString(kitchenUUIDStr)
+		if err != nil {
+			return filter, 0, fmt.Errorf("kitchen uuid is invalid - uuid: %s", kitchenUUIDStr)
+		}
+		filter.KitchenUUID = kitchenUUID
+	}
+
+	if orderUUIDStr := query.Get("orderUUID"); orderUUIDStr != "" {
+		orderUUID, err := guuid.FromString(orderUUIDStr)
+		if err != nil {
+			return filter, 0, fmt.Errorf("order uuid is invalid - uuid: %s", orderUUIDStr)
+		}
+		filter.OrderUUID = orderUUID
+	}
+
+	// Browsers resend the id of the last event they received when they reconnect.
+	lastEventIDStr := r.Header.Get("Last-Event-ID")
+	if lastEventIDStr == "" {
+		lastEventIDStr = query.Get("lastEventId")
+	}
+	if lastEventIDStr == "" {
+		return filter, 0, nil
+	}
+
+	lastEventID, err := strconv.ParseUint(lastEventIDStr, 10, 64)
+	if err != nil {
+		return filter, 0, fmt.Errorf("last event id must be a positive integer, got %q", lastEventIDStr)
+	}
+
+	return filter, lastEventID, nil
+}
+
+// writeEvent writes an event in the Server-Sent Events format.
+func writeEvent(w http.ResponseWriter, event entity.Event) error {
+	content, err := json.Marshal(mapper.EventToJSON(event))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, content)
+	return err
+}
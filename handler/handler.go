@@ -3,24 +3,56 @@ package handler
 import (
 	"github.com/kitchen-delivery/config"
 	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/handler/driver"
+	"github.com/kitchen-delivery/handler/event"
 	"github.com/kitchen-delivery/handler/health"
+	"github.com/kitchen-delivery/handler/job"
+	"github.com/kitchen-delivery/handler/menu"
 	"github.com/kitchen-delivery/handler/order"
+	"github.com/kitchen-delivery/handler/parentorder"
+	"github.com/kitchen-delivery/handler/pickup"
+	"github.com/kitchen-delivery/handler/shelf"
+	"github.com/kitchen-delivery/handler/webhook"
 	"github.com/kitchen-delivery/service"
 )
 
 // Handlers holds HTTP handlers.
 type Handlers struct {
-	Health health.Handler
-	Order  order.Handler
+	Health      health.Handler
+	Order       order.Handler
+	Pickup      pickup.Handler
+	Menu        menu.Handler
+	ParentOrder parentorder.Handler
+	Shelf       shelf.Handler
+	Event       event.Handler
+	Driver      driver.Handler
+	Webhook     webhook.Handler
+	Job         job.Handler
 }
 
 // NewHandlers returns new HTTP handlers.
 func NewHandlers(cfg config.AppConfig, services service.Services, queues *entity.Queues) (*Handlers, error) {
-	healthHandler := health.NewHandler(cfg)
+	healthHandler := health.NewHandler(cfg, services)
 	orderHandler := order.NewHandler(cfg, services, queues)
+	pickupHandler := pickup.NewHandler(cfg, services)
+	menuHandler := menu.NewHandler(cfg, services)
+	parentOrderHandler := parentorder.NewHandler(cfg, services, queues)
+	shelfHandler := shelf.NewHandler(cfg, services)
+	eventHandler := event.NewHandler(cfg, services)
+	driverHandler := driver.NewHandler(cfg, services)
+	webhookHandler := webhook.NewHandler(cfg, services)
+	jobHandler := job.NewHandler(cfg, services)
 
 	return &Handlers{
-		Health: healthHandler,
-		Order:  orderHandler,
+		Health:      healthHandler,
+		Order:       orderHandler,
+		Pickup:      pickupHandler,
+		Menu:        menuHandler,
+		ParentOrder: parentOrderHandler,
+		Shelf:       shelfHandler,
+		Event:       eventHandler,
+		Driver:      driverHandler,
+		Webhook:     webhookHandler,
+		Job:         jobHandler,
 	}, nil
 }
@@ -1,9 +1,13 @@
 package health
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/service"
 )
 
 // Handler is Health handler interface.
@@ -13,21 +17,41 @@ type Handler interface {
 	// TODO: CheckCreateAndPickupOrder()
 	// Simulate launches a Kitchen Delivery system simulation.
 	Simulate(w http.ResponseWriter, r *http.Request)
+	// SimulationReport returns courier and food wait times per pickup strategy.
+	SimulationReport(w http.ResponseWriter, r *http.Request)
 }
 
 type healthHandler struct {
-	cfg config.AppConfig
+	cfg      config.AppConfig
+	services service.Services
 }
 
 // NewHandler creates a new HTTP health handler instance.
-func NewHandler(appConfig config.AppConfig) Handler {
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
 	return &healthHandler{
-		cfg: appConfig,
+		cfg:      appConfig,
+		services: services,
 	}
 }
 
-// CheckHealth checks service health and returns 200 OK if reachable.
+// CheckHealth checks service health and returns 200 OK if reachable,
+// or 503 if any worker keeps crashing.
 func (h *healthHandler) CheckHealth(w http.ResponseWriter, r *http.Request) {
+	crashLoopingWorkers := h.services.Worker.GetCrashLoopingWorkers()
+	if len(crashLoopingWorkers) > 0 {
+		var workers []string
+		for _, workerStatus := range crashLoopingWorkers {
+			workers = append(workers, fmt.Sprintf("%s (%d crashes in a row, last: %s)",
+				workerStatus.Name, workerStatus.ConsecutiveCrashes, workerStatus.LastCrash))
+		}
+
+		msg := fmt.Sprintf("workers are crash looping - %s", strings.Join(workers, ", "))
+		log.Println(msg)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(msg))
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
@@ -8,13 +8,24 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/kitchen-delivery/entity/endpoint"
 	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+
 	stats "github.com/r0fls/gostats"
+	guuid "github.com/satori/go.uuid"
 )
 
+// courierMaxWait is how long a courier waits at the kitchen
+// for an order before giving up.
+const courierMaxWait = 30 * time.Second
+
+// prepReportInterval is how often the simulation logs the prep pipeline of each kitchen.
+const prepReportInterval = 5 * time.Second
+
 // Simulate launches a Kitchen Delivery system simulation.
 func (h *healthHandler) Simulate(w http.ResponseWriter, r *http.Request) {
 	log.Printf("\n\n-------- Simulation Starting ---------\n\n")
@@ -52,34 +63,86 @@ func (h *healthHandler) Simulate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Spawn thread to submit order requests asynchronously.
+	// A courier is dispatched for every order that is submitted.
 	go h.submitOrderRequests(orders)
 
-	// Spawn thread to pickup order requests asynchronously.
-	go h.sendDriversToPickupOrders()
-
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
 
-// submitOrderRequests submits orders to an orders endpoint.
+// SimulationReport returns courier and food wait times per pickup strategy.
+func (h *healthHandler) SimulationReport(w http.ResponseWriter, r *http.Request) {
+	pickupStats, err := h.services.Pickup.GetPickupStats()
+	if err != nil {
+		msg := fmt.Sprintf("failed to fetch pickup stats, err: %+v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	report, err := json.Marshal(mapper.PickupStatsToJSON(pickupStats))
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal pickup stats, err: %+v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(report)
+}
+
+// submitOrderRequests submits orders to an orders endpoint
+// and dispatches a courier for each order.
 func (h *healthHandler) submitOrderRequests(orders []endpoint.OrderJSON) {
 	// TODO: Move to job package and folder.
+	var couriers sync.WaitGroup
+
+	// Log how busy each kitchen's cooking stations are while the simulation runs.
+	done := make(chan struct{})
+	defer close(done)
+	go h.reportPrepPipeline(done)
+
 	// Iterate over order requests and submit request.
 	for _, order := range orders {
 		time.Sleep(250 * time.Millisecond) // rate of submitting an order is 1/4th a second
 
-		err := h.submitOrderRequest(order)
+		orderUUID, err := h.submitOrderRequest(order)
 		if err != nil {
 			// We fail open here as we don't want an error in
 			// the creation of one order to stop the creation of subsequent ones.
 			msg := fmt.Sprintf("failed to submit order request err: %+v", err)
 			log.Println(msg)
+			continue
 		}
+
+		// Spawn thread to send a courier to pickup the order asynchronously.
+		couriers.Add(1)
+		go func(orderUUID string) {
+			defer couriers.Done()
+			h.dispatchCourier(orderUUID)
+		}(orderUUID)
+	}
+
+	// Wait for every courier to either pickup an order or give up.
+	couriers.Wait()
+
+	pickupStats, err := h.services.Pickup.GetPickupStats()
+	if err != nil {
+		log.Printf("failed to fetch pickup stats, err: %+v", err)
+	}
+	for _, stats := range pickupStats {
+		log.Printf("strategy: %s, pickups: %d, avg food wait: %s, avg courier wait: %s",
+			stats.Strategy, stats.NumOfPickups, stats.AvgFoodWaitTime, stats.AvgCourierWaitTime)
 	}
+
+	log.Printf("\n\n-------- Simulation Over ---------\n\n")
 }
 
-// submitOrderRequest submits an order creation HTTP request.
-func (h *healthHandler) submitOrderRequest(order endpoint.OrderJSON) error {
+// submitOrderRequest submits an order creation HTTP request
+// and returns the uuid of the created order.
+func (h *healthHandler) submitOrderRequest(order endpoint.OrderJSON) (string, error) {
 	// Prepare HTTP Post request by stringifying URL values.
 	shelfLife := fmt.Sprintf("%d", order.ShelfLife)   // safely convert int to str int
 	decayRate := fmt.Sprintf("%.2f", order.DecayRate) // keep float to 2 decimal places ex: 2.56
@@ -90,52 +153,93 @@ func (h *healthHandler) submitOrderRequest(order endpoint.OrderJSON) error {
 		"shelfLife": {shelfLife},
 		"decayRate": {decayRate},
 	}
-	resp, err := http.PostForm("http://localhost:8080/order", formData)
+	// Orders without a prep time are cooked for their kitchen's prep time.
+	if order.PrepTime > 0 {
+		formData.Set("prepTime", fmt.Sprintf("%d", order.PrepTime))
+	}
+	if order.Priority != "" {
+		formData.Set("priority", order.Priority)
+	}
+	resp, err := http.PostForm(h.cfg.HTTP.GetBaseURL()+"/order", formData)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body, err: %+v", err)
+	}
+
 	// If request was not successful then return the content
 	// of the response as the error.
 	if resp.StatusCode != http.StatusOK {
-		content, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response body, err: %+v", err)
-		}
-
-		return fmt.Errorf("%s", string(content))
+		return "", fmt.Errorf("%s", string(content))
 	}
 
-	return nil
+	return string(content), nil
 }
 
-// sendDriversToPickupOrders sends drivers to pickup orders.
-func (h *healthHandler) sendDriversToPickupOrders() {
+// reportPrepPipeline logs the cooking stations in use at every kitchen until done is closed.
+func (h *healthHandler) reportPrepPipeline(done chan struct{}) {
+	ticker := time.NewTicker(prepReportInterval)
+	defer ticker.Stop()
+
 	for {
-		// Send drivers using a Poisson distribution.
-		p := stats.Poisson(h.cfg.Pickup.Mean)
-		numOfSeconds := p.Random()
-		time.Sleep(time.Duration(numOfSeconds) * time.Second)
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
 
-		err := h.sendDriverToPickupOrder()
-		if err != nil {
-			// No more orders to pick up.
-			if err == exception.ErrNotFound {
-				log.Printf("\n\n-------- Simulation Over ---------\n\n")
-				break
-			}
-			// We fail open here as we don't want an error in
-			// the creation of one order to stop the creation of subsequent ones.
-			msg := fmt.Sprintf("driver failed to pickup an order: %s", err)
+		for _, kitchen := range h.services.Kitchen.GetKitchens() {
+			log.Printf("kitchen: %s, cooking: %d, stations: %d",
+				kitchen.Name, h.services.Kitchen.CountOrdersCooking(kitchen.UUID), kitchen.CookingStations)
+		}
+	}
+}
+
+// dispatchCourier sends a courier to pickup an order.
+func (h *healthHandler) dispatchCourier(orderUUID string) {
+	// Couriers arrive using a Poisson distribution.
+	p := stats.Poisson(h.cfg.Pickup.Mean)
+	numOfSeconds := p.Random()
+	time.Sleep(time.Duration(numOfSeconds) * time.Second)
+
+	courierUUID := guuid.NewV4().String()
+	arrivedAt := time.Now()
+
+	// Courier waits at the kitchen until an order is handed to them.
+	for time.Since(arrivedAt) < courierMaxWait {
+		err := h.sendCourierToPickupOrder(courierUUID, orderUUID, arrivedAt)
+		if err == nil {
+			return
+		}
+
+		switch err {
+		case exception.ErrNotFound, exception.ErrVersionInvalid:
+			// Order is not ready yet or another courier took it, so we wait and retry.
+			time.Sleep(time.Second)
+		default:
+			msg := fmt.Sprintf("courier failed to pickup an order: %s", err)
 			log.Println(msg)
+			return
 		}
 	}
+
+	log.Printf("courier %s gave up waiting for order %s", courierUUID, orderUUID)
 }
 
-// submitOrderRequest submits an order creation HTTP request.
-func (h *healthHandler) sendDriverToPickupOrder() error {
-	resp, err := http.Get("http://localhost:8080/order")
+// sendCourierToPickupOrder submits a pickup order HTTP request.
+func (h *healthHandler) sendCourierToPickupOrder(courierUUID string, orderUUID string, arrivedAt time.Time) error {
+	arrivedAtMs := fmt.Sprintf("%d", arrivedAt.UnixNano()/int64(time.Millisecond))
+
+	formData := url.Values{
+		"courierUUID": {courierUUID},
+		"orderUUID":   {orderUUID},
+		"arrivedAt":   {arrivedAtMs},
+	}
+	resp, err := http.PostForm(h.cfg.HTTP.GetBaseURL()+"/pickup", formData)
 	if err != nil {
 		return err
 	}
@@ -155,6 +259,8 @@ func (h *healthHandler) sendDriverToPickupOrder() error {
 		return nil
 	case http.StatusNotFound:
 		return exception.ErrNotFound
+	case http.StatusConflict:
+		return exception.ErrVersionInvalid
 	default:
 		return fmt.Errorf("%s", content)
 	}
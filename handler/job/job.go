@@ -0,0 +1,82 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+)
+
+// Handler is Job handler interface.
+type Handler interface {
+	HandleJobs(w http.ResponseWriter, r *http.Request)
+}
+
+type jobHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+}
+
+// NewHandler creates a new HTTP job handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
+	return &jobHandler{
+		cfg:      appConfig,
+		services: services,
+	}
+}
+
+// HandleJobs returns how this instance's scheduled jobs have been running, ex: /jobs or /jobs/{name}.
+func (j *jobHandler) HandleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// "/jobs/{name}" => "{name}"
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/jobs"), "/")
+	if name == "" {
+		j.writeJSON(w, mapper.JobStatusesToJSON(j.services.Job.GetJobStatuses()))
+		return
+	}
+
+	jobStatus, err := j.services.Job.GetJobStatus(name)
+	if err != nil {
+		switch errors.Cause(err) {
+		case exception.ErrNotFound:
+			msg := fmt.Sprintf("job not found - err: %s", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(msg))
+		default:
+			msg := fmt.Sprintf("failed to fetch job - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(msg))
+		}
+		return
+	}
+
+	j.writeJSON(w, mapper.JobStatusToJSON(*jobStatus))
+}
+
+func (j *jobHandler) writeJSON(w http.ResponseWriter, response interface{}) {
+	content, err := json.Marshal(response)
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal jobs - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
@@ -0,0 +1,214 @@
+package menu
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// Handler is Menu handler interface.
+type Handler interface {
+	HandleMenu(w http.ResponseWriter, r *http.Request)
+	HandleMenuItem(w http.ResponseWriter, r *http.Request)
+}
+
+type menuHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+}
+
+// NewHandler creates a new HTTP menu handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
+	return &menuHandler{
+		cfg:      appConfig,
+		services: services,
+	}
+}
+
+// HandleMenu either lists the menu or adds an item to it.
+func (m *menuHandler) HandleMenu(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.getMenuItems(w, r)
+	case http.MethodPost:
+		m.createMenuItem(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleMenuItem routes requests on a specific menu item, ex: /menu/{uuid}.
+func (m *menuHandler) HandleMenuItem(w http.ResponseWriter, r *http.Request) {
+	menuItemUUIDStr := strings.Trim(strings.TrimPrefix(r.URL.Path, "/menu"), "/")
+	menuItemUUID, err := guuid.FromString(menuItemUUIDStr)
+	if err != nil {
+		msg := fmt.Sprintf("menu item uuid is invalid - uuid: %s", menuItemUUIDStr)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		m.getMenuItem(w, r, menuItemUUID)
+	case http.MethodPut:
+		m.updateMenuItem(w, r, menuItemUUID)
+	case http.MethodDelete:
+		m.deleteMenuItem(w, r, menuItemUUID)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (m *menuHandler) getMenuItems(w http.ResponseWriter, r *http.Request) {
+	menuItems, err := m.services.Menu.GetMenuItems()
+	if err != nil {
+		m.writeMenuError(w, err)
+		return
+	}
+
+	m.writeJSON(w, mapper.MenuItemsToJSON(menuItems))
+}
+
+func (m *menuHandler) createMenuItem(w http.ResponseWriter, r *http.Request) {
+	menuItem, err := m.extractMenuItem(r, "")
+	if err != nil {
+		msg := fmt.Sprintf("failed to handle create menu item request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	err = m.services.Menu.CreateMenuItem(*menuItem)
+	if err != nil {
+		m.writeMenuError(w, err)
+		return
+	}
+
+	log.Printf("menu item created successfully - %s", menuItem.String())
+
+	// Send back menu item uuid to client on success.
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(menuItem.UUID.String()))
+}
+
+func (m *menuHandler) getMenuItem(w http.ResponseWriter, r *http.Request, menuItemUUID guuid.UUID) {
+	menuItem, err := m.services.Menu.GetMenuItem(menuItemUUID)
+	if err != nil {
+		m.writeMenuError(w, err)
+		return
+	}
+
+	m.writeJSON(w, mapper.MenuItemToJSON(*menuItem))
+}
+
+func (m *menuHandler) updateMenuItem(w http.ResponseWriter, r *http.Request, menuItemUUID guuid.UUID) {
+	menuItem, err := m.extractMenuItem(r, menuItemUUID.String())
+	if err != nil {
+		msg := fmt.Sprintf("failed to handle update menu item request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	err = m.services.Menu.UpdateMenuItem(*menuItem)
+	if err != nil {
+		m.writeMenuError(w, err)
+		return
+	}
+
+	log.Printf("menu item updated successfully - %s", menuItem.String())
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(menuItem.UUID.String()))
+}
+
+func (m *menuHandler) deleteMenuItem(w http.ResponseWriter, r *http.Request, menuItemUUID guuid.UUID) {
+	err := m.services.Menu.DeleteMenuItem(menuItemUUID)
+	if err != nil {
+		m.writeMenuError(w, err)
+		return
+	}
+
+	log.Printf("menu item deleted successfully - %s", menuItemUUID.String())
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(menuItemUUID.String()))
+}
+
+// extractMenuItem maps the form of a create or update menu item request to a menu item,
+// menuItemUUID is the uuid of the menu item being updated and empty on creation.
+func (m *menuHandler) extractMenuItem(r *http.Request, menuItemUUID string) (*entity.MenuItem, error) {
+	// Parse form so we can access key value pairs of the request body.
+	err := r.ParseForm()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse form")
+	}
+
+	formData := endpoint.FormData(r.PostForm)
+	fieldsToExtract := endpoint.FieldsToExtract{
+		RequiredFields: []string{"name", "temp", "shelfLife", "decayRate"},
+		OptionalFields: []string{"uuid", "prepTime"},
+	}
+	menuItemRequest := endpoint.MenuItemRequest{}
+	err = endpoint.ExtractRequest(formData, fieldsToExtract, &menuItemRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// The uuid in the path of an update request wins over the form.
+	if menuItemUUID != "" {
+		menuItemRequest.UUID = menuItemUUID
+	}
+
+	return mapper.MenuItemRequestToMenuItem(menuItemRequest)
+}
+
+func (m *menuHandler) writeJSON(w http.ResponseWriter, response interface{}) {
+	content, err := json.Marshal(response)
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal menu - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+func (m *menuHandler) writeMenuError(w http.ResponseWriter, err error) {
+	switch errors.Cause(err) {
+	case exception.ErrNotFound:
+		msg := fmt.Sprintf("menu item not found - err: %s", err)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(msg))
+	case exception.ErrInvalidInput:
+		msg := fmt.Sprintf("menu item is invalid - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+	default:
+		msg := fmt.Sprintf("failed to handle menu request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+	}
+}
@@ -1,9 +1,11 @@
 package order
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/kitchen-delivery/config"
 	"github.com/kitchen-delivery/entity"
@@ -13,11 +15,13 @@ import (
 	"github.com/kitchen-delivery/service"
 
 	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
 )
 
 // Handler is Health handler interface.
 type Handler interface {
 	HandleOrder(w http.ResponseWriter, r *http.Request)
+	HandleOrders(w http.ResponseWriter, r *http.Request)
 }
 
 type orderHandler struct {
@@ -63,7 +67,14 @@ func (o *orderHandler) createOrder(w http.ResponseWriter, r *http.Request) {
 	formData := endpoint.FormData(r.PostForm)
 	fieldsToExtract := endpoint.FieldsToExtract{
 		RequiredFields: []string{"name", "temp", "shelfLife", "decayRate"},
-		OptionalFields: []string{"uuid"},
+		OptionalFields: []string{"uuid", "kitchenUUID", "prepTime", "priority", "readyAt"},
+	}
+	// Orders created from a menu item only override the fields they pass in.
+	if _, ok := formData["menuItemUUID"]; ok {
+		fieldsToExtract = endpoint.FieldsToExtract{
+			RequiredFields: []string{"menuItemUUID"},
+			OptionalFields: []string{"uuid", "kitchenUUID", "name", "temp", "shelfLife", "decayRate", "prepTime", "priority", "readyAt"},
+		}
 	}
 	createOrderRequest := endpoint.CreateOrderRequest{}
 	err = endpoint.ExtractRequest(formData, fieldsToExtract, &createOrderRequest)
@@ -75,6 +86,21 @@ func (o *orderHandler) createOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if createOrderRequest.MenuItemUUID != "" {
+		createOrderRequest, err = o.applyMenuItem(createOrderRequest)
+		if err != nil {
+			msg := fmt.Sprintf("failed to create order from menu item - err: %s", err)
+			log.Println(msg)
+			if errors.Cause(err) == exception.ErrNotFound || errors.Cause(err) == exception.ErrInvalidInput {
+				w.WriteHeader(http.StatusBadRequest)
+			} else {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
 	// Map a HTTP create order request to an order entity.
 	order, err := mapper.CreateOrderRequestToOrder(createOrderRequest)
 	if err != nil {
@@ -88,6 +114,13 @@ func (o *orderHandler) createOrder(w http.ResponseWriter, r *http.Request) {
 	// Persist order to DB, before returning success to client.
 	err = o.services.Order.CreateOrder(*order)
 	if err != nil {
+		if errors.Cause(err) == exception.ErrInvalidInput {
+			msg := fmt.Sprintf("order cannot be placed - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(msg))
+			return
+		}
 		if errors.Cause(err) == exception.ErrFullShelf {
 			msg := "shelf is full"
 			log.Println(msg)
@@ -103,27 +136,29 @@ func (o *orderHandler) createOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Place order on queue which multiple worker threads pull off
+	// Scheduled orders are held off of the order queue until they are released.
+	if order.IsScheduled() {
+		o.scheduleOrder(w, *order)
+		return
+	}
+
+	// Record that the order is queued before it is visible to workers
+	// so its history never shows it being shelved before being queued.
+	err = o.services.Order.MarkOrderAsQueued(order.UUID)
+	if err != nil {
+		msg := fmt.Sprintf("failed to queue order - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Place order on its kitchen's queue which multiple worker threads pull off
 	// concurrently. This is increases the throughput that our API can handle.
 	// We purposesfully do not close this channel because we want to keep it open
 	// for workers to continue pulling indefinitely.
-	var requestErr error
-	redisConn := o.queues.Order.Pool.Get() // Fetch redis connection from redis pool.
-	switch redisConn.Err() {
-	case nil:
-		numOfOrders, err := redisConn.Do("LPUSH", o.queues.Order.Name, order.UUID.String())
-		redisConn.Close()
-		if err != nil {
-			requestErr = err
-		} else {
-			log.Printf("num of orders on queue %d", numOfOrders)
-		}
-	default:
-		redisConn.Close()
-		requestErr = redisConn.Err()
-	}
-
-	if requestErr != nil {
+	err = o.queues.Order.Push(order.KitchenUUID, order.Priority, order.UUID)
+	if err != nil {
 		msg := fmt.Sprintf("failed to place order on queue - err: %s", err)
 		log.Println(msg)
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -137,9 +172,64 @@ func (o *orderHandler) createOrder(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(order.UUID.String()))
 }
 
-// pickupOrder picks up an order.
+// scheduleOrder holds an order off of the order queue until it is released.
+func (o *orderHandler) scheduleOrder(w http.ResponseWriter, order entity.Order) {
+	scheduledOrder, err := o.services.ScheduledOrder.ScheduleOrder(order)
+	if err != nil {
+		if errors.Cause(err) == exception.ErrInvalidInput {
+			msg := fmt.Sprintf("order cannot be scheduled - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(msg))
+			return
+		}
+
+		msg := fmt.Sprintf("failed to schedule order - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(msg))
+		return
+	}
+
+	log.Printf("order scheduled successfully - %s", scheduledOrder.String())
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(order.UUID.String()))
+}
+
+// applyMenuItem fills in a create order request from the menu item it names.
+func (o *orderHandler) applyMenuItem(createOrderRequest endpoint.CreateOrderRequest) (endpoint.CreateOrderRequest, error) {
+	menuItemUUID, err := guuid.FromString(createOrderRequest.MenuItemUUID)
+	if err != nil {
+		return createOrderRequest, errors.Wrapf(
+			exception.ErrInvalidInput, "menu item uuid is invalid - uuid: %s", createOrderRequest.MenuItemUUID)
+	}
+
+	menuItem, err := o.services.Menu.GetMenuItem(menuItemUUID)
+	if err != nil {
+		return createOrderRequest, err
+	}
+
+	return mapper.ApplyMenuItem(createOrderRequest, *menuItem), nil
+}
+
+// pickupOrder picks up an order at a kitchen, ex: /order?kitchenUUID={uuid}.
 func (o *orderHandler) pickupOrder(w http.ResponseWriter, r *http.Request) {
-	order, err := o.services.Order.PickupOrder()
+	// Drivers that do not name a kitchen pick up from the default kitchen.
+	kitchenUUID := entity.DefaultKitchenUUID
+	if kitchenUUIDStr := r.URL.Query().Get("kitchenUUID"); kitchenUUIDStr != "" {
+		var err error
+		kitchenUUID, err = guuid.FromString(kitchenUUIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("kitchen uuid is invalid - uuid: %s", kitchenUUIDStr)
+			log.Println(msg)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	order, err := o.services.Order.PickupOrder(kitchenUUID)
 	if err != nil {
 		switch errors.Cause(err) {
 		case exception.ErrNotFound:
@@ -165,3 +255,288 @@ func (o *orderHandler) pickupOrder(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(orderContents))
 }
+
+// HandleOrders routes requests on a specific order, ex: /orders/{uuid}/pickup.
+func (o *orderHandler) HandleOrders(w http.ResponseWriter, r *http.Request) {
+	// "/orders/{uuid}/pickup" => ["{uuid}", "pickup"]
+	pathParams := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/orders"), "/"), "/")
+
+	if len(pathParams) == 1 && pathParams[0] == "scheduled" && r.Method == http.MethodGet {
+		o.getScheduledOrders(w, r)
+		return
+	}
+
+	orderUUID, err := guuid.FromString(pathParams[0])
+	if err != nil {
+		msg := fmt.Sprintf("order uuid is invalid - uuid: %s", pathParams[0])
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	switch {
+	case len(pathParams) == 1 && r.Method == http.MethodDelete:
+		o.cancelOrder(w, r, orderUUID)
+	case len(pathParams) == 2 && pathParams[1] == "pickup" && r.Method == http.MethodPost:
+		o.pickupOrderByUUID(w, r, orderUUID)
+	case len(pathParams) == 2 && pathParams[1] == "events" && r.Method == http.MethodGet:
+		o.getOrderEvents(w, r, orderUUID)
+	case len(pathParams) == 2 && pathParams[1] == "depart" && r.Method == http.MethodPost:
+		o.startDelivery(w, r, orderUUID)
+	case len(pathParams) == 2 && pathParams[1] == "deliver" && r.Method == http.MethodPost:
+		o.confirmDelivery(w, r, orderUUID)
+	case len(pathParams) == 2 && pathParams[1] == "delivery" && r.Method == http.MethodGet:
+		o.getDelivery(w, r, orderUUID)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("route not found"))
+	}
+}
+
+// pickupOrderByUUID picks up a specific order.
+func (o *orderHandler) pickupOrderByUUID(w http.ResponseWriter, r *http.Request, orderUUID guuid.UUID) {
+	order, err := o.services.Order.PickupOrderByUUID(orderUUID)
+	if err != nil {
+		switch errors.Cause(err) {
+		case exception.ErrNotFound:
+			msg := fmt.Sprintf("order not found - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(msg))
+			return
+		case exception.ErrInvalidResourceState, exception.ErrVersionInvalid:
+			msg := fmt.Sprintf("order cannot be picked up - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(msg))
+			return
+		default:
+			msg := fmt.Sprintf("failed to pickup order - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	// Stringify the contents of the order.
+	orderContents := order.String()
+
+	log.Printf("driver picked up order successfully - %s", orderContents)
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(orderContents))
+}
+
+// getScheduledOrders returns the orders of a kitchen that are not released yet,
+// ex: /orders/scheduled?kitchenUUID={uuid}.
+func (o *orderHandler) getScheduledOrders(w http.ResponseWriter, r *http.Request) {
+	kitchenUUID := entity.DefaultKitchenUUID
+	if kitchenUUIDStr := r.URL.Query().Get("kitchenUUID"); kitchenUUIDStr != "" {
+		var err error
+		kitchenUUID, err = guuid.FromString(kitchenUUIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("kitchen uuid is invalid - uuid: %s", kitchenUUIDStr)
+			log.Println(msg)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	scheduledOrders, err := o.services.ScheduledOrder.GetScheduledOrders(kitchenUUID)
+	if err != nil {
+		msg := fmt.Sprintf("failed to fetch scheduled orders - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	content, err := json.Marshal(mapper.ScheduledOrdersToJSON(scheduledOrders))
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal scheduled orders - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// cancelOrder cancels an order and pulls it off of its shelf or the order queue.
+func (o *orderHandler) cancelOrder(w http.ResponseWriter, r *http.Request, orderUUID guuid.UUID) {
+	// Orders that are still scheduled never reached the order queue.
+	err := o.services.ScheduledOrder.CancelScheduledOrder(orderUUID)
+	if err == nil {
+		log.Printf("scheduled order cancelled successfully - %s", orderUUID.String())
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(orderUUID.String()))
+		return
+	}
+	if errors.Cause(err) != exception.ErrNotFound && errors.Cause(err) != exception.ErrInvalidResourceState {
+		msg := fmt.Sprintf("failed to cancel order - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Released orders are cancelled like any other order.
+	err = o.services.Order.CancelOrder(orderUUID)
+	if err != nil {
+		switch errors.Cause(err) {
+		case exception.ErrNotFound:
+			msg := fmt.Sprintf("order not found - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(msg))
+			return
+		case exception.ErrInvalidResourceState, exception.ErrVersionInvalid:
+			msg := fmt.Sprintf("order cannot be cancelled - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(msg))
+			return
+		default:
+			msg := fmt.Sprintf("failed to cancel order - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	// Pull the order out of the order queue if a worker has not picked it up yet.
+	// This is a best effort as workers skip cancelled orders anyway.
+	o.removeOrderFromQueue(orderUUID)
+
+	log.Printf("order cancelled successfully - %s", orderUUID.String())
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(orderUUID.String()))
+}
+
+// removeOrderFromQueue removes an order from its kitchen's order queue.
+func (o *orderHandler) removeOrderFromQueue(orderUUID guuid.UUID) {
+	order, err := o.services.Order.GetOrder(orderUUID)
+	if err != nil {
+		log.Printf("failed to fetch order %s to remove from queue - err: %s", orderUUID.String(), err)
+		return
+	}
+
+	err = o.queues.Order.Remove(order.KitchenUUID, order.Priority, orderUUID)
+	if err != nil {
+		log.Printf("failed to remove order %s from queue - err: %s", orderUUID.String(), err)
+	}
+}
+
+// getOrderEvents returns the status history of an order.
+func (o *orderHandler) getOrderEvents(w http.ResponseWriter, r *http.Request, orderUUID guuid.UUID) {
+	orderEvents, err := o.services.Order.GetOrderEvents(orderUUID)
+	if err != nil {
+		switch errors.Cause(err) {
+		case exception.ErrNotFound:
+			msg := fmt.Sprintf("order not found - err: %s", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(msg))
+			return
+		default:
+			msg := fmt.Sprintf("failed to fetch order events - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	content, err := json.Marshal(mapper.OrderEventsToJSON(orderEvents))
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal order events - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// startDelivery marks a picked up order as out for delivery.
+func (o *orderHandler) startDelivery(w http.ResponseWriter, r *http.Request, orderUUID guuid.UUID) {
+	err := o.services.Delivery.StartDelivery(orderUUID)
+	if err != nil {
+		o.writeDeliveryError(w, err)
+		return
+	}
+
+	log.Printf("driver left the kitchen with order - %s", orderUUID.String())
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(orderUUID.String()))
+}
+
+// confirmDelivery marks an order as delivered and returns its condition on arrival.
+func (o *orderHandler) confirmDelivery(w http.ResponseWriter, r *http.Request, orderUUID guuid.UUID) {
+	delivery, err := o.services.Delivery.ConfirmDelivery(orderUUID)
+	if err != nil {
+		o.writeDeliveryError(w, err)
+		return
+	}
+
+	log.Printf("driver delivered order - %s", delivery.String())
+
+	o.writeDelivery(w, *delivery)
+}
+
+// getDelivery returns the condition of a delivered order on arrival.
+func (o *orderHandler) getDelivery(w http.ResponseWriter, r *http.Request, orderUUID guuid.UUID) {
+	delivery, err := o.services.Delivery.GetDelivery(orderUUID)
+	if err != nil {
+		o.writeDeliveryError(w, err)
+		return
+	}
+
+	o.writeDelivery(w, *delivery)
+}
+
+func (o *orderHandler) writeDelivery(w http.ResponseWriter, delivery entity.Delivery) {
+	content, err := json.Marshal(mapper.DeliveryToJSON(delivery))
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal delivery - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+func (o *orderHandler) writeDeliveryError(w http.ResponseWriter, err error) {
+	switch errors.Cause(err) {
+	case exception.ErrNotFound:
+		msg := fmt.Sprintf("delivery not found - err: %s", err)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(msg))
+	case exception.ErrInvalidResourceState, exception.ErrVersionInvalid:
+		msg := fmt.Sprintf("order cannot be delivered - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(msg))
+	default:
+		msg := fmt.Sprintf("failed to handle delivery - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+	}
+}
@@ -0,0 +1,231 @@
+package parentorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// Handler is Parent Order handler interface.
+type Handler interface {
+	HandleParentOrder(w http.ResponseWriter, r *http.Request)
+	HandleParentOrders(w http.ResponseWriter, r *http.Request)
+}
+
+type parentOrderHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+	queues   *entity.Queues
+}
+
+// NewHandler creates a new HTTP parent order handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services, queues *entity.Queues) Handler {
+	return &parentOrderHandler{
+		cfg:      appConfig,
+		services: services,
+		queues:   queues,
+	}
+}
+
+// HandleParentOrder creates a parent order from a json body, ex:
+// {"kitchenUUID": "...", "items": [{"menuItemUUID": "..."}, {"name": "Yogurt", "temp": "cold", ...}]}.
+func (p *parentOrderHandler) HandleParentOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	createParentOrderRequest := endpoint.CreateParentOrderRequest{}
+	err := json.NewDecoder(r.Body).Decode(&createParentOrderRequest)
+	if err != nil {
+		msg := fmt.Sprintf("failed to decode create parent order request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Items created from a menu item only override the fields they pass in.
+	for i, itemRequest := range createParentOrderRequest.Items {
+		if itemRequest.MenuItemUUID == "" {
+			continue
+		}
+
+		createParentOrderRequest.Items[i], err = p.applyMenuItem(itemRequest)
+		if err != nil {
+			msg := fmt.Sprintf("failed to create item %d from menu item - err: %s", i, err)
+			log.Println(msg)
+			if errors.Cause(err) == exception.ErrNotFound || errors.Cause(err) == exception.ErrInvalidInput {
+				w.WriteHeader(http.StatusBadRequest)
+			} else {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	parentOrder, err := mapper.CreateParentOrderRequestToParentOrder(createParentOrderRequest)
+	if err != nil {
+		msg := fmt.Sprintf("failed to map create parent order request to parent order - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Persist parent order and its items to DB, before returning success to client.
+	err = p.services.ParentOrder.CreateParentOrder(*parentOrder)
+	if err != nil {
+		msg := fmt.Sprintf("failed to store parent order - err: %s", err)
+		log.Println(msg)
+		if errors.Cause(err) == exception.ErrInvalidInput {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Every item is queued and placed on the shelf for its temperature by workers.
+	for _, item := range parentOrder.Items {
+		err = p.queueItem(item)
+		if err != nil {
+			msg := fmt.Sprintf("failed to queue item %s - err: %s", item.UUID.String(), err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	log.Printf("parent order created successfully - %s", parentOrder.String())
+
+	// Send back parent order uuid to client on success.
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(parentOrder.UUID.String()))
+}
+
+// HandleParentOrders routes requests on a specific parent order, ex: /parent-orders/{uuid}/pickup.
+func (p *parentOrderHandler) HandleParentOrders(w http.ResponseWriter, r *http.Request) {
+	// "/parent-orders/{uuid}/pickup" => ["{uuid}", "pickup"]
+	pathParams := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/parent-orders"), "/"), "/")
+
+	parentOrderUUID, err := guuid.FromString(pathParams[0])
+	if err != nil {
+		msg := fmt.Sprintf("parent order uuid is invalid - uuid: %s", pathParams[0])
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	switch {
+	case len(pathParams) == 1 && r.Method == http.MethodGet:
+		p.getParentOrder(w, r, parentOrderUUID)
+	case len(pathParams) == 2 && pathParams[1] == "pickup" && r.Method == http.MethodPost:
+		p.pickupParentOrder(w, r, parentOrderUUID)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("route not found"))
+	}
+}
+
+// getParentOrder returns a parent order with the status of every item.
+func (p *parentOrderHandler) getParentOrder(w http.ResponseWriter, r *http.Request, parentOrderUUID guuid.UUID) {
+	parentOrder, err := p.services.ParentOrder.GetParentOrder(parentOrderUUID)
+	if err != nil {
+		p.writeParentOrderError(w, err)
+		return
+	}
+
+	p.writeParentOrder(w, *parentOrder)
+}
+
+// pickupParentOrder picks up every item of a parent order together.
+func (p *parentOrderHandler) pickupParentOrder(w http.ResponseWriter, r *http.Request, parentOrderUUID guuid.UUID) {
+	parentOrder, err := p.services.ParentOrder.PickupParentOrder(parentOrderUUID)
+	if err != nil {
+		p.writeParentOrderError(w, err)
+		return
+	}
+
+	log.Printf("driver picked up parent order successfully - %s", parentOrder.String())
+
+	p.writeParentOrder(w, *parentOrder)
+}
+
+// applyMenuItem fills in an item request from the menu item it names.
+func (p *parentOrderHandler) applyMenuItem(itemRequest endpoint.CreateOrderRequest) (endpoint.CreateOrderRequest, error) {
+	menuItemUUID, err := guuid.FromString(itemRequest.MenuItemUUID)
+	if err != nil {
+		return itemRequest, errors.Wrapf(
+			exception.ErrInvalidInput, "menu item uuid is invalid - uuid: %s", itemRequest.MenuItemUUID)
+	}
+
+	menuItem, err := p.services.Menu.GetMenuItem(menuItemUUID)
+	if err != nil {
+		return itemRequest, err
+	}
+
+	return mapper.ApplyMenuItem(itemRequest, *menuItem), nil
+}
+
+// queueItem places an item on its kitchen's order queue.
+func (p *parentOrderHandler) queueItem(item entity.Order) error {
+	// Record that the item is queued before it is visible to workers
+	// so its history never shows it being shelved before being queued.
+	err := p.services.Order.MarkOrderAsQueued(item.UUID)
+	if err != nil {
+		return err
+	}
+
+	return p.queues.Order.Push(item.KitchenUUID, item.Priority, item.UUID)
+}
+
+func (p *parentOrderHandler) writeParentOrder(w http.ResponseWriter, parentOrder entity.ParentOrder) {
+	content, err := json.Marshal(mapper.ParentOrderToJSON(parentOrder))
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal parent order - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+func (p *parentOrderHandler) writeParentOrderError(w http.ResponseWriter, err error) {
+	switch errors.Cause(err) {
+	case exception.ErrNotFound:
+		msg := fmt.Sprintf("parent order not found - err: %s", err)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(msg))
+	case exception.ErrInvalidResourceState, exception.ErrVersionInvalid:
+		msg := fmt.Sprintf("parent order cannot be picked up - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(msg))
+	default:
+		msg := fmt.Sprintf("failed to handle parent order - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+	}
+}
@@ -0,0 +1,112 @@
+package pickup
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+)
+
+// Handler is Pickup handler interface.
+type Handler interface {
+	HandlePickup(w http.ResponseWriter, r *http.Request)
+}
+
+type pickupHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+}
+
+// NewHandler creates a new HTTP pickup handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
+	return &pickupHandler{
+		cfg:      appConfig,
+		services: services,
+	}
+}
+
+// HandlePickup hands an arriving courier an order based on the pickup strategy.
+func (p *pickupHandler) HandlePickup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		msg := fmt.Sprintf("failed to parse form - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	formData := endpoint.FormData(r.PostForm)
+	fieldsToExtract := endpoint.FieldsToExtract{
+		OptionalFields: []string{"courierUUID", "kitchenUUID", "orderUUID", "arrivedAt"},
+	}
+	pickupOrderRequest := endpoint.PickupOrderRequest{}
+	err = endpoint.ExtractRequest(formData, fieldsToExtract, &pickupOrderRequest)
+	if err != nil {
+		msg := fmt.Sprintf("failed to handle pickup order request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Map a HTTP pickup order request to a courier entity.
+	courier, err := mapper.PickupOrderRequestToCourier(pickupOrderRequest)
+	if err != nil {
+		msg := fmt.Sprintf("failed to map pickup order request to courier - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	order, err := p.services.Pickup.PickupOrder(*courier)
+	if err != nil {
+		switch errors.Cause(err) {
+		case exception.ErrNotFound:
+			// Courier should wait and retry as the order is not on a shelf yet.
+			msg := fmt.Sprintf("no order ready for pickup - err: %s", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(msg))
+			return
+		case exception.ErrInvalidInput:
+			msg := fmt.Sprintf("invalid pickup request - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(msg))
+			return
+		case exception.ErrVersionInvalid:
+			// Another courier beat this courier to the order.
+			msg := fmt.Sprintf("order was picked up by another courier - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(msg))
+			return
+		default:
+			msg := fmt.Sprintf("failed to pickup order - err: %s", err)
+			log.Println(msg)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(msg))
+			return
+		}
+	}
+
+	orderContents := order.String()
+
+	log.Printf("courier picked up order successfully - %s", orderContents)
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(orderContents))
+}
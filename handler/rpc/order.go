@@ -0,0 +1,267 @@
+package rpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/rpc/kitchenpb"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type orderServer struct {
+	kitchenpb.UnimplementedOrderServiceServer
+
+	cfg      config.AppConfig
+	services service.Services
+	queues   *entity.Queues
+}
+
+// NewOrderServer creates a new gRPC order server instance.
+func NewOrderServer(cfg config.AppConfig, services service.Services, queues *entity.Queues) kitchenpb.OrderServiceServer {
+	return &orderServer{
+		cfg:      cfg,
+		services: services,
+		queues:   queues,
+	}
+}
+
+// CreateOrder places an order on its kitchen's order queue, or schedules it if it has a ready time.
+func (o *orderServer) CreateOrder(
+	ctx context.Context, request *kitchenpb.CreateOrderRequest) (*kitchenpb.CreateOrderResponse, error) {
+	createOrderRequest := mapper.CreateOrderProtoToRequest(request)
+
+	if createOrderRequest.MenuItemUUID != "" {
+		menuItemUUID, err := guuid.FromString(createOrderRequest.MenuItemUUID)
+		if err != nil {
+			return nil, invalidArgument("menu item uuid is invalid - uuid: %s", createOrderRequest.MenuItemUUID)
+		}
+
+		menuItem, err := o.services.Menu.GetMenuItem(menuItemUUID)
+		if err != nil {
+			// A menu item that does not exist is a bad request, not a missing order.
+			if errors.Cause(err) == exception.ErrNotFound {
+				err = errors.Wrap(exception.ErrInvalidInput, err.Error())
+			}
+			return nil, toStatusError(err, "failed to create order from menu item")
+		}
+		createOrderRequest = mapper.ApplyMenuItem(createOrderRequest, *menuItem)
+	}
+
+	// Map a create order request to an order entity.
+	order, err := mapper.CreateOrderRequestToOrder(createOrderRequest)
+	if err != nil {
+		return nil, invalidArgument("failed to map create order request to order - err: %s", err)
+	}
+
+	// Persist order to DB, before returning success to client.
+	err = o.services.Order.CreateOrder(*order)
+	if err != nil {
+		return nil, toStatusError(err, "failed to store order")
+	}
+
+	// Scheduled orders are held off of the order queue until they are released.
+	if order.IsScheduled() {
+		scheduledOrder, err := o.services.ScheduledOrder.ScheduleOrder(*order)
+		if err != nil {
+			return nil, toStatusError(err, "failed to schedule order")
+		}
+
+		log.Printf("rpc | order scheduled successfully - %s", scheduledOrder.String())
+		return &kitchenpb.CreateOrderResponse{OrderUuid: order.UUID.String(), Scheduled: true}, nil
+	}
+
+	// Record that the order is queued before it is visible to workers
+	// so its history never shows it being shelved before being queued.
+	err = o.services.Order.MarkOrderAsQueued(order.UUID)
+	if err != nil {
+		return nil, toStatusError(err, "failed to queue order")
+	}
+
+	err = o.pushOrderToQueue(*order)
+	if err != nil {
+		return nil, toStatusError(errors.Wrap(exception.ErrServiceUnavailable, err.Error()), "failed to place order on queue")
+	}
+
+	return &kitchenpb.CreateOrderResponse{OrderUuid: order.UUID.String()}, nil
+}
+
+// pushOrderToQueue places an order on its kitchen's order queue for workers to pull off.
+func (o *orderServer) pushOrderToQueue(order entity.Order) error {
+	err := o.queues.Order.Push(order.KitchenUUID, order.Priority, order.UUID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("rpc | order placed on queue - %s", order.UUID.String())
+	return nil
+}
+
+// GetOrder returns an order and its latest status.
+func (o *orderServer) GetOrder(ctx context.Context, request *kitchenpb.GetOrderRequest) (*kitchenpb.GetOrderResponse, error) {
+	orderUUID, err := guuid.FromString(request.GetOrderUuid())
+	if err != nil {
+		return nil, invalidArgument("order uuid is invalid - uuid: %s", request.GetOrderUuid())
+	}
+
+	order, err := o.services.Order.GetOrder(orderUUID)
+	if err != nil {
+		return nil, toStatusError(err, "failed to get order")
+	}
+
+	orderEvents, err := o.services.Order.GetOrderEvents(orderUUID)
+	if err != nil {
+		return nil, toStatusError(err, "failed to fetch order events")
+	}
+
+	response := &kitchenpb.GetOrderResponse{Order: mapper.OrderToProto(*order)}
+	if len(orderEvents) > 0 {
+		response.Status = string(orderEvents[len(orderEvents)-1].ToStatus)
+	}
+
+	return response, nil
+}
+
+// PickupOrder picks up a specific order, or the next order ready at a kitchen.
+func (o *orderServer) PickupOrder(
+	ctx context.Context, request *kitchenpb.PickupOrderRequest) (*kitchenpb.PickupOrderResponse, error) {
+	var order *entity.Order
+	var err error
+
+	if request.GetOrderUuid() != "" {
+		orderUUID, err := guuid.FromString(request.GetOrderUuid())
+		if err != nil {
+			return nil, invalidArgument("order uuid is invalid - uuid: %s", request.GetOrderUuid())
+		}
+
+		order, err = o.services.Order.PickupOrderByUUID(orderUUID)
+		if err != nil {
+			return nil, toStatusError(err, "failed to pickup order")
+		}
+	} else {
+		// Drivers that do not name a kitchen pick up from the default kitchen.
+		kitchenUUID := entity.DefaultKitchenUUID
+		if request.GetKitchenUuid() != "" {
+			kitchenUUID, err = guuid.FromString(request.GetKitchenUuid())
+			if err != nil {
+				return nil, invalidArgument("kitchen uuid is invalid - uuid: %s", request.GetKitchenUuid())
+			}
+		}
+
+		order, err = o.services.Order.PickupOrder(kitchenUUID)
+		if err != nil {
+			return nil, toStatusError(err, "failed to pickup order")
+		}
+	}
+
+	log.Printf("rpc | driver picked up order successfully - %s", order.String())
+	return &kitchenpb.PickupOrderResponse{Order: mapper.OrderToProto(*order)}, nil
+}
+
+// CancelOrder cancels an order and pulls it off of its shelf or the order queue.
+func (o *orderServer) CancelOrder(
+	ctx context.Context, request *kitchenpb.CancelOrderRequest) (*kitchenpb.CancelOrderResponse, error) {
+	orderUUID, err := guuid.FromString(request.GetOrderUuid())
+	if err != nil {
+		return nil, invalidArgument("order uuid is invalid - uuid: %s", request.GetOrderUuid())
+	}
+	response := &kitchenpb.CancelOrderResponse{OrderUuid: orderUUID.String()}
+
+	// Orders that are still scheduled never reached the order queue.
+	err = o.services.ScheduledOrder.CancelScheduledOrder(orderUUID)
+	if err == nil {
+		log.Printf("rpc | scheduled order cancelled successfully - %s", orderUUID.String())
+		return response, nil
+	}
+	if errors.Cause(err) != exception.ErrNotFound && errors.Cause(err) != exception.ErrInvalidResourceState {
+		return nil, toStatusError(err, "failed to cancel order")
+	}
+
+	// Released orders are cancelled like any other order.
+	err = o.services.Order.CancelOrder(orderUUID)
+	if err != nil {
+		return nil, toStatusError(err, "failed to cancel order")
+	}
+
+	// Pull the order out of the order queue if a worker has not picked it up yet.
+	// This is a best effort as workers skip cancelled orders anyway.
+	o.removeOrderFromQueue(orderUUID)
+
+	log.Printf("rpc | order cancelled successfully - %s", orderUUID.String())
+	return response, nil
+}
+
+// removeOrderFromQueue removes an order from its kitchen's order queue.
+func (o *orderServer) removeOrderFromQueue(orderUUID guuid.UUID) {
+	order, err := o.services.Order.GetOrder(orderUUID)
+	if err != nil {
+		log.Printf("rpc | failed to fetch order %s to remove from queue - err: %s", orderUUID.String(), err)
+		return
+	}
+
+	err = o.queues.Order.Remove(order.KitchenUUID, order.Priority, orderUUID)
+	if err != nil {
+		log.Printf("rpc | failed to remove order %s from queue - err: %s", orderUUID.String(), err)
+	}
+}
+
+// StreamOrderEvents streams order events until the client goes away. Clients that resume
+// with the id of the last event they received are first sent the recent events they missed.
+func (o *orderServer) StreamOrderEvents(
+	request *kitchenpb.StreamOrderEventsRequest, stream kitchenpb.OrderService_StreamOrderEventsServer) error {
+	filter := entity.EventFilter{}
+	if request.GetKitchenUuid() != "" {
+		kitchenUUID, err := guuid.FromString(request.GetKitchenUuid())
+		if err != nil {
+			return invalidArgument("kitchen uuid is invalid - uuid: %s", request.GetKitchenUuid())
+		}
+		filter.KitchenUUID = kitchenUUID
+	}
+	if request.GetOrderUuid() != "" {
+		orderUUID, err := guuid.FromString(request.GetOrderUuid())
+		if err != nil {
+			return invalidArgument("order uuid is invalid - uuid: %s", request.GetOrderUuid())
+		}
+		filter.OrderUUID = orderUUID
+	}
+
+	subscription := o.services.EventBus.Subscribe(filter, request.GetLastEventId())
+	defer o.services.EventBus.Unsubscribe(subscription)
+
+	for _, event := range subscription.Replay {
+		err := stream.Send(mapper.EventToProto(event))
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscription.Events:
+			// The client fell behind, it resumes from its last event when it reconnects.
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "client fell behind the event stream, resume from the last event received")
+			}
+
+			err := stream.Send(mapper.EventToProto(event))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// invalidArgument returns an invalid argument status error for a bad request.
+func invalidArgument(format string, args ...interface{}) error {
+	return toStatusError(errors.Wrapf(exception.ErrInvalidInput, format, args...), "invalid request")
+}
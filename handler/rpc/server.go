@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/rpc/kitchenpb"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer returns a gRPC server serving the order and shelf services.
+func NewServer(cfg config.AppConfig, services service.Services, queues *entity.Queues) *grpc.Server {
+	server := grpc.NewServer()
+	kitchenpb.RegisterOrderServiceServer(server, NewOrderServer(cfg, services, queues))
+	kitchenpb.RegisterShelfServiceServer(server, NewShelfServer(cfg, services))
+
+	return server
+}
+
+// toStatusError maps a service error to a gRPC status error
+// the same way the HTTP handlers map it to a status code.
+func toStatusError(err error, msg string) error {
+	code := codes.Internal
+	switch errors.Cause(err) {
+	case exception.ErrNotFound:
+		code = codes.NotFound
+	case exception.ErrInvalidInput:
+		code = codes.InvalidArgument
+	case exception.ErrUnauthorized:
+		code = codes.Unauthenticated
+	case exception.ErrInvalidResourceState:
+		code = codes.FailedPrecondition
+	case exception.ErrVersionInvalid:
+		// The order was updated underneath us, clients can retry.
+		code = codes.Aborted
+	case exception.ErrFullShelf:
+		code = codes.ResourceExhausted
+	case exception.ErrDatabase, exception.ErrServiceUnavailable:
+		code = codes.Unavailable
+	case exception.ErrDataCorrupted:
+		code = codes.DataLoss
+	}
+
+	msg = fmt.Sprintf("%s - err: %s", msg, err)
+	log.Println(msg)
+	return status.Error(code, msg)
+}
@@ -0,0 +1,188 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/rpc/kitchenpb"
+	"github.com/kitchen-delivery/service"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the gRPC API with real services on top of mocked repositories
+// over an in-memory connection, and returns a client connected to it.
+func newTestClient(t *testing.T, orderRepo repository.OrderRepository,
+	orderEventRepo repository.OrderEventRepository) (*grpc.ClientConn, service.EventBus) {
+	cfg := config.AppConfig{}
+	err := cfg.LoadConfig("../../config/development.yaml")
+	assert.Nil(t, err)
+
+	kitchenService, err := service.NewKitchenService(cfg)
+	assert.Nil(t, err)
+
+	eventBus := service.NewEventBus(cfg)
+	services := service.Services{
+		Kitchen:  kitchenService,
+		EventBus: eventBus,
+		Order:    service.NewOrderService(cfg, kitchenService, orderRepo, nil, orderEventRepo, eventBus),
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(cfg, services, &entity.Queues{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(
+		func(ctx context.Context, address string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, eventBus
+}
+
+func TestToStatusError(t *testing.T) {
+	expectedCodes := map[error]codes.Code{
+		exception.ErrNotFound:             codes.NotFound,
+		exception.ErrInvalidInput:         codes.InvalidArgument,
+		exception.ErrUnauthorized:         codes.Unauthenticated,
+		exception.ErrInvalidResourceState: codes.FailedPrecondition,
+		exception.ErrVersionInvalid:       codes.Aborted,
+		exception.ErrFullShelf:            codes.ResourceExhausted,
+		exception.ErrDatabase:             codes.Unavailable,
+		exception.ErrDataCorrupted:        codes.DataLoss,
+		exception.ErrUnhandledException:   codes.Internal,
+	}
+
+	for sentinel, expectedCode := range expectedCodes {
+		err := toStatusError(errors.Wrap(sentinel, "wrapped"), "failed")
+		assert.Equal(t, expectedCode, status.Code(err), sentinel.Error())
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := repository.NewMockOrderRepository(ctrl)
+	orderEventRepo := repository.NewMockOrderEventRepository(ctrl)
+	conn, _ := newTestClient(t, orderRepo, orderEventRepo)
+	client := kitchenpb.NewOrderServiceClient(conn)
+
+	order := entity.Order{
+		UUID:        guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		Name:        "Cheese Pizza",
+		Temp:        entity.OrderTempHot,
+		ShelfLife:   300,
+		DecayRate:   0.45,
+	}
+	orderRepo.EXPECT().GetOrder(order.UUID).Return(&order, nil).Times(2)
+	orderEventRepo.EXPECT().GetOrderEvents(order.UUID).Return([]*entity.OrderEvent{
+		{OrderUUID: order.UUID, ToStatus: entity.OrderStatusReceived},
+		{OrderUUID: order.UUID, FromStatus: entity.OrderStatusReceived, ToStatus: entity.OrderStatusQueued},
+	}, nil)
+
+	response, err := client.GetOrder(context.Background(), &kitchenpb.GetOrderRequest{OrderUuid: order.UUID.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, string(entity.OrderStatusQueued), response.GetStatus())
+	assert.Equal(t, order.UUID.String(), response.GetOrder().GetUuid())
+	assert.Equal(t, "Cheese Pizza", response.GetOrder().GetName())
+	assert.Equal(t, "standard", response.GetOrder().GetPriority())
+	assert.Nil(t, response.GetOrder().GetReadyAt())
+}
+
+func TestGetOrder_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := repository.NewMockOrderRepository(ctrl)
+	conn, _ := newTestClient(t, orderRepo, repository.NewMockOrderEventRepository(ctrl))
+	client := kitchenpb.NewOrderServiceClient(conn)
+
+	_, err := client.GetOrder(context.Background(), &kitchenpb.GetOrderRequest{OrderUuid: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	orderUUID := guuid.NewV4()
+	orderRepo.EXPECT().GetOrder(orderUUID).Return(nil, errors.Wrap(exception.ErrNotFound, "order not found"))
+
+	_, err = client.GetOrder(context.Background(), &kitchenpb.GetOrderRequest{OrderUuid: orderUUID.String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreateOrder_InvalidArgument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn, _ := newTestClient(t, repository.NewMockOrderRepository(ctrl), repository.NewMockOrderEventRepository(ctrl))
+	client := kitchenpb.NewOrderServiceClient(conn)
+
+	// Orders not created from a menu item need a shelf life.
+	_, err := client.CreateOrder(context.Background(), &kitchenpb.CreateOrderRequest{
+		Name:      "Cheese Pizza",
+		Temp:      string(entity.OrderTempHot),
+		DecayRate: 0.45,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestStreamOrderEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn, eventBus := newTestClient(t, repository.NewMockOrderRepository(ctrl), repository.NewMockOrderEventRepository(ctrl))
+	client := kitchenpb.NewOrderServiceClient(conn)
+
+	orderUUID := guuid.NewV4()
+	newEvent := func(eventType entity.EventType, toStatus entity.OrderStatus) entity.Event {
+		return entity.Event{
+			Type:        eventType,
+			KitchenUUID: entity.DefaultKitchenUUID,
+			OrderUUID:   orderUUID,
+			ToStatus:    toStatus,
+			OccurredAt:  time.Now(),
+		}
+	}
+	created := eventBus.Publish(newEvent(entity.EventTypeOrderCreated, entity.OrderStatusReceived))
+	eventBus.Publish(newEvent(entity.EventTypeOrderShelved, entity.OrderStatusReadyForPickup))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Events published after the last event the client received are replayed.
+	stream, err := client.StreamOrderEvents(ctx, &kitchenpb.StreamOrderEventsRequest{
+		OrderUuid:   orderUUID.String(),
+		LastEventId: created.ID,
+	})
+	assert.Nil(t, err)
+
+	event, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, string(entity.EventTypeOrderShelved), event.GetType())
+	assert.Equal(t, created.ID+1, event.GetId())
+
+	// Events of other orders are filtered out, later events are streamed as they happen.
+	eventBus.Publish(entity.Event{Type: entity.EventTypeOrderCreated, OrderUUID: guuid.NewV4()})
+	pickedUp := eventBus.Publish(newEvent(entity.EventTypeOrderPickedUp, entity.OrderStatusPickedUp))
+
+	event, err = stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, pickedUp.ID, event.GetId())
+	assert.Equal(t, orderUUID.String(), event.GetOrderUuid())
+	assert.Equal(t, string(entity.OrderStatusPickedUp), event.GetToStatus())
+}
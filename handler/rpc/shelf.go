@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/rpc/kitchenpb"
+	"github.com/kitchen-delivery/service"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+type shelfServer struct {
+	kitchenpb.UnimplementedShelfServiceServer
+
+	cfg      config.AppConfig
+	services service.Services
+}
+
+// NewShelfServer creates a new gRPC shelf server instance.
+func NewShelfServer(cfg config.AppConfig, services service.Services) kitchenpb.ShelfServiceServer {
+	return &shelfServer{
+		cfg:      cfg,
+		services: services,
+	}
+}
+
+// ListShelves returns the shelves of a kitchen and the orders on them,
+// filtered and sorted the same way as /shelves.
+func (s *shelfServer) ListShelves(
+	ctx context.Context, request *kitchenpb.ListShelvesRequest) (*kitchenpb.ListShelvesResponse, error) {
+	kitchenUUID := entity.DefaultKitchenUUID
+	if request.GetKitchenUuid() != "" {
+		var err error
+		kitchenUUID, err = guuid.FromString(request.GetKitchenUuid())
+		if err != nil {
+			return nil, invalidArgument("kitchen uuid is invalid - uuid: %s", request.GetKitchenUuid())
+		}
+	}
+
+	filter := entity.ShelfOrderFilter{ShelfType: entity.ShelfType(request.GetShelfType())}
+	for _, temp := range request.GetTemps() {
+		if !entity.IsValidOrderTemp(entity.OrderTemp(temp)) {
+			return nil, invalidArgument("order temp %s is invalid", temp)
+		}
+		filter.Temps = append(filter.Temps, entity.OrderTemp(temp))
+	}
+	for _, orderStatus := range request.GetStatuses() {
+		if _, ok := entity.AllOrderStatuses[entity.OrderStatus(orderStatus)]; !ok {
+			return nil, invalidArgument("order status %s is invalid", orderStatus)
+		}
+		filter.Statuses = append(filter.Statuses, entity.OrderStatus(orderStatus))
+	}
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = []entity.OrderStatus{entity.OrderStatusReadyForPickup}
+	}
+
+	sortOrder, err := entity.ParseShelfSortOrder(request.GetSort())
+	if err != nil {
+		return nil, invalidArgument("sort is invalid - err: %s", err)
+	}
+
+	if filter.ShelfType != "" {
+		shelfSnapshot, err := s.services.Shelf.GetShelf(kitchenUUID, filter, sortOrder)
+		if err != nil {
+			return nil, toStatusError(err, "failed to get shelf")
+		}
+
+		return &kitchenpb.ListShelvesResponse{Shelves: []*kitchenpb.Shelf{mapper.ShelfSnapshotToProto(*shelfSnapshot)}}, nil
+	}
+
+	shelfSnapshots, err := s.services.Shelf.GetShelves(kitchenUUID, filter, sortOrder)
+	if err != nil {
+		return nil, toStatusError(err, "failed to get shelves")
+	}
+
+	return &kitchenpb.ListShelvesResponse{Shelves: mapper.ShelfSnapshotsToProto(shelfSnapshots)}, nil
+}
@@ -0,0 +1,171 @@
+package shelf
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// Handler is Shelf handler interface.
+type Handler interface {
+	HandleShelves(w http.ResponseWriter, r *http.Request)
+}
+
+type shelfHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+}
+
+// NewHandler creates a new HTTP shelf handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
+	return &shelfHandler{
+		cfg:      appConfig,
+		services: services,
+	}
+}
+
+// HandleShelves returns what is on the shelves of a kitchen, ex: /shelves or /shelves/{type}.
+// Orders are filtered with ?temp=hot,cold and ?status=ready_for_pickup,picked_up and
+// sorted with ?sort=value or ?sort=-value, by default orders ready for pickup
+// that expire soonest are listed first.
+func (s *shelfHandler) HandleShelves(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// "/shelves/{type}" => "{type}"
+	shelfType := entity.ShelfType(strings.Trim(strings.TrimPrefix(r.URL.Path, "/shelves"), "/"))
+	if strings.Contains(string(shelfType), "/") {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("route not found"))
+		return
+	}
+
+	kitchenUUID, filter, sortOrder, err := s.extractShelfQuery(r.URL.Query())
+	if err != nil {
+		msg := fmt.Sprintf("failed to handle shelves request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+	filter.ShelfType = shelfType
+
+	var response interface{}
+	if shelfType == "" {
+		var shelfSnapshots []*entity.ShelfSnapshot
+		shelfSnapshots, err = s.services.Shelf.GetShelves(kitchenUUID, filter, sortOrder)
+		response = mapper.ShelfSnapshotsToJSON(shelfSnapshots)
+	} else {
+		var shelfSnapshot *entity.ShelfSnapshot
+		shelfSnapshot, err = s.services.Shelf.GetShelf(kitchenUUID, filter, sortOrder)
+		if shelfSnapshot != nil {
+			response = mapper.ShelfSnapshotToJSON(*shelfSnapshot)
+		}
+	}
+	if err != nil {
+		s.writeShelfError(w, err)
+		return
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal shelves - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// extractShelfQuery maps the query string of a shelves request to
+// a kitchen, an order filter and a sort order.
+func (s *shelfHandler) extractShelfQuery(query url.Values) (guuid.UUID, entity.ShelfOrderFilter, entity.ShelfSortOrder, error) {
+	filter := entity.ShelfOrderFilter{}
+
+	// Requests that do not name a kitchen are for the default kitchen.
+	kitchenUUID := entity.DefaultKitchenUUID
+	if kitchenUUIDStr := query.Get("kitchenUUID"); kitchenUUIDStr != "" {
+		var err error
+		kitchenUUID, err = guuid.FromString(kitchenUUIDStr)
+		if err != nil {
+			return kitchenUUID, filter, entity.ShelfSortOrder{}, fmt.Errorf("kitchen uuid is invalid - uuid: %s", kitchenUUIDStr)
+		}
+	}
+
+	for _, temp := range splitQueryValues(query["temp"]) {
+		if !entity.IsValidOrderTemp(entity.OrderTemp(temp)) {
+			return kitchenUUID, filter, entity.ShelfSortOrder{}, fmt.Errorf("order temp %s is invalid", temp)
+		}
+		filter.Temps = append(filter.Temps, entity.OrderTemp(temp))
+	}
+
+	for _, status := range splitQueryValues(query["status"]) {
+		if _, ok := entity.AllOrderStatuses[entity.OrderStatus(status)]; !ok {
+			return kitchenUUID, filter, entity.ShelfSortOrder{}, fmt.Errorf("order status %s is invalid", status)
+		}
+		filter.Statuses = append(filter.Statuses, entity.OrderStatus(status))
+	}
+	// Only orders taking up shelf space are listed by default.
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = []entity.OrderStatus{entity.OrderStatusReadyForPickup}
+	}
+
+	sortOrder, err := entity.ParseShelfSortOrder(query.Get("sort"))
+	if err != nil {
+		return kitchenUUID, filter, entity.ShelfSortOrder{}, err
+	}
+
+	return kitchenUUID, filter, sortOrder, nil
+}
+
+// splitQueryValues splits repeated and comma separated query values,
+// ex: ?temp=hot,cold&temp=frozen => ["hot", "cold", "frozen"].
+func splitQueryValues(values []string) []string {
+	var splitValues []string
+	for _, value := range values {
+		for _, splitValue := range strings.Split(value, ",") {
+			if splitValue = strings.TrimSpace(splitValue); splitValue != "" {
+				splitValues = append(splitValues, splitValue)
+			}
+		}
+	}
+
+	return splitValues
+}
+
+func (s *shelfHandler) writeShelfError(w http.ResponseWriter, err error) {
+	switch errors.Cause(err) {
+	case exception.ErrNotFound:
+		msg := fmt.Sprintf("shelf not found - err: %s", err)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(msg))
+	case exception.ErrInvalidInput:
+		msg := fmt.Sprintf("shelves request is invalid - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+	default:
+		msg := fmt.Sprintf("failed to fetch shelves - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+	}
+}
@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// Handler is Webhook handler interface.
+type Handler interface {
+	HandleWebhooks(w http.ResponseWriter, r *http.Request)
+	HandleWebhook(w http.ResponseWriter, r *http.Request)
+}
+
+type webhookHandler struct {
+	cfg      config.AppConfig
+	services service.Services
+}
+
+// NewHandler creates a new HTTP webhook handler instance.
+func NewHandler(appConfig config.AppConfig, services service.Services) Handler {
+	return &webhookHandler{
+		cfg:      appConfig,
+		services: services,
+	}
+}
+
+// HandleWebhooks either lists webhook subscriptions or adds one.
+func (h *webhookHandler) HandleWebhooks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getSubscriptions(w, r)
+	case http.MethodPost:
+		h.createSubscription(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleWebhook routes requests on a specific webhook subscription, ex: /webhooks/{uuid},
+// /webhooks/{uuid}/deliveries and /webhooks/{uuid}/deliveries/{deliveryUUID}/replay.
+func (h *webhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/webhooks"), "/"), "/")
+	subscriptionUUID, err := guuid.FromString(parts[0])
+	if err != nil {
+		msg := fmt.Sprintf("webhook subscription uuid is invalid - uuid: %s", parts[0])
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			h.getSubscription(w, r, subscriptionUUID)
+		case http.MethodDelete:
+			h.deleteSubscription(w, r, subscriptionUUID)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	case len(parts) == 2 && parts[1] == "deliveries":
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.getDeliveries(w, r, subscriptionUUID)
+	case len(parts) == 4 && parts[1] == "deliveries" && parts[3] == "replay":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		deliveryUUID, err := guuid.FromString(parts[2])
+		if err != nil {
+			msg := fmt.Sprintf("webhook delivery uuid is invalid - uuid: %s", parts[2])
+			log.Println(msg)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(msg))
+			return
+		}
+		h.replayDelivery(w, r, subscriptionUUID, deliveryUUID)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (h *webhookHandler) getSubscriptions(w http.ResponseWriter, r *http.Request) {
+	webhookSubscriptions, err := h.services.Webhook.GetSubscriptions()
+	if err != nil {
+		h.writeWebhookError(w, err)
+		return
+	}
+
+	h.writeJSON(w, mapper.WebhookSubscriptionsToJSON(webhookSubscriptions))
+}
+
+func (h *webhookHandler) createSubscription(w http.ResponseWriter, r *http.Request) {
+	// Parse form so we can access key value pairs of the request body.
+	err := r.ParseForm()
+	if err != nil {
+		msg := fmt.Sprintf("failed to parse form - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	formData := endpoint.FormData(r.PostForm)
+	fieldsToExtract := endpoint.FieldsToExtract{
+		RequiredFields: []string{"url", "secret"},
+		OptionalFields: []string{"uuid", "kitchenUUID", "eventTypes"},
+	}
+	webhookSubscriptionRequest := endpoint.WebhookSubscriptionRequest{}
+	err = endpoint.ExtractRequest(formData, fieldsToExtract, &webhookSubscriptionRequest)
+	if err != nil {
+		msg := fmt.Sprintf("failed to handle create webhook subscription request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	webhookSubscription, err := mapper.WebhookSubscriptionRequestToWebhookSubscription(webhookSubscriptionRequest)
+	if err != nil {
+		msg := fmt.Sprintf("failed to map webhook subscription request to webhook subscription - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	err = h.services.Webhook.CreateSubscription(*webhookSubscription)
+	if err != nil {
+		h.writeWebhookError(w, err)
+		return
+	}
+
+	log.Printf("webhook subscription created successfully - %s", webhookSubscription.String())
+
+	// Send back webhook subscription uuid to client on success.
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(webhookSubscription.UUID.String()))
+}
+
+func (h *webhookHandler) getSubscription(w http.ResponseWriter, r *http.Request, subscriptionUUID guuid.UUID) {
+	webhookSubscription, err := h.services.Webhook.GetSubscription(subscriptionUUID)
+	if err != nil {
+		h.writeWebhookError(w, err)
+		return
+	}
+
+	h.writeJSON(w, mapper.WebhookSubscriptionToJSON(*webhookSubscription))
+}
+
+func (h *webhookHandler) deleteSubscription(w http.ResponseWriter, r *http.Request, subscriptionUUID guuid.UUID) {
+	err := h.services.Webhook.DeleteSubscription(subscriptionUUID)
+	if err != nil {
+		h.writeWebhookError(w, err)
+		return
+	}
+
+	log.Printf("webhook subscription deleted successfully - %s", subscriptionUUID.String())
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(subscriptionUUID.String()))
+}
+
+func (h *webhookHandler) getDeliveries(w http.ResponseWriter, r *http.Request, subscriptionUUID guuid.UUID) {
+	webhookDeliveries, err := h.services.Webhook.GetDeliveries(subscriptionUUID)
+	if err != nil {
+		h.writeWebhookError(w, err)
+		return
+	}
+
+	h.writeJSON(w, mapper.WebhookDeliveriesToJSON(webhookDeliveries))
+}
+
+func (h *webhookHandler) replayDelivery(
+	w http.ResponseWriter, r *http.Request, subscriptionUUID guuid.UUID, deliveryUUID guuid.UUID) {
+	webhookDelivery, err := h.services.Webhook.ReplayDelivery(subscriptionUUID, deliveryUUID)
+	if err != nil {
+		h.writeWebhookError(w, err)
+		return
+	}
+
+	log.Printf("webhook delivery %s replayed successfully - %s", deliveryUUID.String(), webhookDelivery.String())
+
+	// Send back the uuid of the new delivery to client on success.
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(webhookDelivery.UUID.String()))
+}
+
+func (h *webhookHandler) writeJSON(w http.ResponseWriter, response interface{}) {
+	content, err := json.Marshal(response)
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal webhooks - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+func (h *webhookHandler) writeWebhookError(w http.ResponseWriter, err error) {
+	switch errors.Cause(err) {
+	case exception.ErrNotFound:
+		msg := fmt.Sprintf("webhook not found - err: %s", err)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(msg))
+	case exception.ErrInvalidInput:
+		msg := fmt.Sprintf("webhook subscription is invalid - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+	default:
+		msg := fmt.Sprintf("failed to handle webhook request - err: %s", err)
+		log.Println(msg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+	}
+}
@@ -1,6 +1,8 @@
 package job
 
 import (
+	"time"
+
 	"github.com/kitchen-delivery/config"
 	"github.com/kitchen-delivery/entity"
 	"github.com/kitchen-delivery/service"
@@ -8,14 +10,79 @@ import (
 
 // Jobs holds both event-driven asynchronous jobs and scheduled jobs.
 type Jobs struct {
-	Order OrderJob
+	Order      OrderJob
+	Webhook    WebhookJob
+	Outbox     OutboxJob
+	Leader     LeaderElector // singleton jobs only run on the leader
+	Scheduler  Scheduler     // runs the periodic work of the jobs above
+	Supervisor Supervisor    // restarts workers that panic
 }
 
 // InitializeJobs creates a new jobs instance.
-func InitializeJobs(cfg config.AppConfig, services service.Services, queues *entity.Queues) Jobs {
-	orderJob := NewOrderJob(cfg, services, queues)
+func InitializeJobs(cfg config.AppConfig, services service.Services, queues *entity.Queues) (Jobs, error) {
+	leader := NewLeaderElector(cfg, queues.Order.Pool)
+	supervisor := NewSupervisor(cfg, services)
+	orderJob := NewOrderJob(cfg, services, queues, supervisor)
+	webhookJob := NewWebhookJob(cfg, services, supervisor)
+	outboxJob, err := NewOutboxJob(cfg, services, queues)
+	if err != nil {
+		return Jobs{}, err
+	}
 
-	return Jobs{
-		Order: orderJob,
+	scheduler := NewScheduler(services, leader)
+	err = scheduler.Register(ScheduledJob{
+		Name:       "remove_expired_orders",
+		Schedule:   Every(5 * time.Second),
+		Jitter:     500 * time.Millisecond,
+		Timeout:    time.Minute,
+		LeaderOnly: true,
+		Run:        orderJob.RemoveExpiredOrders,
+	})
+	if err != nil {
+		return Jobs{}, err
 	}
+
+	err = scheduler.Register(ScheduledJob{
+		Name:       "requeue_cooking_orders",
+		Schedule:   Every(30 * time.Second),
+		Jitter:     time.Second,
+		Timeout:    time.Minute,
+		LeaderOnly: true,
+		Run:        orderJob.RequeueCookingOrders,
+	})
+	if err != nil {
+		return Jobs{}, err
+	}
+
+	err = scheduler.Register(ScheduledJob{
+		Name:       "release_scheduled_orders",
+		Schedule:   Every(time.Second),
+		Timeout:    time.Minute,
+		LeaderOnly: true,
+		Run:        orderJob.ReleaseScheduledOrders,
+	})
+	if err != nil {
+		return Jobs{}, err
+	}
+
+	// Runs never overlap, so events are relayed in the order they were recorded.
+	err = scheduler.Register(ScheduledJob{
+		Name:       "relay_outbox_events",
+		Schedule:   Every(time.Second),
+		Timeout:    time.Minute,
+		LeaderOnly: true,
+		Run:        outboxJob.RelayEvents,
+	})
+	if err != nil {
+		return Jobs{}, err
+	}
+
+	return Jobs{
+		Order:      orderJob,
+		Webhook:    webhookJob,
+		Outbox:     outboxJob,
+		Leader:     leader,
+		Scheduler:  scheduler,
+		Supervisor: supervisor,
+	}, nil
 }
@@ -0,0 +1,155 @@
+package job
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// LeaderElector elects the one instance singleton jobs run on.
+type LeaderElector interface {
+	Run()
+	IsLeader() bool
+}
+
+// renewLeaseScript extends the lease only if this instance still holds it.
+var renewLeaseScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// leaseStore holds the lease instances campaign for.
+type leaseStore interface {
+	acquireLease(key string, holder string, leaseTimeout time.Duration) (bool, error)
+	renewLease(key string, holder string, leaseTimeout time.Duration) (bool, error)
+}
+
+type redisLeaderElector struct {
+	cfg    config.AppConfig
+	leases leaseStore
+	id     string // identifies this instance as the lease holder
+
+	lock           sync.Mutex
+	leaseExpiresAt time.Time // zero while this instance is not the leader
+}
+
+// NewLeaderElector returns a leader elector holding a lease on a Redis key.
+// The leader renews its lease well before it runs out, so when the leader dies
+// another instance acquires the lease once the lease timeout passes.
+func NewLeaderElector(cfg config.AppConfig, pool *redis.Pool) LeaderElector {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &redisLeaderElector{
+		cfg:    cfg,
+		leases: &redisLeaseStore{pool: pool},
+		id:     fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), guuid.NewV4().String()),
+	}
+}
+
+// Run acquires the lease whenever it is free and renews it while this instance leads.
+func (l *redisLeaderElector) Run() {
+	leaseTimeout := time.Duration(l.cfg.Leader.LeaseTimeout) * time.Second
+	for {
+		l.campaign(leaseTimeout)
+
+		// Renewing three times per lease keeps it alive through a missed renewal.
+		time.Sleep(leaseTimeout / 3)
+	}
+}
+
+// IsLeader returns whether this instance holds a lease that has not run out.
+// A leader cut off from Redis steps down once its lease runs out,
+// by which point another instance may have acquired it.
+func (l *redisLeaderElector) IsLeader() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return time.Now().Before(l.leaseExpiresAt)
+}
+
+// campaign renews the lease if this instance holds it, or tries to acquire it otherwise.
+func (l *redisLeaderElector) campaign(leaseTimeout time.Duration) {
+	wasLeader := l.IsLeader()
+	startedAt := time.Now()
+
+	// The lease may still be held in Redis after it ran out locally, so it is renewed first.
+	leads, err := l.leases.renewLease(l.cfg.Leader.Key, l.id, leaseTimeout)
+	if err == nil && !leads {
+		leads, err = l.leases.acquireLease(l.cfg.Leader.Key, l.id, leaseTimeout)
+	}
+	if err != nil {
+		// The lease is kept until it runs out, Redis may be back before then.
+		log.Printf("leader | failed to campaign for leadership - err: %s", err.Error())
+		return
+	}
+
+	l.lock.Lock()
+	if leads {
+		// The lease started before the request was sent, so it never outlives the one in Redis.
+		l.leaseExpiresAt = startedAt.Add(leaseTimeout)
+	} else {
+		l.leaseExpiresAt = time.Time{}
+	}
+	l.lock.Unlock()
+
+	switch {
+	case leads && !wasLeader:
+		log.Printf("leader | %s was elected leader", l.id)
+	case !leads && wasLeader:
+		log.Printf("leader | %s lost leadership", l.id)
+	}
+}
+
+// redisLeaseStore holds leases on Redis keys that expire once they run out.
+type redisLeaseStore struct {
+	pool *redis.Pool
+}
+
+// acquireLease takes the lease if no instance holds it.
+func (r *redisLeaseStore) acquireLease(key string, holder string, leaseTimeout time.Duration) (bool, error) {
+	redisConn := r.pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return false, redisConn.Err()
+	}
+
+	_, err := redis.String(redisConn.Do(
+		"SET", key, holder, "NX", "PX", leaseTimeout.Nanoseconds()/1e6))
+	if err == redis.ErrNil {
+		// Another instance holds the lease.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to acquire lease %s", key)
+	}
+
+	return true, nil
+}
+
+// renewLease extends the lease, false if another instance acquired it in the meantime.
+func (r *redisLeaseStore) renewLease(key string, holder string, leaseTimeout time.Duration) (bool, error) {
+	redisConn := r.pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return false, redisConn.Err()
+	}
+
+	renewed, err := redis.Int(renewLeaseScript.Do(redisConn, key, holder, leaseTimeout.Nanoseconds()/1e6))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to renew lease %s", key)
+	}
+
+	return renewed == 1, nil
+}
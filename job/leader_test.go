@@ -0,0 +1,145 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLeaseStore holds one lease in memory the way Redis does, the lease is free once it runs out.
+type fakeLeaseStore struct {
+	lock      sync.Mutex
+	holder    string
+	expiresAt time.Time
+	err       error // returned by every call while set, ex: Redis is down
+}
+
+func (f *fakeLeaseStore) acquireLease(key string, holder string, leaseTimeout time.Duration) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.err != nil {
+		return false, f.err
+	}
+	if f.holder != "" && time.Now().Before(f.expiresAt) {
+		return false, nil
+	}
+
+	f.holder = holder
+	f.expiresAt = time.Now().Add(leaseTimeout)
+	return true, nil
+}
+
+func (f *fakeLeaseStore) renewLease(key string, holder string, leaseTimeout time.Duration) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.err != nil {
+		return false, f.err
+	}
+	if f.holder != holder || !time.Now().Before(f.expiresAt) {
+		return false, nil
+	}
+
+	f.expiresAt = time.Now().Add(leaseTimeout)
+	return true, nil
+}
+
+// setErr makes every call fail until it is cleared.
+func (f *fakeLeaseStore) setErr(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.err = err
+}
+
+func newTestLeaderElector(leases leaseStore, id string) *redisLeaderElector {
+	cfg := config.AppConfig{}
+	cfg.Leader.Key = "leader"
+
+	return &redisLeaderElector{
+		cfg:    cfg,
+		leases: leases,
+		id:     id,
+	}
+}
+
+func TestLeaderElector_AcquireAndRenew(t *testing.T) {
+	leases := &fakeLeaseStore{}
+	first := newTestLeaderElector(leases, "instance-1")
+	second := newTestLeaderElector(leases, "instance-2")
+
+	// The first instance to campaign acquires the lease.
+	first.campaign(time.Minute)
+	second.campaign(time.Minute)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+
+	// The leader renews its lease instead of acquiring it again.
+	expiresAt := leases.expiresAt
+	first.campaign(time.Minute)
+	assert.True(t, first.IsLeader())
+	assert.Equal(t, "instance-1", leases.holder)
+	assert.True(t, leases.expiresAt.After(expiresAt))
+}
+
+func TestLeaderElector_StepDown(t *testing.T) {
+	leases := &fakeLeaseStore{}
+	leader := newTestLeaderElector(leases, "instance-1")
+	leaseTimeout := 50 * time.Millisecond
+
+	leader.campaign(leaseTimeout)
+	assert.True(t, leader.IsLeader())
+
+	// A leader cut off from Redis keeps its lease until it runs out.
+	leases.setErr(errors.New("connection refused"))
+	leader.campaign(leaseTimeout)
+	assert.True(t, leader.IsLeader())
+
+	time.Sleep(leaseTimeout)
+	assert.False(t, leader.IsLeader())
+
+	// It leads again once Redis is back and the lease is free.
+	leases.setErr(nil)
+	leader.campaign(leaseTimeout)
+	assert.True(t, leader.IsLeader())
+}
+
+func TestLeaderElector_LostLease(t *testing.T) {
+	leases := &fakeLeaseStore{}
+	leader := newTestLeaderElector(leases, "instance-1")
+
+	leader.campaign(time.Minute)
+	assert.True(t, leader.IsLeader())
+
+	// Another instance acquired the lease after it ran out in Redis, ex: the leader was paused.
+	leases.holder = "instance-2"
+	leader.campaign(time.Minute)
+	assert.False(t, leader.IsLeader())
+}
+
+func TestLeaderElector_Failover(t *testing.T) {
+	leases := &fakeLeaseStore{}
+	first := newTestLeaderElector(leases, "instance-1")
+	second := newTestLeaderElector(leases, "instance-2")
+	leaseTimeout := 50 * time.Millisecond
+
+	first.campaign(leaseTimeout)
+	second.campaign(leaseTimeout)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+
+	// The leader dies, another instance takes over once its lease runs out.
+	time.Sleep(leaseTimeout)
+	second.campaign(leaseTimeout)
+	assert.True(t, second.IsLeader())
+	assert.Equal(t, "instance-2", leases.holder)
+
+	// The old leader steps down if it comes back.
+	first.campaign(leaseTimeout)
+	assert.False(t, first.IsLeader())
+}
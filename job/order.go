@@ -1,7 +1,11 @@
 package job
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/kitchen-delivery/config"
@@ -13,24 +17,39 @@ import (
 	guuid "github.com/satori/go.uuid"
 )
 
+// idlePollInterval is how long a worker sleeps once it found nothing to work on.
+const idlePollInterval = 100 * time.Millisecond
+
 // OrderJob is order job interface.
 type OrderJob interface {
 	HandleIncomingOrders()
-	RemoveExpiredOrders()
+	RemoveExpiredOrders(ctx context.Context) error
+	RequeueCookingOrders(ctx context.Context) error
+	ReleaseScheduledOrders(ctx context.Context) error
+	SetMaxWorkers(maxWorkers int)
 }
 
 type orderJob struct {
-	cfg      config.AppConfig
-	services service.Services
-	queues   *entity.Queues
+	cfg        config.AppConfig
+	services   service.Services
+	queues     *entity.Queues
+	supervisor Supervisor
+
+	workersLock sync.Mutex
+	workers     []chan struct{} // closing a worker's channel stops the worker
+
+	requeuedAt map[guuid.UUID]time.Time // when stuck cooking orders were last queued again, only used by the scheduler
 }
 
 // NewOrderJob returns a new order job.
-func NewOrderJob(cfg config.AppConfig, services service.Services, queues *entity.Queues) OrderJob {
+func NewOrderJob(
+	cfg config.AppConfig, services service.Services, queues *entity.Queues, supervisor Supervisor) OrderJob {
 	return &orderJob{
-		cfg:      cfg,
-		services: services,
-		queues:   queues,
+		cfg:        cfg,
+		services:   services,
+		queues:     queues,
+		supervisor: supervisor,
+		requeuedAt: make(map[guuid.UUID]time.Time),
 	}
 }
 
@@ -38,94 +57,423 @@ func NewOrderJob(cfg config.AppConfig, services service.Services, queues *entity
 func (o *orderJob) HandleIncomingOrders() {
 	// Pull order off of shelf queue and spawn a go-routine to retry placing order
 	// on the right shelf.
-	for i := 0; i < o.cfg.WorkerPool.MaxWorkers; i++ {
-		go o.handleIncomingOrder(i)
-	}
-}
-
-func (o *orderJob) handleIncomingOrder(workerNum int) {
-	// Poll redis queue until we stop service.
-	for {
-		// Sleep for 1s before polling Redis queue.
-		time.Sleep(1)
-
-		// Fetch redis connection from redis pool.
-		redisConn := o.queues.Order.Pool.Get() // Fetch redis connection from redis pool.
-		switch redisConn.Err() {
-		case nil:
-			orderUUIDObj, err := redisConn.Do("RPOP", o.queues.Order.Name)
-			redisConn.Close()
-			if err != nil {
-				log.Printf("worker %d failed to fetch order uuid from order queue - err: %+v", workerNum, err)
-				continue
-			}
-			if orderUUIDObj == nil {
-				// Nothing in the queue to pull and work on.
-				continue
-			}
+	o.SetMaxWorkers(o.cfg.WorkerPool.MaxWorkers)
+}
 
-			orderUUIDStr := string(orderUUIDObj.([]uint8))
-			orderUUID, err := guuid.FromString(orderUUIDStr)
-			if err != nil {
-				log.Printf("worker %d order uuid got corrupted - err: %s", workerNum, err.Error())
-				continue
-			}
+// SetMaxWorkers scales the worker pool up or down while it is running.
+// Workers that are stopped finish the order they are cooking first,
+// so no in-flight order is dropped.
+func (o *orderJob) SetMaxWorkers(maxWorkers int) {
+	o.workersLock.Lock()
+	defer o.workersLock.Unlock()
+
+	// Scale up.
+	for len(o.workers) < maxWorkers {
+		stop := make(chan struct{})
+		workerNum := len(o.workers)
+		o.workers = append(o.workers, stop)
+
+		go o.supervisor.Supervise(orderWorkerName(workerNum), func() {
+			o.handleIncomingOrder(workerNum, stop)
+		})
+	}
 
-			log.Printf("worker %d pulled orderUUID %s from order queue", workerNum, orderUUID.String())
+	// Scale down, newest workers stop first.
+	for len(o.workers) > maxWorkers {
+		lastWorker := len(o.workers) - 1
+		close(o.workers[lastWorker])
+		o.workers = o.workers[:lastWorker]
+	}
+
+	log.Printf("order worker pool running %d workers", len(o.workers))
+}
 
-			o.placeOrderOnShelf(orderUUID)
+func (o *orderJob) handleIncomingOrder(workerNum int, stop chan struct{}) {
+	consumer := workerConsumerName(workerNum)
+
+	// Poll redis queue until we stop service or the worker pool shrinks.
+	for turn := 0; ; turn++ {
+		select {
+		case <-stop:
+			log.Printf("worker %d stopped", workerNum)
+			return
 		default:
-			redisConn.Close()
-			err := redisConn.Err()
-			if err != nil {
-				log.Printf("worker %d failed to connect to redis queue, err: %+v", workerNum, err)
+		}
+
+		// Reads off of streams block while they are empty, lists are polled
+		// so idle workers back off instead of spinning on Redis.
+		if !o.handleKitchenQueues(workerNum, consumer, turn) {
+			time.Sleep(idlePollInterval)
+		}
+	}
+}
+
+// workerConsumerName returns the name a worker reads streams as,
+// unique across service instances, ex: "kitchen-1-4242-worker-0".
+func workerConsumerName(workerNum int) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d-worker-%d", hostname, os.Getpid(), workerNum)
+}
+
+// orderWorkerName returns the name an order worker is supervised as, ex: "order-worker-0".
+func orderWorkerName(workerNum int) string {
+	return fmt.Sprintf("order-worker-%d", workerNum)
+}
+
+// handleKitchenQueues pulls the highest priority order off of the queues of every kitchen
+// with a free cooking station at once, then cooks the order on one of its kitchen's stations
+// and places it on a shelf. Orders cook on the worker pulling them, so an instance cooks
+// at most max_workers orders at once and at most cooking.stations orders of each kitchen.
+// Returns whether the worker pulled an order or already waited on the queues.
+func (o *orderJob) handleKitchenQueues(workerNum int, consumer string, turn int) bool {
+	// Orders wait on the order queue while every cooking station of their kitchen is in use.
+	// Workers start at a different kitchen every turn, so kitchens share workers evenly.
+	kitchens := o.services.Kitchen.GetKitchens()
+	var kitchenUUIDs []guuid.UUID
+	for i := range kitchens {
+		kitchen := kitchens[(turn+i)%len(kitchens)]
+		if o.services.Kitchen.HasFreeCookingStation(kitchen.UUID) {
+			kitchenUUIDs = append(kitchenUUIDs, kitchen.UUID)
+		}
+	}
+	if len(kitchenUUIDs) == 0 {
+		return false
+	}
+
+	// Higher priority orders are pulled off of their queue first.
+	queuedOrder := o.pullOrder(workerNum, consumer, kitchenUUIDs)
+	if queuedOrder == nil {
+		return o.queues.Order.Backend == entity.QueueBackendStream
+	}
+
+	// Another worker may take the last free station of the kitchen while the order is pulled.
+	if !o.services.Kitchen.AcquireCookingStation(queuedOrder.KitchenUUID) {
+		o.releaseOrder(workerNum, consumer, *queuedOrder)
+		return true
+	}
+	defer o.services.Kitchen.ReleaseCookingStation(queuedOrder.KitchenUUID)
+
+	o.handleQueuedOrder(workerNum, consumer, *queuedOrder)
+	return true
+}
+
+// handleQueuedOrder prepares an order pulled off of an order queue and acknowledges it,
+// or releases it to be pulled again if preparing it failed.
+func (o *orderJob) handleQueuedOrder(workerNum int, consumer string, queuedOrder entity.QueuedOrder) {
+	// The worker keeps pulling other orders if an order panics. A stream entry is left unacknowledged
+	// and taken over once the claim timeout runs out, an order pulled off of a list is only queued again
+	// if it started cooking.
+	prepared := false
+	defer o.supervisor.RecoverPanic(orderWorkerName(workerNum), "order "+queuedOrder.OrderUUID.String())
+	defer func() {
+		if !prepared && o.queues.Order.Backend != entity.QueueBackendStream {
+			log.Printf("worker %d stopped order %s pulled off of list %s after a panic, "+
+				"it is queued again if it is still cooking after cooking.requeue_after",
+				workerNum, queuedOrder.OrderUUID.String(), queuedOrder.QueueName)
+		}
+	}()
+
+	// The order is no longer kept claimed once it is prepared, so a released order is taken over right away.
+	err := func() error {
+		stopKeepingClaimed := o.keepOrderClaimed(workerNum, consumer, queuedOrder)
+		defer close(stopKeepingClaimed)
+
+		return o.prepareOrder(queuedOrder.OrderUUID)
+	}()
+	prepared = true
+	if err != nil {
+		log.Printf("worker %d failed to prepare order %s, releasing it to be pulled again - err: %s",
+			workerNum, queuedOrder.OrderUUID.String(), err.Error())
+
+		o.releaseOrder(workerNum, consumer, queuedOrder)
+		return
+	}
+
+	err = o.queues.Order.Ack(queuedOrder)
+	if err != nil {
+		log.Printf("worker %d failed to acknowledge order %s - err: %s",
+			workerNum, queuedOrder.OrderUUID.String(), err.Error())
+	}
+}
+
+// releaseOrder gives back an order pulled off of an order queue so it is pulled again.
+func (o *orderJob) releaseOrder(workerNum int, consumer string, queuedOrder entity.QueuedOrder) {
+	err := o.queues.Order.Release(queuedOrder, consumer)
+	if err != nil {
+		// Stream entries are still taken over once the claim timeout runs out,
+		// an order pulled off of a list is only queued again if it is stuck cooking.
+		log.Printf("worker %d failed to release order %s pulled off of %s - err: %s",
+			workerNum, queuedOrder.OrderUUID.String(), queuedOrder.QueueName, err.Error())
+	}
+}
+
+// keepOrderClaimed keeps an order claimed by a worker until the returned channel is closed,
+// so orders that cook for longer than the claim timeout are not taken over by another worker.
+func (o *orderJob) keepOrderClaimed(workerNum int, consumer string, queuedOrder entity.QueuedOrder) chan struct{} {
+	stop := make(chan struct{})
+	if o.queues.Order.Backend != entity.QueueBackendStream {
+		return stop
+	}
+
+	go func() {
+		ticker := time.NewTicker(o.queues.Order.Stream.ClaimTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := o.queues.Order.KeepClaimed(queuedOrder, consumer)
+				if err != nil {
+					log.Printf("worker %d - err: %s", workerNum, err.Error())
+				}
 			}
 		}
+	}()
+
+	return stop
+}
+
+// pullOrder pulls the highest priority order off of the order queues of kitchens, nil if they are empty.
+func (o *orderJob) pullOrder(workerNum int, consumer string, kitchenUUIDs []guuid.UUID) *entity.QueuedOrder {
+	queuedOrder, err := o.queues.Order.Pull(kitchenUUIDs, consumer)
+	if err != nil {
+		log.Printf("worker %d failed to pull order off of order queue - err: %+v", workerNum, err)
+		return nil
+	}
+	if queuedOrder == nil {
+		// Nothing in the queue to pull and work on.
+		return nil
 	}
+
+	log.Printf("worker %d pulled orderUUID %s from order queue %s",
+		workerNum, queuedOrder.OrderUUID.String(), queuedOrder.QueueName)
+
+	return queuedOrder
 }
 
-// placeOrderOnShelf pulls an order off of an order queue and stores it.
-func (o *orderJob) placeOrderOnShelf(orderUUID guuid.UUID) {
+// prepareOrder cooks an order pulled off of an order queue and places it on a shelf.
+// Only failures worth retrying are returned, the order is released to be pulled again for them.
+func (o *orderJob) prepareOrder(orderUUID guuid.UUID) error {
 	order, err := o.services.Order.GetOrder(orderUUID)
 	if err != nil {
-		log.Printf("worker | failed to fetch order - orderUUID: %s", orderUUID.String())
-		return
+		if errors.Cause(err) == exception.ErrNotFound {
+			log.Printf("worker | order not found - skipping order: %s", orderUUID.String())
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to fetch order")
 	}
 
-	err = o.services.Order.PlaceOrderOnShelf(*order)
+	prepTime, err := o.services.Order.StartCooking(*order)
+	if err != nil {
+		if errors.Cause(err) == exception.ErrInvalidResourceState {
+			log.Printf("worker | order was cancelled - skipping order: %s", order.String())
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to start cooking order")
+	}
+
+	// Orders only start to decay once they are cooked and placed on a shelf.
+	if prepTime > 0 {
+		log.Printf("worker | cooking order for %s - %s", prepTime, order.String())
+		time.Sleep(prepTime)
+	}
+
+	// An order released after it started cooking is cooked again once it is pulled again.
+	return o.placeOrderOnShelf(*order)
+}
+
+// placeOrderOnShelf stores a cooked order on a shelf.
+// Only failures worth retrying are returned, ex: a database error.
+func (o *orderJob) placeOrderOnShelf(order entity.Order) error {
+	err := o.services.Order.PlaceOrderOnShelf(order)
 	if err != nil {
 		if errors.Cause(err) == exception.ErrFullShelf {
 			log.Printf("worker | kitchen is over capacity - dropping order: %s", order.String())
-			return
+
+			err = o.services.Order.MarkOrderAsEvicted(order.UUID, "all shelves are full")
+			if err != nil && errors.Cause(err) != exception.ErrInvalidResourceState {
+				return errors.Wrapf(err, "failed to mark dropped order as evicted")
+			}
+			return nil
+		}
+		if errors.Cause(err) == exception.ErrInvalidResourceState {
+			log.Printf("worker | order was cancelled or already placed - skipping order: %s", order.String())
+			return nil
 		}
 
-		log.Printf("worker | failed to place order on shelf, err: %s", err.Error())
-		return
+		return errors.Wrapf(err, "failed to place order on shelf")
 	}
 
 	log.Printf("worker | placed order on correct shelf - %s", order.String())
+	return nil
 }
 
-func (o *orderJob) RemoveExpiredOrders() {
-	// Every 5 seconds, find all food that is wasted and status is "ready_for_pickup".
-	// and update it's status to "wasted".
-	for {
-		time.Sleep(5 * time.Second)
+// RemoveExpiredOrders finds all food that is wasted and status is "ready_for_pickup"
+// and updates its status to "wasted". It is run by the scheduler on the leader only
+// so instances do not race on the same orders.
+func (o *orderJob) RemoveExpiredOrders(ctx context.Context) error {
+	expiredOrdersOnShelf, err := o.services.Order.GetExpiredOrdersOnShelf()
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	for _, shelfOrder := range expiredOrdersOnShelf {
+		// Orders left once the run times out are picked up by the next run.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
-		expiredOrdersOnShelf, err := o.services.Order.GetExpiredOrdersOnShelf()
+		err := o.removeExpiredOrder(*shelfOrder)
 		if err != nil {
+			log.Printf("Failed to mark order as waste %s - err: %s", shelfOrder.String(), err.Error())
+			failures++
 			continue
 		}
+	}
 
-		for _, shelfOrder := range expiredOrdersOnShelf {
-			err := o.removeExpiredOrder(*shelfOrder)
-			if err != nil {
-				log.Printf("Failed to mark order as waste %s - err: %s", shelfOrder.String(), err.Error())
-				continue
-			}
+	if failures > 0 {
+		return errors.Errorf("failed to mark %d of %d expired orders as waste", failures, len(expiredOrdersOnShelf))
+	}
+
+	return nil
+}
+
+// RequeueCookingOrders pushes orders back onto their order queue once they are still cooking
+// cooking.requeue_after seconds after they started, ex: the instance cooking them stopped.
+// Stream entries of those orders are left unacknowledged and taken over by another worker,
+// so only orders pulled off of lists are queued again. It is run by the scheduler on the leader only.
+func (o *orderJob) RequeueCookingOrders(ctx context.Context) error {
+	if o.queues.Order.Backend == entity.QueueBackendStream {
+		return nil
+	}
+
+	stuckOrders, err := o.services.Order.GetStuckCookingOrders()
+	if err != nil {
+		return err
+	}
+
+	// An order queued again keeps the time it first started cooking, so it is only queued again once more
+	// after another cooking.requeue_after. A new leader may queue an order the previous leader just queued,
+	// the order is then skipped by the worker pulling it last.
+	requeueAfter := time.Duration(o.cfg.Cooking.RequeueAfter) * time.Second
+	o.forgetRequeuedOrders(stuckOrders)
+
+	failures := 0
+	for _, order := range stuckOrders {
+		// Orders left once the run times out are queued again by the next run.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		lastRequeuedAt, ok := o.requeuedAt[order.UUID]
+		if ok && time.Since(lastRequeuedAt) < requeueAfter {
+			continue
+		}
+
+		// The order is cooked again from the start once it is pulled.
+		err := o.queues.Order.Push(order.KitchenUUID, order.Priority, order.UUID)
+		if err != nil {
+			log.Printf("Failed to queue stuck cooking order again %s - err: %s", order.String(), err.Error())
+			failures++
+			continue
+		}
+
+		o.requeuedAt[order.UUID] = time.Now()
+		log.Printf("Queued stuck cooking order again %s", order.String())
+	}
+
+	if failures > 0 {
+		return errors.Errorf("failed to queue %d of %d stuck cooking orders again", failures, len(stuckOrders))
+	}
 
+	return nil
+}
+
+// forgetRequeuedOrders forgets orders queued again that are no longer stuck cooking, ex: they were placed.
+func (o *orderJob) forgetRequeuedOrders(stuckOrders []*entity.Order) {
+	stuck := make(map[guuid.UUID]bool, len(stuckOrders))
+	for _, order := range stuckOrders {
+		stuck[order.UUID] = true
+	}
+
+	for orderUUID := range o.requeuedAt {
+		if !stuck[orderUUID] {
+			delete(o.requeuedAt, orderUUID)
+		}
+	}
+}
+
+// ReleaseScheduledOrders places scheduled orders on the order queue once they are due. It is run
+// by the scheduler on the leader only so instances do not race on the same orders.
+func (o *orderJob) ReleaseScheduledOrders(ctx context.Context) error {
+	dueScheduledOrders, err := o.services.ScheduledOrder.GetDueScheduledOrders()
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	for _, scheduledOrder := range dueScheduledOrders {
+		// Orders left once the run times out are released by the next run.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := o.releaseScheduledOrder(*scheduledOrder)
+		if err != nil {
+			log.Printf("scheduler | failed to release scheduled order %s - err: %s", scheduledOrder.String(), err.Error())
+			failures++
+			continue
 		}
 	}
+
+	if failures > 0 {
+		return errors.Errorf("failed to release %d of %d due scheduled orders", failures, len(dueScheduledOrders))
+	}
+
+	return nil
+}
+
+func (o *orderJob) releaseScheduledOrder(scheduledOrder entity.ScheduledOrder) error {
+	order, err := o.services.Order.GetOrder(scheduledOrder.OrderUUID)
+	if err != nil {
+		return err
+	}
+
+	// Record that the order is released before it is visible to workers
+	// the same way orders are marked as queued when they are created.
+	err = o.services.ScheduledOrder.ReleaseScheduledOrder(scheduledOrder)
+	if errors.Cause(err) == exception.ErrVersionInvalid {
+		// The order was cancelled underneath us, there is nothing to release.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = o.queues.Order.Push(order.KitchenUUID, order.Priority, order.UUID)
+	if err != nil {
+		// The order is still due once it is back on the schedule, so it is released again on the next poll.
+		revertErr := o.services.ScheduledOrder.RevertScheduledOrderRelease(scheduledOrder)
+		if revertErr != nil {
+			log.Printf("scheduler | order %s is released but not queued, it has to be queued again - err: %s",
+				scheduledOrder.OrderUUID.String(), revertErr.Error())
+		}
+
+		return err
+	}
+
+	queueName := o.queues.Order.GetKitchenQueueName(order.KitchenUUID, order.Priority)
+	log.Printf("scheduler | released scheduled order to order queue %s - %s", queueName, scheduledOrder.String())
+	return nil
 }
 
 func (o *orderJob) removeExpiredOrder(shelfOrder entity.ShelfOrder) error {
@@ -0,0 +1,166 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+)
+
+// OutboxJob is outbox job interface.
+type OutboxJob interface {
+	RelayEvents(ctx context.Context) error
+}
+
+type outboxJob struct {
+	cfg      config.AppConfig
+	services service.Services
+	sinks    []OutboxSink
+}
+
+// NewOutboxJob returns a new outbox job relaying events to the configured sinks.
+func NewOutboxJob(
+	cfg config.AppConfig, services service.Services, queues *entity.Queues) (OutboxJob, error) {
+	var sinks []OutboxSink
+	for _, sinkType := range cfg.Outbox.Sinks {
+		sink, err := NewOutboxSink(entity.OutboxSinkType(sinkType), cfg, services, queues)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return &outboxJob{
+		cfg:      cfg,
+		services: services,
+		sinks:    sinks,
+	}, nil
+}
+
+// RelayEvents publishes the events recorded in the outbox to every sink, oldest first.
+// An event is only marked published once every sink accepted it, so events are relayed
+// at least once and a sink may see an event again after a crash or a failure of another sink.
+// It is run by the scheduler on the leader only so events reach sinks in the order they were recorded.
+func (o *outboxJob) RelayEvents(ctx context.Context) error {
+	outboxEvents, err := o.services.Outbox.GetUnpublishedEvents()
+	if err != nil {
+		return err
+	}
+
+	for _, outboxEvent := range outboxEvents {
+		// Events left once the run times out are relayed by the next run.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := o.relayEvent(*outboxEvent)
+		if err != nil {
+			log.Printf("outbox | failed to relay event %s - err: %s", outboxEvent.String(), err.Error())
+
+			parked, recordErr := o.services.Outbox.RecordFailedAttempt(*outboxEvent, err)
+			if recordErr != nil {
+				log.Printf("outbox | %s", recordErr.Error())
+			}
+			if parked {
+				// Sinks never receive a parked event, it has to be looked into by hand.
+				log.Printf("outbox | parked event %s after %d attempts, it is no longer relayed",
+					outboxEvent.String(), o.cfg.Outbox.MaxAttempts)
+				continue
+			}
+
+			// Later events wait so sinks receive events in the order they were recorded.
+			return errors.Wrapf(err, "failed to relay event %s", outboxEvent.String())
+		}
+	}
+
+	return nil
+}
+
+// relayEvent publishes an event to every sink and marks it published.
+func (o *outboxJob) relayEvent(outboxEvent entity.OutboxEvent) error {
+	for _, sink := range o.sinks {
+		err := sink.Publish(outboxEvent)
+		if err != nil {
+			return err
+		}
+	}
+
+	return o.services.Outbox.MarkEventPublished(outboxEvent)
+}
+
+// OutboxSink is where outbox events are relayed to.
+type OutboxSink interface {
+	Publish(outboxEvent entity.OutboxEvent) error
+}
+
+// NewOutboxSink returns the outbox sink of a sink type.
+func NewOutboxSink(sinkType entity.OutboxSinkType, cfg config.AppConfig, services service.Services, queues *entity.Queues) (OutboxSink, error) {
+	switch sinkType {
+	case entity.OutboxSinkRedisStream:
+		return &redisStreamSink{cfg: cfg, queues: queues}, nil
+	case entity.OutboxSinkWebhook:
+		return &webhookSink{services: services}, nil
+	case entity.OutboxSinkLog:
+		return &logSink{}, nil
+	default:
+		return nil, fmt.Errorf("outbox sink %s is not supported", sinkType)
+	}
+}
+
+// redisStreamSink appends events to a Redis stream trimmed to about its max length.
+// Consumers drop events whose dedupID they already handled.
+type redisStreamSink struct {
+	cfg    config.AppConfig
+	queues *entity.Queues
+}
+
+func (r *redisStreamSink) Publish(outboxEvent entity.OutboxEvent) error {
+	payload, err := json.Marshal(mapper.EventToJSON(outboxEvent.Event))
+	if err != nil {
+		return errors.Wrap(err, "failed to encode event")
+	}
+
+	redisConn := r.queues.Order.Pool.Get() // Fetch redis connection from redis pool.
+	defer redisConn.Close()
+	if redisConn.Err() != nil {
+		return redisConn.Err()
+	}
+
+	_, err = redisConn.Do("XADD", r.cfg.Outbox.Stream, "MAXLEN", "~", r.cfg.Outbox.StreamMaxLength, "*",
+		"dedupID", outboxEvent.DedupID.String(), "type", string(outboxEvent.Event.Type), "event", payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to append event to stream %s", r.cfg.Outbox.Stream)
+	}
+
+	return nil
+}
+
+// webhookSink hands events to the webhook dispatcher, which stores
+// a single delivery per event and subscription however many times it is relayed.
+type webhookSink struct {
+	services service.Services
+}
+
+func (w *webhookSink) Publish(outboxEvent entity.OutboxEvent) error {
+	return w.services.Webhook.EnqueueDeliveries(outboxEvent)
+}
+
+// logSink writes events to the service log.
+type logSink struct{}
+
+func (l *logSink) Publish(outboxEvent entity.OutboxEvent) error {
+	payload, err := json.Marshal(mapper.EventToJSON(outboxEvent.Event))
+	if err != nil {
+		return errors.Wrap(err, "failed to encode event")
+	}
+
+	log.Printf("outbox | event dedupID=%s %s", outboxEvent.DedupID.String(), payload)
+	return nil
+}
@@ -0,0 +1,214 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxCronLookahead is how far ahead a cron schedule looks for its next run.
+const maxCronLookahead = 5 * 366 * 24 * time.Hour
+
+// Schedule decides when a scheduled job runs next.
+type Schedule interface {
+	// Next returns the first run after a time, zero if the job never runs again.
+	Next(after time.Time) time.Time
+	String() string
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every returns a schedule running a job at a fixed interval, counted from when it was last scheduled.
+func Every(interval time.Duration) Schedule {
+	return &intervalSchedule{interval: interval}
+}
+
+// Next returns the time an interval after a time.
+func (i *intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(i.interval)
+}
+
+// String returns the schedule as it is displayed, ex: "every 5s".
+func (i *intervalSchedule) String() string {
+	return fmt.Sprintf("every %s", i.interval)
+}
+
+// cronField is a set of values a field of a cron spec matches, bit n is set if value n matches.
+type cronField uint64
+
+func (c cronField) has(value int) bool {
+	return c&(1<<uint(value)) != 0
+}
+
+// cronBounds holds the values a field of a cron spec accepts.
+type cronBounds struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	minuteBounds     = cronBounds{"minute", 0, 59}
+	hourBounds       = cronBounds{"hour", 0, 23}
+	dayOfMonthBounds = cronBounds{"day of month", 1, 31}
+	monthBounds      = cronBounds{"month", 1, 12}
+	dayOfWeekBounds  = cronBounds{"day of week", 0, 7} // both 0 and 7 are Sunday
+)
+
+// cronDescriptors holds the shorthands accepted in place of a cron spec.
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+type cronSchedule struct {
+	spec string
+
+	minutes     cronField
+	hours       cronField
+	daysOfMonth cronField
+	months      cronField
+	daysOfWeek  cronField
+	// Like cron, a day matches either of the day fields when neither starts with *.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// ParseCron returns a schedule running a job at the times matched by a standard
+// five field cron spec "minute hour day-of-month month day-of-week" in local time,
+// ex: "*/5 * * * *" or "30 2 * * 1-5". Fields accept *, lists, ranges and steps.
+func ParseCron(spec string) (Schedule, error) {
+	fieldsSpec := spec
+	if descriptor, ok := cronDescriptors[spec]; ok {
+		fieldsSpec = descriptor
+	}
+
+	fields := strings.Fields(fieldsSpec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("cron spec %q must have 5 fields, got %d", spec, len(fields))
+	}
+
+	var err error
+	cron := cronSchedule{
+		spec:          spec,
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}
+	for i, field := range []struct {
+		value  *cronField
+		bounds cronBounds
+	}{
+		{&cron.minutes, minuteBounds},
+		{&cron.hours, hourBounds},
+		{&cron.daysOfMonth, dayOfMonthBounds},
+		{&cron.months, monthBounds},
+		{&cron.daysOfWeek, dayOfWeekBounds},
+	} {
+		*field.value, err = parseCronField(fields[i], field.bounds)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cron spec %q is invalid", spec)
+		}
+	}
+
+	// Sunday is matched as 0.
+	if cron.daysOfWeek.has(7) {
+		cron.daysOfWeek |= 1
+	}
+
+	return &cron, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps, ex: "1,15-30/5".
+func parseCronField(field string, bounds cronBounds) (cronField, error) {
+	var values cronField
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeSpec = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, errors.Errorf("%s step %q must be a positive number", bounds.name, part[i+1:])
+			}
+		}
+
+		start, end := bounds.min, bounds.max
+		if rangeSpec != "*" {
+			var err error
+			startSpec, endSpec := rangeSpec, rangeSpec
+			if i := strings.Index(rangeSpec, "-"); i >= 0 {
+				startSpec, endSpec = rangeSpec[:i], rangeSpec[i+1:]
+			} else if step > 1 {
+				// "5/15" runs from 5 through the end of the range.
+				endSpec = strconv.Itoa(bounds.max)
+			}
+
+			start, err = strconv.Atoi(startSpec)
+			if err != nil {
+				return 0, errors.Errorf("%s %q is not a number", bounds.name, startSpec)
+			}
+			end, err = strconv.Atoi(endSpec)
+			if err != nil {
+				return 0, errors.Errorf("%s %q is not a number", bounds.name, endSpec)
+			}
+		}
+
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, errors.Errorf("%s %q must be within %d-%d", bounds.name, part, bounds.min, bounds.max)
+		}
+
+		for value := start; value <= end; value += step {
+			values |= 1 << uint(value)
+		}
+	}
+
+	return values, nil
+}
+
+// Next returns the first minute after a time matched by the cron spec.
+func (c *cronSchedule) Next(after time.Time) time.Time {
+	next := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute()+1, 0, 0, after.Location())
+	limit := after.Add(maxCronLookahead)
+
+	// Each field that does not match skips ahead to the start of its next value.
+	for next.Before(limit) {
+		switch {
+		case !c.months.has(int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !c.hours.has(next.Hour()):
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case !c.minutes.has(next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	// Specs like "0 0 30 2 *" never match.
+	return time.Time{}
+}
+
+// matchesDay returns whether a day is matched by the day of month and day of week fields.
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth.has(t.Day())
+	dayOfWeek := c.daysOfWeek.has(int(t.Weekday()))
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+// String returns the cron spec.
+func (c *cronSchedule) String() string {
+	return c.spec
+}
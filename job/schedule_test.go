@@ -0,0 +1,52 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	schedule := Every(5 * time.Second)
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, now.Add(5*time.Second), schedule.Next(now))
+	assert.Equal(t, "every 5s", schedule.String())
+}
+
+func TestParseCron(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2020, 1, 1, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2020, 1, 1, 10, 8, 0, 0, time.UTC)},
+		{"*/5 * * * *", time.Date(2020, 1, 1, 10, 10, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2020, 1, 2, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2020, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 6,7", time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 3 *", time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted.
+		{"0 0 10 * 5", time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Never matches.
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		schedule, err := ParseCron(test.spec)
+		assert.Nil(t, err, test.spec)
+		assert.Equal(t, test.next, schedule.Next(now), test.spec)
+		assert.Equal(t, test.spec, schedule.String())
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := ParseCron(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
@@ -0,0 +1,192 @@
+package job
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+)
+
+// Scheduler is scheduler interface.
+type Scheduler interface {
+	Register(scheduledJob ScheduledJob) error
+	Start()
+}
+
+// ScheduledJob is periodic work run by the scheduler.
+type ScheduledJob struct {
+	Name       string        // unique name the job's status is kept under ex: "remove_expired_orders"
+	Schedule   Schedule      // ex: Every(5 * time.Second) or a schedule returned by ParseCron
+	Jitter     time.Duration // max random delay added to every run so instances do not run in lockstep
+	Timeout    time.Duration // the context of a run is cancelled after it, 0 never times out
+	LeaderOnly bool          // only runs on the instance elected leader
+	Run        func(ctx context.Context) error
+}
+
+type scheduler struct {
+	services service.Services
+	leader   LeaderElector
+
+	lock          sync.Mutex
+	started       bool
+	scheduledJobs []ScheduledJob
+	jobStatuses   map[string]*entity.JobStatus
+}
+
+// NewScheduler returns a new scheduler running registered jobs on their schedule.
+// A run is skipped while the job's previous run is still going, and runs that panic
+// are recovered and recorded as failures. The status of every job is recorded
+// with the job service.
+func NewScheduler(services service.Services, leader LeaderElector) Scheduler {
+	return &scheduler{
+		services:    services,
+		leader:      leader,
+		jobStatuses: make(map[string]*entity.JobStatus),
+	}
+}
+
+// Register adds a job to run once the scheduler starts.
+func (s *scheduler) Register(scheduledJob ScheduledJob) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch {
+	case scheduledJob.Name == "":
+		return errors.New("scheduled job name is required")
+	case scheduledJob.Schedule == nil || scheduledJob.Run == nil:
+		return errors.Errorf("scheduled job %s needs a schedule and a run function", scheduledJob.Name)
+	case s.jobStatuses[scheduledJob.Name] != nil:
+		return errors.Errorf("scheduled job %s is registered more than once", scheduledJob.Name)
+	case s.started:
+		return errors.Errorf("scheduled job %s is registered after the scheduler started", scheduledJob.Name)
+	}
+
+	jobStatus := &entity.JobStatus{
+		Name:       scheduledJob.Name,
+		Schedule:   scheduledJob.Schedule.String(),
+		LeaderOnly: scheduledJob.LeaderOnly,
+	}
+	s.scheduledJobs = append(s.scheduledJobs, scheduledJob)
+	s.jobStatuses[scheduledJob.Name] = jobStatus
+	s.services.Job.UpdateJobStatus(*jobStatus)
+
+	return nil
+}
+
+// Start schedules every registered job on its own goroutine.
+func (s *scheduler) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, scheduledJob := range s.scheduledJobs {
+		go s.schedule(scheduledJob)
+	}
+}
+
+// schedule waits for every run of a job and starts it unless the previous run is still going.
+func (s *scheduler) schedule(scheduledJob ScheduledJob) {
+	for {
+		nextRunAt := scheduledJob.Schedule.Next(time.Now())
+		if nextRunAt.IsZero() {
+			log.Printf("scheduler | %s is never scheduled to run again", scheduledJob.Name)
+			return
+		}
+		if scheduledJob.Jitter > 0 {
+			nextRunAt = nextRunAt.Add(time.Duration(rand.Int63n(int64(scheduledJob.Jitter))))
+		}
+		s.updateJobStatus(scheduledJob.Name, func(jobStatus *entity.JobStatus) {
+			jobStatus.NextRunAt = nextRunAt
+		})
+
+		time.Sleep(time.Until(nextRunAt))
+
+		if scheduledJob.LeaderOnly && !s.leader.IsLeader() {
+			continue
+		}
+
+		// Runs never overlap, even once a run timed out but did not stop.
+		started := false
+		s.updateJobStatus(scheduledJob.Name, func(jobStatus *entity.JobStatus) {
+			if jobStatus.Running {
+				jobStatus.Skipped++
+				return
+			}
+
+			jobStatus.Running = true
+			jobStatus.Runs++
+			started = true
+		})
+		if !started {
+			log.Printf("scheduler | skipping %s as its previous run is still going", scheduledJob.Name)
+			continue
+		}
+
+		go s.run(scheduledJob)
+	}
+}
+
+// run runs a job once and records how it went.
+func (s *scheduler) run(scheduledJob ScheduledJob) {
+	ctx := context.Background()
+	if scheduledJob.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scheduledJob.Timeout)
+		defer cancel()
+	}
+
+	startedAt := time.Now()
+	err := s.runSafely(ctx, scheduledJob)
+	if err == nil && ctx.Err() == context.DeadlineExceeded {
+		err = errors.Errorf("run timed out after %s", scheduledJob.Timeout)
+	}
+	duration := time.Since(startedAt)
+
+	if err != nil {
+		log.Printf("scheduler | %s failed after %s - err: %s", scheduledJob.Name, duration, err.Error())
+	}
+
+	s.updateJobStatus(scheduledJob.Name, func(jobStatus *entity.JobStatus) {
+		jobStatus.Running = false
+		jobStatus.LastRunAt = startedAt
+		jobStatus.LastDuration = duration
+		jobStatus.LastError = ""
+		if err != nil {
+			jobStatus.Failures++
+			jobStatus.LastError = err.Error()
+		}
+	})
+}
+
+// runSafely runs a job, recovering a panic as an error so it does not take down the service.
+func (s *scheduler) runSafely(ctx context.Context, scheduledJob ScheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("scheduler | %s panicked: %v\n%s", scheduledJob.Name, r, debug.Stack())
+			err = errors.Errorf("run panicked: %v", r)
+		}
+	}()
+
+	return scheduledJob.Run(ctx)
+}
+
+// updateJobStatus changes a job's status and records it with the job service.
+func (s *scheduler) updateJobStatus(name string, update func(jobStatus *entity.JobStatus)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	jobStatus := s.jobStatuses[name]
+	update(jobStatus)
+	s.services.Job.UpdateJobStatus(*jobStatus)
+}
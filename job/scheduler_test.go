@@ -0,0 +1,82 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// testLeader is a leader elector that is always the leader.
+type testLeader struct{}
+
+func (t *testLeader) Run()           {}
+func (t *testLeader) IsLeader() bool { return true }
+
+func newTestScheduler() (*scheduler, service.Services) {
+	services := service.Services{Job: service.NewJobService(config.AppConfig{})}
+	return NewScheduler(services, &testLeader{}).(*scheduler), services
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s, services := newTestScheduler()
+	scheduledJob := ScheduledJob{
+		Name:     "test",
+		Schedule: Every(time.Second),
+		Run:      func(ctx context.Context) error { return nil },
+	}
+
+	assert.Nil(t, s.Register(scheduledJob))
+	assert.NotNil(t, s.Register(scheduledJob))
+	assert.NotNil(t, s.Register(ScheduledJob{Name: "no-run", Schedule: Every(time.Second)}))
+
+	jobStatuses := services.Job.GetJobStatuses()
+	assert.Equal(t, 1, len(jobStatuses))
+	assert.Equal(t, "every 1s", jobStatuses[0].Schedule)
+
+	s.Start()
+	assert.NotNil(t, s.Register(ScheduledJob{
+		Name: "late", Schedule: Every(time.Second), Run: scheduledJob.Run}))
+}
+
+func TestScheduler_Run(t *testing.T) {
+	s, services := newTestScheduler()
+	runErr := errors.New("database is down")
+	tests := []struct {
+		name string
+		run  func(ctx context.Context) error
+		err  string
+	}{
+		{"succeeds", func(ctx context.Context) error { return nil }, ""},
+		{"fails", func(ctx context.Context) error { return runErr }, "database is down"},
+		{"panics", func(ctx context.Context) error { panic("nil shelf") }, "run panicked: nil shelf"},
+		{"times out", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}, "run timed out after 10ms"},
+	}
+
+	for _, test := range tests {
+		scheduledJob := ScheduledJob{
+			Name:     test.name,
+			Schedule: Every(time.Hour),
+			Timeout:  10 * time.Millisecond,
+			Run:      test.run,
+		}
+		assert.Nil(t, s.Register(scheduledJob))
+
+		s.run(scheduledJob)
+
+		jobStatus, err := services.Job.GetJobStatus(test.name)
+		assert.Nil(t, err)
+		assert.Equal(t, test.err, jobStatus.LastError, test.name)
+		assert.Equal(t, test.err != "", jobStatus.Failures == 1, test.name)
+		assert.False(t, jobStatus.LastRunAt.IsZero())
+		assert.False(t, jobStatus.Running)
+	}
+}
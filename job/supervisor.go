@@ -0,0 +1,97 @@
+package job
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/service"
+)
+
+// Supervisor is supervisor interface.
+type Supervisor interface {
+	Supervise(name string, work func())
+	RecoverPanic(name string, subject string)
+}
+
+type supervisor struct {
+	services       service.Services
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// NewSupervisor returns a new supervisor restarting worker goroutines that panic.
+func NewSupervisor(cfg config.AppConfig, services service.Services) Supervisor {
+	return &supervisor{
+		services:       services,
+		initialBackoff: time.Duration(cfg.Supervisor.InitialBackoff) * time.Second,
+		maxBackoff:     time.Duration(cfg.Supervisor.MaxBackoff) * time.Second,
+	}
+}
+
+// Supervise runs a worker until it returns. A worker that panics is restarted after a backoff
+// that doubles with every crash in a row, and is considered stable again once it runs
+// for longer than the max backoff without crashing.
+func (s *supervisor) Supervise(name string, work func()) {
+	backoff := s.initialBackoff
+	for {
+		startedAt := time.Now()
+		if !s.runSafely(name, work) {
+			// The worker returned on its own, ex: it was stopped.
+			return
+		}
+
+		if time.Since(startedAt) > s.maxBackoff {
+			s.services.Worker.RecordStable(name)
+			backoff = s.initialBackoff
+		}
+
+		log.Printf("supervisor | restarting %s in %s", name, backoff)
+		time.Sleep(backoff)
+		s.services.Worker.RecordRestart(name)
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// runSafely runs a worker, returning whether it panicked.
+func (s *supervisor) runSafely(name string, work func()) (crashed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.recordCrash(name, "", r)
+			crashed = true
+		}
+	}()
+
+	work()
+	return false
+}
+
+// RecoverPanic recovers a panic of work a worker hands off to its own goroutine, logging
+// what was being worked on, ex: "order {uuid}". It has to be deferred directly, work that
+// panicked is not restarted. Ex: defer supervisor.RecoverPanic("order-worker-0", "order "+uuid)
+func (s *supervisor) RecoverPanic(name string, subject string) {
+	if r := recover(); r != nil {
+		s.recordCrash(name, subject, r)
+	}
+}
+
+// recordCrash logs a recovered panic along with its stack and records the crash.
+func (s *supervisor) recordCrash(name string, subject string, r interface{}) {
+	crash := fmt.Sprintf("%v", r)
+	if subject != "" {
+		crash = fmt.Sprintf("%v while working on %s", r, subject)
+	}
+
+	log.Printf("supervisor | %s panicked: %s\n%s", name, crash, debug.Stack())
+
+	workerStatus := s.services.Worker.RecordCrash(name, crash)
+	if workerStatus.CrashLooping {
+		log.Printf("supervisor | %s is crash looping - %s", name, workerStatus.String())
+	}
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSupervisor(crashLoopThreshold int) (*supervisor, service.Services) {
+	cfg := config.AppConfig{}
+	cfg.Supervisor.CrashLoopThreshold = crashLoopThreshold
+	services := service.Services{Worker: service.NewWorkerService(cfg)}
+
+	return &supervisor{
+		services:       services,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     time.Second,
+	}, services
+}
+
+func TestSupervisor_Supervise(t *testing.T) {
+	s, services := newTestSupervisor(2)
+
+	// The worker panics twice, then stops on its own.
+	runs := 0
+	s.Supervise("test-worker", func() {
+		runs++
+		if runs <= 2 {
+			panic("shelf is nil")
+		}
+	})
+
+	assert.Equal(t, 3, runs)
+	workerStatuses := services.Worker.GetWorkerStatuses()
+	assert.Equal(t, 1, len(workerStatuses))
+	assert.Equal(t, "test-worker", workerStatuses[0].Name)
+	assert.Equal(t, 2, workerStatuses[0].Crashes)
+	assert.Equal(t, 2, workerStatuses[0].Restarts)
+	assert.Equal(t, "shelf is nil", workerStatuses[0].LastCrash)
+	assert.Equal(t, workerStatuses, services.Worker.GetCrashLoopingWorkers())
+
+	// Workers that run long enough are stable again.
+	services.Worker.RecordStable("test-worker")
+	assert.Equal(t, 0, len(services.Worker.GetCrashLoopingWorkers()))
+}
+
+func TestSupervisor_RecoverPanic(t *testing.T) {
+	s, services := newTestSupervisor(5)
+
+	func() {
+		defer s.RecoverPanic("order-worker-0", "order 8c3e0f1d")
+		panic("prep time is negative")
+	}()
+
+	workerStatuses := services.Worker.GetWorkerStatuses()
+	assert.Equal(t, 1, len(workerStatuses))
+	assert.Equal(t, "prep time is negative while working on order 8c3e0f1d", workerStatuses[0].LastCrash)
+	assert.Equal(t, 0, workerStatuses[0].Restarts)
+	assert.False(t, workerStatuses[0].CrashLooping)
+}
@@ -0,0 +1,122 @@
+package job
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service"
+
+	"github.com/pkg/errors"
+)
+
+// WebhookJob is webhook job interface.
+// Deliveries are enqueued by the outbox job's webhook sink.
+type WebhookJob interface {
+	HandleDeliveries()
+	SetMaxWorkers(maxWorkers int)
+}
+
+type webhookJob struct {
+	cfg        config.AppConfig
+	services   service.Services
+	supervisor Supervisor
+
+	deliveries chan entity.WebhookDelivery // due deliveries waiting on a free worker
+
+	workersLock sync.Mutex
+	workers     []chan struct{} // closing a worker's channel stops the worker
+}
+
+// NewWebhookJob returns a new webhook job.
+func NewWebhookJob(cfg config.AppConfig, services service.Services, supervisor Supervisor) WebhookJob {
+	return &webhookJob{
+		cfg:        cfg,
+		services:   services,
+		supervisor: supervisor,
+		deliveries: make(chan entity.WebhookDelivery),
+	}
+}
+
+// HandleDeliveries hands due deliveries to the worker pool.
+func (w *webhookJob) HandleDeliveries() {
+	w.SetMaxWorkers(w.cfg.Webhooks.Workers)
+
+	for {
+		time.Sleep(1 * time.Second)
+
+		dueDeliveries, err := w.services.Webhook.GetDueDeliveries()
+		if err != nil {
+			log.Printf("webhook | failed to fetch due deliveries - err: %s", err.Error())
+			continue
+		}
+
+		// Blocks until a worker is free, so a slow endpoint only holds up its own worker.
+		for _, webhookDelivery := range dueDeliveries {
+			w.deliveries <- *webhookDelivery
+		}
+	}
+}
+
+// SetMaxWorkers scales the worker pool up or down while it is running.
+// Workers that are stopped finish the delivery they are sending first.
+func (w *webhookJob) SetMaxWorkers(maxWorkers int) {
+	w.workersLock.Lock()
+	defer w.workersLock.Unlock()
+
+	// Scale up.
+	for len(w.workers) < maxWorkers {
+		stop := make(chan struct{})
+		workerNum := len(w.workers)
+		w.workers = append(w.workers, stop)
+
+		go w.supervisor.Supervise(fmt.Sprintf("webhook-worker-%d", workerNum), func() {
+			w.handleDelivery(workerNum, stop)
+		})
+	}
+
+	// Scale down, newest workers stop first.
+	for len(w.workers) > maxWorkers {
+		lastWorker := len(w.workers) - 1
+		close(w.workers[lastWorker])
+		w.workers = w.workers[:lastWorker]
+	}
+
+	log.Printf("webhook worker pool running %d workers", len(w.workers))
+}
+
+func (w *webhookJob) handleDelivery(workerNum int, stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			log.Printf("webhook worker %d stopped", workerNum)
+			return
+		case webhookDelivery := <-w.deliveries:
+			w.deliver(workerNum, webhookDelivery)
+		}
+	}
+}
+
+// deliver sends a delivery to its subscription.
+func (w *webhookJob) deliver(workerNum int, webhookDelivery entity.WebhookDelivery) {
+	// A delivery that panics is retried once it is due again, the worker keeps sending other deliveries.
+	defer w.supervisor.RecoverPanic(
+		fmt.Sprintf("webhook-worker-%d", workerNum), "webhook delivery "+webhookDelivery.UUID.String())
+
+	err := w.services.Webhook.Deliver(webhookDelivery)
+	if err != nil {
+		if errors.Cause(err) == exception.ErrVersionInvalid {
+			// Another worker claimed the delivery, this is not an exceptional case.
+			return
+		}
+
+		log.Printf("webhook worker %d failed to send delivery %s - err: %s", workerNum, webhookDelivery.String(), err.Error())
+		return
+	}
+
+	log.Printf("webhook worker %d sent delivery - %s", workerNum, webhookDelivery.String())
+}
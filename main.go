@@ -1,15 +1,24 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
 	"github.com/kitchen-delivery/config"
 	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
 	"github.com/kitchen-delivery/handler"
+	"github.com/kitchen-delivery/handler/rpc"
 	"github.com/kitchen-delivery/job"
+	"github.com/kitchen-delivery/mapper"
 	"github.com/kitchen-delivery/service"
 	"github.com/kitchen-delivery/service/repository"
 
@@ -17,14 +26,44 @@ import (
 	_ "github.com/jinzhu/gorm/dialects/mysql"
 )
 
+// defaultMenuSeedFile is the input file the menu catalog is seeded from.
+const defaultMenuSeedFile = "data/input.json"
+
 func main() {
+	// `kitchen-delivery config check [--config file]` validates
+	// configuration without starting the server.
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "check" {
+		checkConfig(os.Args[3:])
+		return
+	}
+
+	// `kitchen-delivery menu seed [file] [--config file]` builds
+	// the menu catalog from an input file of orders.
+	if len(os.Args) > 2 && os.Args[1] == "menu" && os.Args[2] == "seed" {
+		seedMenu(os.Args[3:])
+		return
+	}
+
 	log.Print("Starting Kitchen Delivery ....")
 
-	// Load application configuration.
+	// Load application configuration, environment variables
+	// and command line flags override the yaml file.
 	cfg := config.AppConfig{}
-	err := cfg.LoadConfig("config/development.yaml")
+	err := cfg.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Failed to load configuration - err: %+v", err)
+	}
+
+	// Refuse to start with a broken setup.
+	err = cfg.Validate()
+	if err != nil {
+		log.Fatalf("Failed to validate configuration - err: %s", err)
+	}
+
+	// Order temps and shelf types are validated against the shelves of every kitchen.
+	err = useShelfCatalogs(cfg)
 	if err != nil {
-		log.Fatalf("Failed to load configuration from yaml files - err: %+v", err)
+		log.Fatalf("Failed to load kitchens - err: %+v", err)
 	}
 
 	////////////////////////////////////////
@@ -40,27 +79,39 @@ func main() {
 
 	// Open connection to Redis instance.
 	// Use this as a first in first out queue.
-	// TODO: Move this to a configuration.
+	redisConfig := cfg.Databases.Redis
+	dialRedis := func() (redis.Conn, error) {
+		redisConn, err := redis.Dial("tcp", redisConfig.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		return redisConn, nil
+	}
 	redisPool := &redis.Pool{
-		MaxIdle:     5,
-		MaxActive:   5,
-		IdleTimeout: 20 * time.Second,
+		MaxIdle:     redisConfig.MaxIdle,
+		MaxActive:   redisConfig.MaxActive,
+		IdleTimeout: time.Duration(redisConfig.IdleTimeout) * time.Second,
 		Wait:        true,
-		Dial: func() (redis.Conn, error) {
-			redisConn, err := redis.Dial("tcp", ":6379")
-			if err != nil {
-				return nil, err
-			}
+		Dial:        dialRedis,
+	}
 
-			return redisConn, nil
-		},
+	// Order workers pull orders on their own connections, as reads off of streams block
+	// while they are empty. Every worker holds at most one, so the pool is not capped.
+	redisReadPool := &redis.Pool{
+		MaxIdle:     cfg.WorkerPool.MaxWorkers,
+		IdleTimeout: time.Duration(redisConfig.IdleTimeout) * time.Second,
+		Dial:        dialRedis,
 	}
 
 	////////////////////////////////////////
 	// Service Initialization
 	////////////////////////////////////////
 	repositories := repository.InitializeRepositories(db)
-	services := service.InitializeServices(cfg, repositories)
+	services, err := service.InitializeServices(cfg, repositories)
+	if err != nil {
+		log.Fatalf("Failed to initialize services - err: %+v", err)
+	}
 
 	////////////////////////////////////////
 	// Local Queue Initialization
@@ -69,22 +120,58 @@ func main() {
 		// We pass redis pool by reference
 		// as it contains mutex lock.
 		Order: entity.Queue{
-			Name: "Order",
-			Pool: redisPool,
+			Name:     "Order",
+			Pool:     redisPool,
+			ReadPool: redisReadPool,
+			Backend:  entity.QueueBackend(cfg.Queue.Backend),
+			Stream:   cfg.Queue.GetStreamOptions(),
 		},
 	}
 
 	////////////////////////////////////////
 	// Job & Worker Initialization
 	////////////////////////////////////////
-	jobs := job.InitializeJobs(cfg, services, queues)
+	jobs, err := job.InitializeJobs(cfg, services, queues)
+	if err != nil {
+		log.Fatalf("Failed to initialize jobs - err: %+v", err)
+	}
+
+	// Long running threads are supervised, a thread that panics is restarted with backoff
+	// and threads that keep crashing fail the health check. Crashes are counted at /debug/vars.
+
+	// Spawn thread to elect the instance the singleton jobs below run on,
+	// every instance runs them but only the leader does any work.
+	go jobs.Supervisor.Supervise("leader-election", jobs.Leader.Run)
 
 	// Spawn workers to pull orders off of order queue
 	// as orders come in.
 	go jobs.Order.HandleIncomingOrders()
 
-	// Spawn thread to remove expired orders.
-	go jobs.Order.RemoveExpiredOrders()
+	// Start scheduled jobs, ex: removing expired orders, releasing scheduled orders
+	// and relaying outbox events to the configured sinks, their runs are listed at /jobs.
+	jobs.Scheduler.Start()
+
+	// Spawn thread to send webhook deliveries on their own worker pool.
+	go jobs.Supervisor.Supervise("webhook-dispatcher", jobs.Webhook.HandleDeliveries)
+
+	////////////////////////////////////////
+	// Configuration Reload
+	////////////////////////////////////////
+
+	// Shelf capacities and the worker pool sizes are applied without a restart
+	// when the config file changes or the service receives SIGHUP.
+	reloader := config.NewReloader(cfg, os.Args[1:])
+	reloader.OnReload(func(cfg config.AppConfig) {
+		kitchens, err := cfg.GetKitchens()
+		if err != nil {
+			log.Printf("Failed to reload kitchens - err: %s", err)
+		} else {
+			services.Kitchen.UpdateKitchens(kitchens)
+		}
+		jobs.Order.SetMaxWorkers(cfg.WorkerPool.MaxWorkers)
+		jobs.Webhook.SetMaxWorkers(cfg.Webhooks.Workers)
+	})
+	go reloader.Run()
 
 	////////////////////////////////////////
 	// Handler Initialization
@@ -101,17 +188,161 @@ func main() {
 	// Register service health and simulation routes.
 	http.HandleFunc("/health", handlers.Health.CheckHealth)
 	http.HandleFunc("/health/simulate", handlers.Health.Simulate)
+	http.HandleFunc("/health/simulate/report", handlers.Health.SimulationReport)
 
 	// Register order routes.
 	http.HandleFunc("/order", handlers.Order.HandleOrder)
+	http.HandleFunc("/orders/", handlers.Order.HandleOrders)
+
+	// Register parent order routes, a parent order holds several items.
+	http.HandleFunc("/parent-orders", handlers.ParentOrder.HandleParentOrder)
+	http.HandleFunc("/parent-orders/", handlers.ParentOrder.HandleParentOrders)
+
+	// Register menu catalog routes.
+	http.HandleFunc("/menu", handlers.Menu.HandleMenu)
+	http.HandleFunc("/menu/", handlers.Menu.HandleMenuItem)
+
+	// Register courier pickup routes.
+	http.HandleFunc("/pickup", handlers.Pickup.HandlePickup)
+
+	// Register shelf snapshot routes for the kitchen display screen.
+	http.HandleFunc("/shelves", handlers.Shelf.HandleShelves)
+	http.HandleFunc("/shelves/", handlers.Shelf.HandleShelves)
+
+	// Register the order event stream for kitchen displays and driver apps.
+	http.HandleFunc("/events", handlers.Event.StreamEvents)
+
+	// Register the driver channel, drivers are pushed their order once they arrive.
+	http.HandleFunc("/drivers/ws", handlers.Driver.HandleDriverChannel)
+
+	// Register webhook subscription routes, partners are called back on order status changes.
+	http.HandleFunc("/webhooks", handlers.Webhook.HandleWebhooks)
+	http.HandleFunc("/webhooks/", handlers.Webhook.HandleWebhook)
+
+	// Register scheduled job routes, reporting each job's last run on this instance.
+	http.HandleFunc("/jobs", handlers.Job.HandleJobs)
+	http.HandleFunc("/jobs/", handlers.Job.HandleJobs)
+
+	////////////////////////////////////////
+	// gRPC Server Initialization
+	////////////////////////////////////////
+
+	// Internal services call the same services over gRPC on their own port.
+	grpcListener, err := net.Listen("tcp", cfg.GRPC.Address)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC address %s - err: %+v", cfg.GRPC.Address, err)
+	}
+	grpcServer := rpc.NewServer(cfg, services, queues)
+	go func() {
+		err := grpcServer.Serve(grpcListener)
+		if err != nil {
+			log.Printf("gRPC server stopped - err: %s", err)
+		}
+	}()
 
 	log.Print("Kitchen Delivery online ....")
 
 	// Mount server and listen on HTTP port.
-	http.ListenAndServe(":8080", nil)
+	http.ListenAndServe(cfg.HTTP.Address, nil)
 
 	// Block indefinitely to keep server alive.
 	switch {
 
 	}
 }
+
+// checkConfig loads and validates configuration then exits
+// with a non-zero status code if it is invalid.
+func checkConfig(args []string) {
+	cfg := config.AppConfig{}
+	err := cfg.Load(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load configuration: %s\n", err)
+		os.Exit(1)
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println("configuration is valid")
+}
+
+// useShelfCatalogs makes the shelves of every kitchen the only valid ones.
+func useShelfCatalogs(cfg config.AppConfig) error {
+	kitchens, err := cfg.GetKitchens()
+	if err != nil {
+		return err
+	}
+
+	var shelfCatalogs []*entity.ShelfCatalog
+	for _, kitchen := range kitchens {
+		shelfCatalogs = append(shelfCatalogs, kitchen.ShelfCatalog)
+	}
+	entity.UseShelfCatalogs(shelfCatalogs...)
+
+	return nil
+}
+
+// seedMenu adds every item of an input file of orders to the menu catalog,
+// items already on the menu are left untouched so seeding can be repeated.
+func seedMenu(args []string) {
+	seedFile := defaultMenuSeedFile
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		seedFile = args[0]
+		args = args[1:]
+	}
+
+	cfg := config.AppConfig{}
+	err := cfg.Load(args)
+	if err != nil {
+		log.Fatalf("Failed to load configuration - err: %+v", err)
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		log.Fatalf("Failed to validate configuration - err: %s", err)
+	}
+
+	// Menu item temps are validated against the shelves of every kitchen.
+	err = useShelfCatalogs(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load kitchens - err: %+v", err)
+	}
+
+	ordersByteArray, err := ioutil.ReadFile(seedFile)
+	if err != nil {
+		log.Fatalf("Failed to read menu seed file %s - err: %s", seedFile, err)
+	}
+
+	var orders []endpoint.OrderJSON
+	err = json.Unmarshal(ordersByteArray, &orders)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal menu seed file %s - err: %s", seedFile, err)
+	}
+
+	var menuItems []entity.MenuItem
+	for _, order := range orders {
+		menuItem, err := mapper.OrderJSONToMenuItem(order)
+		if err != nil {
+			log.Fatalf("Failed to map %s to a menu item - err: %s", order.Name, err)
+		}
+		menuItems = append(menuItems, *menuItem)
+	}
+
+	db, err := gorm.Open("mysql", cfg.Databases.MySQL.GetConnectionString())
+	if err != nil {
+		log.Fatalf("Failed to connect to mysql database %+v", err)
+	}
+	defer db.Close()
+
+	menuService := service.NewMenuService(cfg, repository.NewMenuItemRepository(db))
+	err = menuService.SeedMenu(menuItems)
+	if err != nil {
+		log.Fatalf("Failed to seed menu - err: %+v", err)
+	}
+
+	fmt.Printf("menu seeded with %d items from %s\n", len(menuItems), seedFile)
+}
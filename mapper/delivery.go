@@ -0,0 +1,75 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// DeliveryToRecord maps a delivery entity to a delivery record.
+func DeliveryToRecord(delivery entity.Delivery) record.Delivery {
+	record := record.Delivery{
+		UUID:            delivery.UUID.String(),
+		OrderUUID:       delivery.OrderUUID.String(),
+		ShelfOrderUUID:  delivery.ShelfOrderUUID.String(),
+		OrderAgeMs:      int64(delivery.OrderAge / time.Millisecond),
+		Value:           delivery.Value,
+		NormalizedValue: delivery.NormalizedValue,
+		DeliveredAt:     delivery.DeliveredAt,
+	}
+
+	// We set a random uuid for delivery if there is not one passed in.
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == delivery.UUID {
+		record.UUID = guuid.NewV4().String()
+	}
+
+	return record
+}
+
+// RecordToDelivery maps a delivery record to a delivery entity.
+func RecordToDelivery(record record.Delivery) (*entity.Delivery, error) {
+	uuid, err := guuid.FromString(record.UUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "uuid is not valid, uuid: %s", record.UUID)
+	}
+
+	orderUUID, err := guuid.FromString(record.OrderUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "order uuid is not valid, uuid: %s", record.OrderUUID)
+	}
+
+	shelfOrderUUID, err := guuid.FromString(record.ShelfOrderUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "shelf order uuid is not valid, uuid: %s", record.ShelfOrderUUID)
+	}
+
+	delivery := entity.Delivery{
+		UUID:            uuid,
+		OrderUUID:       orderUUID,
+		ShelfOrderUUID:  shelfOrderUUID,
+		OrderAge:        time.Duration(record.OrderAgeMs) * time.Millisecond,
+		Value:           record.Value,
+		NormalizedValue: record.NormalizedValue,
+		DeliveredAt:     record.DeliveredAt,
+	}
+
+	return &delivery, nil
+}
+
+// DeliveryToJSON maps a delivery entity to a JSON response.
+func DeliveryToJSON(delivery entity.Delivery) endpoint.DeliveryJSON {
+	return endpoint.DeliveryJSON{
+		OrderUUID:       delivery.OrderUUID.String(),
+		OrderAgeMs:      int64(delivery.OrderAge / time.Millisecond),
+		Value:           delivery.Value,
+		NormalizedValue: delivery.NormalizedValue,
+		IsAcceptable:    delivery.IsAcceptable(),
+		DeliveredAt:     delivery.DeliveredAt,
+	}
+}
@@ -0,0 +1,22 @@
+package mapper
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+)
+
+// OrderToDriverOrderJSON maps an order entity to the order details handed to a driver.
+func OrderToDriverOrderJSON(order entity.Order) endpoint.DriverOrderJSON {
+	priority := order.Priority
+	if priority == "" {
+		priority = entity.OrderPriorityStandard
+	}
+
+	return endpoint.DriverOrderJSON{
+		UUID:        order.UUID.String(),
+		KitchenUUID: order.KitchenUUID.String(),
+		Name:        order.Name,
+		Temp:        string(order.Temp),
+		Priority:    string(priority),
+	}
+}
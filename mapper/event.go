@@ -0,0 +1,21 @@
+package mapper
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+)
+
+// EventToJSON maps an event entity to an event stream response.
+func EventToJSON(event entity.Event) endpoint.EventJSON {
+	return endpoint.EventJSON{
+		ID:          event.ID,
+		Type:        string(event.Type),
+		KitchenUUID: event.KitchenUUID.String(),
+		OrderUUID:   event.OrderUUID.String(),
+		ShelfType:   string(event.ShelfType),
+		FromStatus:  string(event.FromStatus),
+		ToStatus:    string(event.ToStatus),
+		Reason:      event.Reason,
+		OccurredAt:  event.OccurredAt,
+	}
+}
@@ -0,0 +1,38 @@
+package mapper
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+)
+
+// JobStatusesToJSON maps job status entities to a jobs response.
+func JobStatusesToJSON(jobStatuses []entity.JobStatus) []endpoint.JobStatusJSON {
+	jobStatusesJSON := make([]endpoint.JobStatusJSON, 0, len(jobStatuses))
+	for _, jobStatus := range jobStatuses {
+		jobStatusesJSON = append(jobStatusesJSON, JobStatusToJSON(jobStatus))
+	}
+
+	return jobStatusesJSON
+}
+
+// JobStatusToJSON maps a job status entity to a job response.
+func JobStatusToJSON(jobStatus entity.JobStatus) endpoint.JobStatusJSON {
+	var lastDuration string
+	if !jobStatus.LastRunAt.IsZero() {
+		lastDuration = jobStatus.LastDuration.String()
+	}
+
+	return endpoint.JobStatusJSON{
+		Name:         jobStatus.Name,
+		Schedule:     jobStatus.Schedule,
+		LeaderOnly:   jobStatus.LeaderOnly,
+		Running:      jobStatus.Running,
+		Runs:         jobStatus.Runs,
+		Failures:     jobStatus.Failures,
+		Skipped:      jobStatus.Skipped,
+		LastRunAt:    optionalTimeToRecord(jobStatus.LastRunAt),
+		LastDuration: lastDuration,
+		LastError:    jobStatus.LastError,
+		NextRunAt:    optionalTimeToRecord(jobStatus.NextRunAt),
+	}
+}
@@ -0,0 +1,175 @@
+package mapper
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// MenuItemRequestToMenuItem maps a HTTP menu item request to a menu item entity.
+func MenuItemRequestToMenuItem(menuItemRequest endpoint.MenuItemRequest) (*entity.MenuItem, error) {
+	shelfLife, err := strconv.ParseInt(menuItemRequest.ShelfLife, 0, 32)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "failed to parse int shelf life %s", menuItemRequest.ShelfLife)
+	}
+
+	decayRate, err := strconv.ParseFloat(menuItemRequest.DecayRate, 64)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "failed to parse float decay rate %s", menuItemRequest.DecayRate)
+	}
+
+	prepTime, err := parsePrepTime(menuItemRequest.PrepTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// We support idempotency by checking if a menu item UUID is passed,
+	// otherwise we generate a new one.
+	menuItemUUID := guuid.NewV4()
+	if menuItemRequest.UUID != "" {
+		menuItemUUID, err = guuid.FromString(menuItemRequest.UUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "menu item request uuid is invalid - uuid: %s", menuItemRequest.UUID)
+		}
+	}
+
+	menuItem := entity.MenuItem{
+		UUID:      menuItemUUID,
+		Name:      menuItemRequest.Name,
+		Temp:      entity.OrderTemp(menuItemRequest.Temp),
+		ShelfLife: int(shelfLife),
+		DecayRate: decayRate,
+		PrepTime:  prepTime,
+	}
+
+	err = menuItem.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &menuItem, nil
+}
+
+// OrderJSONToMenuItem maps an order from input.json to a menu item entity
+// used to seed the menu catalog.
+func OrderJSONToMenuItem(orderJSON endpoint.OrderJSON) (*entity.MenuItem, error) {
+	menuItem := entity.MenuItem{
+		UUID:      entity.NewMenuItemUUID(orderJSON.Name),
+		Name:      orderJSON.Name,
+		Temp:      entity.OrderTemp(orderJSON.Temp),
+		ShelfLife: orderJSON.ShelfLife,
+		DecayRate: orderJSON.DecayRate,
+		PrepTime:  orderJSON.PrepTime,
+	}
+
+	err := menuItem.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &menuItem, nil
+}
+
+// ApplyMenuItem fills in the fields of a create order request
+// that are not passed in from the menu item the order is created from.
+func ApplyMenuItem(createOrderRequest endpoint.CreateOrderRequest, menuItem entity.MenuItem) endpoint.CreateOrderRequest {
+	createOrderRequest.MenuItemUUID = menuItem.UUID.String()
+	if createOrderRequest.Name == "" {
+		createOrderRequest.Name = menuItem.Name
+	}
+	if createOrderRequest.Temp == "" {
+		createOrderRequest.Temp = string(menuItem.Temp)
+	}
+	if createOrderRequest.ShelfLife == "" {
+		createOrderRequest.ShelfLife = fmt.Sprintf("%d", menuItem.ShelfLife)
+	}
+	if createOrderRequest.DecayRate == "" {
+		createOrderRequest.DecayRate = strconv.FormatFloat(menuItem.DecayRate, 'f', -1, 64)
+	}
+	if createOrderRequest.PrepTime == "" && menuItem.PrepTime > 0 {
+		createOrderRequest.PrepTime = fmt.Sprintf("%d", menuItem.PrepTime)
+	}
+
+	return createOrderRequest
+}
+
+// MenuItemToRecord maps a menu item entity to a menu item record.
+func MenuItemToRecord(menuItem entity.MenuItem) record.MenuItem {
+	record := record.MenuItem{
+		UUID:      menuItem.UUID.String(),
+		Name:      menuItem.Name,
+		Temp:      string(menuItem.Temp),
+		ShelfLife: menuItem.ShelfLife,
+		DecayRate: menuItem.DecayRate,
+		PrepTime:  menuItem.PrepTime,
+		CreatedAt: menuItem.CreatedAt,
+		UpdatedAt: menuItem.UpdatedAt,
+	}
+
+	// We set a random uuid for menu item if there is not one passed in.
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == menuItem.UUID {
+		record.UUID = guuid.NewV4().String()
+	}
+
+	return record
+}
+
+// RecordToMenuItem maps a menu item record to a menu item entity.
+func RecordToMenuItem(record record.MenuItem) (*entity.MenuItem, error) {
+	uuid, err := guuid.FromString(record.UUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "uuid is not valid, uuid: %s", record.UUID)
+	}
+
+	menuItem := entity.MenuItem{
+		UUID:      uuid,
+		Name:      record.Name,
+		Temp:      entity.OrderTemp(record.Temp),
+		ShelfLife: record.ShelfLife,
+		DecayRate: record.DecayRate,
+		PrepTime:  record.PrepTime,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+
+	err = menuItem.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &menuItem, nil
+}
+
+// MenuItemToJSON maps a menu item entity to a menu item response.
+func MenuItemToJSON(menuItem entity.MenuItem) endpoint.MenuItemJSON {
+	return endpoint.MenuItemJSON{
+		UUID:      menuItem.UUID.String(),
+		Name:      menuItem.Name,
+		Temp:      string(menuItem.Temp),
+		ShelfLife: menuItem.ShelfLife,
+		DecayRate: menuItem.DecayRate,
+		PrepTime:  menuItem.PrepTime,
+		CreatedAt: menuItem.CreatedAt,
+		UpdatedAt: menuItem.UpdatedAt,
+	}
+}
+
+// MenuItemsToJSON maps menu item entities to a menu response.
+func MenuItemsToJSON(menuItems []entity.MenuItem) []endpoint.MenuItemJSON {
+	menuItemsJSON := make([]endpoint.MenuItemJSON, 0, len(menuItems))
+	for _, menuItem := range menuItems {
+		menuItemsJSON = append(menuItemsJSON, MenuItemToJSON(menuItem))
+	}
+
+	return menuItemsJSON
+}
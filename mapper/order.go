@@ -2,6 +2,7 @@ package mapper
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/kitchen-delivery/entity"
 	"github.com/kitchen-delivery/entity/endpoint"
@@ -25,6 +26,21 @@ func CreateOrderRequestToOrder(createOrderRequest endpoint.CreateOrderRequest) (
 			err, "failed to parse float decay rate %s", createOrderRequest.DecayRate)
 	}
 
+	prepTime, err := parsePrepTime(createOrderRequest.PrepTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders that do not set a ready time are wanted as soon as possible.
+	var readyAt time.Time
+	if createOrderRequest.ReadyAt != "" {
+		readyAt, err = time.Parse(time.RFC3339, createOrderRequest.ReadyAt)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "failed to parse RFC 3339 ready at %s", createOrderRequest.ReadyAt)
+		}
+	}
+
 	// We support idempotency by checking if an order UUID is passed.
 	// If it is, we convert it to a UUID.
 	var orderUUID guuid.UUID
@@ -40,12 +56,36 @@ func CreateOrderRequestToOrder(createOrderRequest endpoint.CreateOrderRequest) (
 		orderUUID = guuid.NewV4()
 	}
 
+	// Orders that do not name a kitchen go to the default kitchen.
+	kitchenUUID := entity.DefaultKitchenUUID
+	if createOrderRequest.KitchenUUID != "" {
+		kitchenUUID, err = guuid.FromString(createOrderRequest.KitchenUUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "create order request kitchen uuid is invalid - uuid: %s", createOrderRequest.KitchenUUID)
+		}
+	}
+
+	var menuItemUUID guuid.UUID
+	if createOrderRequest.MenuItemUUID != "" {
+		menuItemUUID, err = guuid.FromString(createOrderRequest.MenuItemUUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "create order request menu item uuid is invalid - uuid: %s", createOrderRequest.MenuItemUUID)
+		}
+	}
+
 	order := entity.Order{
-		UUID:      orderUUID,
-		Name:      createOrderRequest.Name,
-		Temp:      entity.OrderTemp(createOrderRequest.Temp),
-		ShelfLife: int(shelfLife),
-		DecayRate: decayRate,
+		UUID:         orderUUID,
+		KitchenUUID:  kitchenUUID,
+		MenuItemUUID: menuItemUUID,
+		Name:         createOrderRequest.Name,
+		Temp:         entity.OrderTemp(createOrderRequest.Temp),
+		Priority:     toOrderPriority(createOrderRequest.Priority),
+		ShelfLife:    int(shelfLife),
+		DecayRate:    decayRate,
+		PrepTime:     prepTime,
+		ReadyAt:      readyAt,
 	}
 
 	err = order.Validate()
@@ -59,12 +99,15 @@ func CreateOrderRequestToOrder(createOrderRequest endpoint.CreateOrderRequest) (
 // OrderToRecord maps an order entity to an order record.
 func OrderToRecord(order entity.Order) (*record.Order, error) {
 	record := record.Order{
-		UUID:      order.UUID.String(),
-		Name:      order.Name,
-		Temp:      string(order.Temp),
-		ShelfLife: order.ShelfLife,
-		DecayRate: order.DecayRate,
-		CreatedAt: order.CreatedAt,
+		UUID:        order.UUID.String(),
+		KitchenUUID: order.KitchenUUID.String(),
+		Name:        order.Name,
+		Temp:        string(order.Temp),
+		Priority:    string(toOrderPriority(string(order.Priority))),
+		ShelfLife:   order.ShelfLife,
+		DecayRate:   order.DecayRate,
+		PrepTime:    order.PrepTime,
+		CreatedAt:   order.CreatedAt,
 	}
 
 	// We set a random uuid for order if there is not one passed in.
@@ -73,6 +116,22 @@ func OrderToRecord(order entity.Order) (*record.Order, error) {
 		record.UUID = guuid.NewV4().String()
 	}
 
+	// We place an order at the default kitchen if there is not one passed in.
+	if nullUUID.UUID == order.KitchenUUID {
+		record.KitchenUUID = entity.DefaultKitchenUUID.String()
+	}
+
+	// Orders not created from the menu have no menu item
+	// and orders that are not line items have no parent order.
+	record.MenuItemUUID = optionalUUIDToRecord(order.MenuItemUUID)
+	record.ParentOrderUUID = optionalUUIDToRecord(order.ParentOrderUUID)
+
+	// Orders wanted as soon as possible have no ready time.
+	if order.IsScheduled() {
+		readyAt := order.ReadyAt
+		record.ReadyAt = &readyAt
+	}
+
 	return &record, nil
 }
 
@@ -83,13 +142,39 @@ func RecordToOrder(record record.Order) (*entity.Order, error) {
 		return nil, errors.Wrapf(err, "uuid is not valid, uuid: %s", record.UUID)
 	}
 
+	kitchenUUID, err := recordToKitchenUUID(record.KitchenUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	menuItemUUID, err := recordToOptionalUUID(record.MenuItemUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "menu item uuid is not valid")
+	}
+
+	parentOrderUUID, err := recordToOptionalUUID(record.ParentOrderUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "parent order uuid is not valid")
+	}
+
+	var readyAt time.Time
+	if record.ReadyAt != nil {
+		readyAt = *record.ReadyAt
+	}
+
 	order := entity.Order{
-		UUID:      orderUUID,
-		Name:      record.Name,
-		Temp:      entity.OrderTemp(record.Temp),
-		ShelfLife: record.ShelfLife,
-		DecayRate: record.DecayRate,
-		CreatedAt: record.CreatedAt,
+		UUID:            orderUUID,
+		KitchenUUID:     kitchenUUID,
+		MenuItemUUID:    menuItemUUID,
+		ParentOrderUUID: parentOrderUUID,
+		Name:            record.Name,
+		Temp:            entity.OrderTemp(record.Temp),
+		Priority:        toOrderPriority(record.Priority),
+		ShelfLife:       record.ShelfLife,
+		DecayRate:       record.DecayRate,
+		PrepTime:        record.PrepTime,
+		ReadyAt:         readyAt,
+		CreatedAt:       record.CreatedAt,
 	}
 
 	err = order.Validate()
@@ -99,3 +184,61 @@ func RecordToOrder(record record.Order) (*entity.Order, error) {
 
 	return &order, nil
 }
+
+// recordToKitchenUUID maps a kitchen uuid column to a kitchen uuid,
+// rows stored before kitchens existed belong to the default kitchen.
+func recordToKitchenUUID(kitchenUUID string) (guuid.UUID, error) {
+	if kitchenUUID == "" {
+		return entity.DefaultKitchenUUID, nil
+	}
+
+	uuid, err := guuid.FromString(kitchenUUID)
+	if err != nil {
+		return guuid.UUID{}, errors.Wrapf(err, "kitchen uuid is not valid, uuid: %s", kitchenUUID)
+	}
+
+	return uuid, nil
+}
+
+// optionalUUIDToRecord maps an optional uuid to a column that is empty when it is not set.
+func optionalUUIDToRecord(uuid guuid.UUID) string {
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == uuid {
+		return ""
+	}
+
+	return uuid.String()
+}
+
+// recordToOptionalUUID maps a column that is empty when a uuid is not set to a uuid.
+func recordToOptionalUUID(uuid string) (guuid.UUID, error) {
+	if uuid == "" {
+		return guuid.NullUUID{}.UUID, nil
+	}
+
+	return guuid.FromString(uuid)
+}
+
+// parsePrepTime parses optional seconds to cook, an empty prep time is 0.
+func parsePrepTime(prepTime string) (int, error) {
+	if prepTime == "" {
+		return 0, nil
+	}
+
+	parsedPrepTime, err := strconv.ParseInt(prepTime, 0, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse int prep time %s", prepTime)
+	}
+
+	return int(parsedPrepTime), nil
+}
+
+// toOrderPriority maps an optional priority to an order priority,
+// orders that do not set a priority and orders placed before priorities are standard.
+func toOrderPriority(priority string) entity.OrderPriority {
+	if priority == "" {
+		return entity.OrderPriorityStandard
+	}
+
+	return entity.OrderPriority(priority)
+}
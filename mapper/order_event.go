@@ -0,0 +1,91 @@
+package mapper
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// OrderEventToRecord maps an order event entity to an order event record.
+func OrderEventToRecord(orderEvent entity.OrderEvent) record.OrderEvent {
+	record := record.OrderEvent{
+		UUID:       orderEvent.UUID.String(),
+		OrderUUID:  orderEvent.OrderUUID.String(),
+		FromStatus: string(orderEvent.FromStatus),
+		ToStatus:   string(orderEvent.ToStatus),
+		Reason:     orderEvent.Reason,
+		CreatedAt:  orderEvent.CreatedAt,
+	}
+
+	// We set a random uuid for order event if there is not one passed in.
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == orderEvent.UUID {
+		record.UUID = guuid.NewV4().String()
+	}
+
+	return record
+}
+
+// RecordsToOrderEvents maps order event records to order event entities.
+func RecordsToOrderEvents(records []*record.OrderEvent) ([]*entity.OrderEvent, error) {
+	var orderEvents []*entity.OrderEvent
+
+	for _, record := range records {
+		orderEvent, err := RecordToOrderEvent(*record)
+		if err != nil {
+			return nil, err
+		}
+
+		orderEvents = append(orderEvents, orderEvent)
+	}
+
+	return orderEvents, nil
+}
+
+// RecordToOrderEvent maps an order event record to an order event entity.
+func RecordToOrderEvent(record record.OrderEvent) (*entity.OrderEvent, error) {
+	uuid, err := guuid.FromString(record.UUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "uuid is not valid, uuid: %s", record.UUID)
+	}
+
+	orderUUID, err := guuid.FromString(record.OrderUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "order uuid is not valid, uuid: %s", record.OrderUUID)
+	}
+
+	toStatus := entity.OrderStatus(record.ToStatus)
+	if _, ok := entity.AllOrderStatuses[toStatus]; !ok {
+		return nil, errors.Errorf("order status %s is invalid", record.ToStatus)
+	}
+
+	orderEvent := entity.OrderEvent{
+		UUID:       uuid,
+		OrderUUID:  orderUUID,
+		FromStatus: entity.OrderStatus(record.FromStatus),
+		ToStatus:   toStatus,
+		Reason:     record.Reason,
+		CreatedAt:  record.CreatedAt,
+	}
+
+	return &orderEvent, nil
+}
+
+// OrderEventsToJSON maps order event entities to a JSON response.
+func OrderEventsToJSON(orderEvents []*entity.OrderEvent) []endpoint.OrderEventJSON {
+	orderEventsJSON := []endpoint.OrderEventJSON{}
+
+	for _, orderEvent := range orderEvents {
+		orderEventsJSON = append(orderEventsJSON, endpoint.OrderEventJSON{
+			FromStatus: string(orderEvent.FromStatus),
+			ToStatus:   string(orderEvent.ToStatus),
+			Reason:     orderEvent.Reason,
+			CreatedAt:  orderEvent.CreatedAt,
+		})
+	}
+
+	return orderEventsJSON
+}
@@ -35,11 +35,13 @@ func TestCreateOrderRequestToOrder(t *testing.T) {
 	}
 
 	expected := &entity.Order{
-		UUID:      orderUUID,
-		Name:      createOrderRequests[0].Name,
-		Temp:      entity.OrderTempHot,
-		ShelfLife: shelfLife,
-		DecayRate: decayRate,
+		UUID:        orderUUID,
+		KitchenUUID: entity.DefaultKitchenUUID, // no kitchen passed in
+		Name:        createOrderRequests[0].Name,
+		Temp:        entity.OrderTempHot,
+		Priority:    entity.OrderPriorityStandard, // no priority passed in
+		ShelfLife:   shelfLife,
+		DecayRate:   decayRate,
 	}
 
 	// Verify we can map with an idempotency uuid.
@@ -55,6 +57,13 @@ func TestCreateOrderRequestToOrder(t *testing.T) {
 	assert.Equal(t, expected.Temp, order2.Temp)
 	assert.Equal(t, expected.ShelfLife, order2.ShelfLife)
 	assert.Equal(t, expected.DecayRate, order2.DecayRate)
+
+	// Verify we map the kitchen an order is placed at.
+	kitchenUUID := guuid.NewV4()
+	createOrderRequests[0].KitchenUUID = kitchenUUID.String()
+	order3, err := CreateOrderRequestToOrder(createOrderRequests[0])
+	assert.Nil(t, err, "no error mapping create order request to order")
+	assert.Equal(t, kitchenUUID, order3.KitchenUUID)
 }
 
 func TestCreateOrderRequestToOrder_InvalidRequests(t *testing.T) {
@@ -83,6 +92,14 @@ func TestCreateOrderRequestToOrder_InvalidRequests(t *testing.T) {
 			ShelfLife: fmt.Sprintf("%d", shelfLife),
 			DecayRate: fmt.Sprintf("%f", decayRate),
 		},
+		{
+			UUID:        orderUUID.String(),
+			KitchenUUID: "invalid kitchen uuid", // invalid kitchen uuid
+			Name:        "Cheeze Pizza",
+			Temp:        string(entity.OrderTempHot),
+			ShelfLife:   fmt.Sprintf("%d", shelfLife),
+			DecayRate:   fmt.Sprintf("%f", decayRate),
+		},
 		{
 			UUID:      orderUUID.String(),
 			Name:      "Cheeze Pizza",
@@ -90,6 +107,14 @@ func TestCreateOrderRequestToOrder_InvalidRequests(t *testing.T) {
 			ShelfLife: fmt.Sprintf("%d", shelfLife),
 			DecayRate: fmt.Sprintf("%f", decayRate),
 		},
+		{
+			UUID:      orderUUID.String(),
+			Name:      "Cheeze Pizza",
+			Temp:      string(entity.OrderTempHot),
+			ShelfLife: fmt.Sprintf("%d", shelfLife),
+			DecayRate: fmt.Sprintf("%f", decayRate),
+			Priority:  "urgent", // invalid order priority
+		},
 	}
 
 	for _, invalidRequest := range invalidRequests {
@@ -122,12 +147,14 @@ func TestOrderToRecord(t *testing.T) {
 
 	// Expected record for order 1 w/ uuid.
 	expected := &record.Order{
-		UUID:      orders[0].UUID.String(),
-		Name:      orders[0].Name,
-		Temp:      string(orders[0].Temp),
-		ShelfLife: orders[0].ShelfLife,
-		DecayRate: orders[0].DecayRate,
-		CreatedAt: orders[0].CreatedAt,
+		UUID:        orders[0].UUID.String(),
+		KitchenUUID: entity.DefaultKitchenUUID.String(), // no kitchen passed in
+		Name:        orders[0].Name,
+		Temp:        string(orders[0].Temp),
+		Priority:    string(entity.OrderPriorityStandard), // no priority passed in
+		ShelfLife:   orders[0].ShelfLife,
+		DecayRate:   orders[0].DecayRate,
+		CreatedAt:   orders[0].CreatedAt,
 	}
 
 	record1, err := OrderToRecord(orders[0])
@@ -157,12 +184,14 @@ func TestRecordToOrder(t *testing.T) {
 	}
 
 	expected := &entity.Order{
-		UUID:      orderUUID,
-		Name:      record.Name,
-		Temp:      entity.OrderTemp(record.Temp),
-		ShelfLife: record.ShelfLife,
-		DecayRate: record.DecayRate,
-		CreatedAt: record.CreatedAt,
+		UUID:        orderUUID,
+		KitchenUUID: entity.DefaultKitchenUUID, // records from before kitchens have no kitchen
+		Name:        record.Name,
+		Temp:        entity.OrderTemp(record.Temp),
+		Priority:    entity.OrderPriorityStandard, // records from before priorities have no priority
+		ShelfLife:   record.ShelfLife,
+		DecayRate:   record.DecayRate,
+		CreatedAt:   record.CreatedAt,
 	}
 
 	order, err := RecordToOrder(record)
@@ -195,3 +224,23 @@ func TestRecordToOrder_InvalidRecords(t *testing.T) {
 		assert.Error(t, err, "error mapping record to order")
 	}
 }
+
+func TestApplyMenuItem(t *testing.T) {
+	menuItem := entity.MenuItem{
+		UUID:      entity.NewMenuItemUUID("Cheeze Pizza"),
+		Name:      "Cheeze Pizza",
+		Temp:      entity.OrderTempHot,
+		ShelfLife: 300,
+		DecayRate: 0.45,
+	}
+
+	// Only the shelf life is overridden.
+	createOrderRequest := ApplyMenuItem(endpoint.CreateOrderRequest{ShelfLife: "200"}, menuItem)
+	order, err := CreateOrderRequestToOrder(createOrderRequest)
+	assert.Nil(t, err, "no error mapping create order request to order")
+	assert.Equal(t, menuItem.UUID, order.MenuItemUUID)
+	assert.Equal(t, menuItem.Name, order.Name)
+	assert.Equal(t, menuItem.Temp, order.Temp)
+	assert.Equal(t, 200, order.ShelfLife)
+	assert.Equal(t, menuItem.DecayRate, order.DecayRate)
+}
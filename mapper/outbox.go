@@ -0,0 +1,91 @@
+package mapper
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// OutboxEventToRecord maps an outbox event entity to an outbox event record.
+func OutboxEventToRecord(outboxEvent entity.OutboxEvent) record.OutboxEvent {
+	return record.OutboxEvent{
+		ID:          outboxEvent.Event.ID,
+		DedupID:     outboxEvent.DedupID.String(),
+		KitchenUUID: outboxEvent.Event.KitchenUUID.String(),
+		OrderUUID:   outboxEvent.Event.OrderUUID.String(),
+		EventType:   string(outboxEvent.Event.Type),
+		ShelfType:   string(outboxEvent.Event.ShelfType),
+		FromStatus:  string(outboxEvent.Event.FromStatus),
+		ToStatus:    string(outboxEvent.Event.ToStatus),
+		Reason:      outboxEvent.Event.Reason,
+		OccurredAt:  outboxEvent.Event.OccurredAt,
+		Attempts:    outboxEvent.Attempts,
+		LastError:   outboxEvent.LastError,
+		PublishedAt: optionalTimeToRecord(outboxEvent.PublishedAt),
+		FailedAt:    optionalTimeToRecord(outboxEvent.FailedAt),
+		CreatedAt:   outboxEvent.CreatedAt,
+	}
+}
+
+// RecordsToOutboxEvents maps outbox event records to outbox event entities.
+func RecordsToOutboxEvents(records []*record.OutboxEvent) ([]*entity.OutboxEvent, error) {
+	var outboxEvents []*entity.OutboxEvent
+
+	for _, record := range records {
+		outboxEvent, err := RecordToOutboxEvent(*record)
+		if err != nil {
+			return nil, err
+		}
+
+		outboxEvents = append(outboxEvents, outboxEvent)
+	}
+
+	return outboxEvents, nil
+}
+
+// RecordToOutboxEvent maps an outbox event record to an outbox event entity.
+func RecordToOutboxEvent(record record.OutboxEvent) (*entity.OutboxEvent, error) {
+	dedupID, err := guuid.FromString(record.DedupID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "dedup id is not valid, uuid: %s", record.DedupID)
+	}
+
+	kitchenUUID, err := guuid.FromString(record.KitchenUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "kitchen uuid is not valid, uuid: %s", record.KitchenUUID)
+	}
+
+	orderUUID, err := guuid.FromString(record.OrderUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "order uuid is not valid, uuid: %s", record.OrderUUID)
+	}
+
+	eventType := entity.EventType(record.EventType)
+	if !entity.AllEventTypes[eventType] {
+		return nil, errors.Errorf("event type %s is invalid", record.EventType)
+	}
+
+	outboxEvent := entity.OutboxEvent{
+		DedupID: dedupID,
+		Event: entity.Event{
+			ID:          record.ID,
+			Type:        eventType,
+			KitchenUUID: kitchenUUID,
+			OrderUUID:   orderUUID,
+			ShelfType:   entity.ShelfType(record.ShelfType),
+			FromStatus:  entity.OrderStatus(record.FromStatus),
+			ToStatus:    entity.OrderStatus(record.ToStatus),
+			Reason:      record.Reason,
+			OccurredAt:  record.OccurredAt,
+		},
+		Attempts:    record.Attempts,
+		LastError:   record.LastError,
+		PublishedAt: recordToOptionalTime(record.PublishedAt),
+		FailedAt:    recordToOptionalTime(record.FailedAt),
+		CreatedAt:   record.CreatedAt,
+	}
+
+	return &outboxEvent, nil
+}
@@ -0,0 +1,133 @@
+package mapper
+
+import (
+	"fmt"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ParentOrderToRecord maps a parent order entity to a parent order record,
+// items are mapped to order records separately.
+func ParentOrderToRecord(parentOrder entity.ParentOrder) record.ParentOrder {
+	record := record.ParentOrder{
+		UUID:        parentOrder.UUID.String(),
+		KitchenUUID: parentOrder.KitchenUUID.String(),
+		CreatedAt:   parentOrder.CreatedAt,
+	}
+
+	// We place a parent order at the default kitchen if there is not one passed in.
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == parentOrder.KitchenUUID {
+		record.KitchenUUID = entity.DefaultKitchenUUID.String()
+	}
+
+	return record
+}
+
+// RecordToParentOrder maps a parent order record and its item records to a parent order entity.
+func RecordToParentOrder(record record.ParentOrder, itemRecords []record.Order) (*entity.ParentOrder, error) {
+	uuid, err := guuid.FromString(record.UUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "uuid is not valid, uuid: %s", record.UUID)
+	}
+
+	kitchenUUID, err := recordToKitchenUUID(record.KitchenUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	parentOrder := entity.ParentOrder{
+		UUID:        uuid,
+		KitchenUUID: kitchenUUID,
+		CreatedAt:   record.CreatedAt,
+	}
+
+	for _, itemRecord := range itemRecords {
+		item, err := RecordToOrder(itemRecord)
+		if err != nil {
+			return nil, err
+		}
+		parentOrder.Items = append(parentOrder.Items, *item)
+	}
+
+	return &parentOrder, nil
+}
+
+// CreateParentOrderRequestToParentOrder maps a HTTP create parent order request to a parent order entity,
+// items created from a menu item must have the menu item applied before mapping.
+func CreateParentOrderRequestToParentOrder(createParentOrderRequest endpoint.CreateParentOrderRequest) (*entity.ParentOrder, error) {
+	// We support idempotency by checking if a parent order UUID is passed,
+	// otherwise we generate a new one.
+	parentOrderUUID := guuid.NewV4()
+	if createParentOrderRequest.UUID != "" {
+		var err error
+		parentOrderUUID, err = guuid.FromString(createParentOrderRequest.UUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "create parent order request uuid is invalid - uuid: %s", createParentOrderRequest.UUID)
+		}
+	}
+
+	parentOrder := entity.ParentOrder{
+		UUID: parentOrderUUID,
+	}
+
+	for i, itemRequest := range createParentOrderRequest.Items {
+		// Items share the kitchen and priority of the parent order.
+		itemRequest.KitchenUUID = createParentOrderRequest.KitchenUUID
+		itemRequest.Priority = createParentOrderRequest.Priority
+
+		// Item uuids are derived from the parent order so retried requests create the same items.
+		if itemRequest.UUID == "" {
+			itemRequest.UUID = guuid.NewV5(parentOrderUUID, fmt.Sprintf("item-%d", i)).String()
+		}
+
+		item, err := CreateOrderRequestToOrder(itemRequest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "item %d is invalid", i)
+		}
+		item.ParentOrderUUID = parentOrderUUID
+
+		parentOrder.KitchenUUID = item.KitchenUUID
+		parentOrder.Items = append(parentOrder.Items, *item)
+	}
+
+	err := parentOrder.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &parentOrder, nil
+}
+
+// ParentOrderToJSON maps a parent order entity to a parent order response.
+func ParentOrderToJSON(parentOrder entity.ParentOrder) endpoint.ParentOrderJSON {
+	parentOrderJSON := endpoint.ParentOrderJSON{
+		UUID:        parentOrder.UUID.String(),
+		KitchenUUID: parentOrder.KitchenUUID.String(),
+		Status:      string(parentOrder.GetStatus()),
+		Items:       make([]endpoint.ParentOrderItemJSON, 0, len(parentOrder.Items)),
+		WastedItems: make([]string, 0),
+		CreatedAt:   parentOrder.CreatedAt,
+	}
+
+	for _, item := range parentOrder.Items {
+		parentOrderJSON.Items = append(parentOrderJSON.Items, endpoint.ParentOrderItemJSON{
+			UUID:   item.UUID.String(),
+			Name:   item.Name,
+			Temp:   string(item.Temp),
+			Status: string(parentOrder.ItemStatuses[item.UUID]),
+		})
+	}
+
+	for _, item := range parentOrder.GetItemsWithStatus(entity.OrderStatusWasted) {
+		parentOrderJSON.WastedItems = append(parentOrderJSON.WastedItems, item.UUID.String())
+	}
+
+	return parentOrderJSON
+}
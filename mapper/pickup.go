@@ -0,0 +1,127 @@
+package mapper
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// PickupOrderRequestToCourier maps a HTTP pickup order request to a courier entity.
+func PickupOrderRequestToCourier(pickupOrderRequest endpoint.PickupOrderRequest) (*entity.Courier, error) {
+	var err error
+
+	// If a courier does not identify themselves we generate a courier uuid.
+	courierUUID := guuid.NewV4()
+	if pickupOrderRequest.CourierUUID != "" {
+		courierUUID, err = guuid.FromString(pickupOrderRequest.CourierUUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "pickup order request courier uuid is invalid - uuid: %s", pickupOrderRequest.CourierUUID)
+		}
+	}
+
+	// Order uuid is optional as not every strategy matches
+	// a courier to a specific order.
+	var orderUUID guuid.UUID
+	if pickupOrderRequest.OrderUUID != "" {
+		orderUUID, err = guuid.FromString(pickupOrderRequest.OrderUUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "pickup order request order uuid is invalid - uuid: %s", pickupOrderRequest.OrderUUID)
+		}
+	}
+
+	// Kitchen uuid is optional as a courier dispatched for
+	// a specific order is sent to that order's kitchen.
+	var kitchenUUID guuid.UUID
+	if pickupOrderRequest.KitchenUUID != "" {
+		kitchenUUID, err = guuid.FromString(pickupOrderRequest.KitchenUUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "pickup order request kitchen uuid is invalid - uuid: %s", pickupOrderRequest.KitchenUUID)
+		}
+	}
+
+	// We default arrival time to now if a courier does not tell us
+	// when they arrived.
+	arrivedAt := time.Now()
+	if pickupOrderRequest.ArrivedAt != "" {
+		arrivedAtMs, err := strconv.ParseInt(pickupOrderRequest.ArrivedAt, 0, 64)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "failed to parse int arrived at %s", pickupOrderRequest.ArrivedAt)
+		}
+		arrivedAt = time.Unix(0, arrivedAtMs*int64(time.Millisecond))
+	}
+
+	courier := entity.Courier{
+		UUID:        courierUUID,
+		KitchenUUID: kitchenUUID,
+		OrderUUID:   orderUUID,
+		ArrivedAt:   arrivedAt,
+	}
+
+	return &courier, nil
+}
+
+// PickupToRecord maps a pickup entity to a pickup record.
+func PickupToRecord(pickup entity.Pickup) record.Pickup {
+	record := record.Pickup{
+		UUID:           pickup.UUID.String(),
+		KitchenUUID:    pickup.KitchenUUID.String(),
+		CourierUUID:    pickup.CourierUUID.String(),
+		OrderUUID:      pickup.OrderUUID.String(),
+		ShelfOrderUUID: pickup.ShelfOrderUUID.String(),
+		Strategy:       string(pickup.Strategy),
+		FoodWaitMs:     int64(pickup.FoodWaitTime / time.Millisecond),
+		CourierWaitMs:  int64(pickup.CourierWaitTime / time.Millisecond),
+		PickedUpAt:     pickup.PickedUpAt,
+	}
+
+	// We set a random uuid for pickup if there is not one passed in.
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == pickup.UUID {
+		record.UUID = guuid.NewV4().String()
+	}
+
+	return record
+}
+
+// RecordsToPickupStats maps aggregated pickup records to pickup stats entities.
+func RecordsToPickupStats(records []*record.PickupStats) []*entity.PickupStats {
+	var pickupStats []*entity.PickupStats
+
+	for _, record := range records {
+		stats := entity.PickupStats{
+			Strategy:           entity.PickupStrategy(record.Strategy),
+			NumOfPickups:       record.NumOfPickups,
+			AvgFoodWaitTime:    time.Duration(record.AvgFoodWaitMs) * time.Millisecond,
+			AvgCourierWaitTime: time.Duration(record.AvgCourierWaitMs) * time.Millisecond,
+		}
+		pickupStats = append(pickupStats, &stats)
+	}
+
+	return pickupStats
+}
+
+// PickupStatsToJSON maps pickup stats entities to a JSON response.
+func PickupStatsToJSON(pickupStats []*entity.PickupStats) []endpoint.PickupStatsJSON {
+	statsJSON := []endpoint.PickupStatsJSON{}
+
+	for _, stats := range pickupStats {
+		statsJSON = append(statsJSON, endpoint.PickupStatsJSON{
+			Strategy:         string(stats.Strategy),
+			NumOfPickups:     stats.NumOfPickups,
+			AvgFoodWaitMs:    int64(stats.AvgFoodWaitTime / time.Millisecond),
+			AvgCourierWaitMs: int64(stats.AvgCourierWaitTime / time.Millisecond),
+		})
+	}
+
+	return statsJSON
+}
@@ -0,0 +1,133 @@
+package mapper
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/rpc/kitchenpb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateOrderProtoToRequest maps a gRPC create order request to a create order request
+// so gRPC orders go through the same validation as HTTP orders.
+func CreateOrderProtoToRequest(createOrderRequest *kitchenpb.CreateOrderRequest) endpoint.CreateOrderRequest {
+	request := endpoint.CreateOrderRequest{
+		UUID:         createOrderRequest.GetUuid(),
+		KitchenUUID:  createOrderRequest.GetKitchenUuid(),
+		MenuItemUUID: createOrderRequest.GetMenuItemUuid(),
+		Name:         createOrderRequest.GetName(),
+		Temp:         createOrderRequest.GetTemp(),
+		Priority:     createOrderRequest.GetPriority(),
+	}
+
+	// Unset numbers are left empty so they are taken from the menu item, or rejected without one.
+	if createOrderRequest.GetShelfLife() != 0 {
+		request.ShelfLife = strconv.FormatInt(int64(createOrderRequest.GetShelfLife()), 10)
+	}
+	if createOrderRequest.GetDecayRate() != 0 {
+		request.DecayRate = strconv.FormatFloat(createOrderRequest.GetDecayRate(), 'f', -1, 64)
+	}
+	if createOrderRequest.GetPrepTime() != 0 {
+		request.PrepTime = strconv.FormatInt(int64(createOrderRequest.GetPrepTime()), 10)
+	}
+	if createOrderRequest.GetReadyAt() != nil {
+		request.ReadyAt = createOrderRequest.GetReadyAt().AsTime().Format(time.RFC3339)
+	}
+
+	return request
+}
+
+// OrderToProto maps an order entity to a gRPC order.
+func OrderToProto(order entity.Order) *kitchenpb.Order {
+	priority := order.Priority
+	if priority == "" {
+		priority = entity.OrderPriorityStandard
+	}
+
+	return &kitchenpb.Order{
+		Uuid:            order.UUID.String(),
+		KitchenUuid:     order.KitchenUUID.String(),
+		MenuItemUuid:    optionalUUIDToRecord(order.MenuItemUUID),
+		ParentOrderUuid: optionalUUIDToRecord(order.ParentOrderUUID),
+		Name:            order.Name,
+		Temp:            string(order.Temp),
+		Priority:        string(priority),
+		ShelfLife:       int32(order.ShelfLife),
+		DecayRate:       order.DecayRate,
+		PrepTime:        int32(order.PrepTime),
+		ReadyAt:         optionalTimeToProto(order.ReadyAt),
+		CreatedAt:       optionalTimeToProto(order.CreatedAt),
+	}
+}
+
+// EventToProto maps an event entity to a gRPC order event.
+func EventToProto(event entity.Event) *kitchenpb.OrderEvent {
+	return &kitchenpb.OrderEvent{
+		Id:          event.ID,
+		Type:        string(event.Type),
+		KitchenUuid: event.KitchenUUID.String(),
+		OrderUuid:   event.OrderUUID.String(),
+		ShelfType:   string(event.ShelfType),
+		FromStatus:  string(event.FromStatus),
+		ToStatus:    string(event.ToStatus),
+		Reason:      event.Reason,
+		OccurredAt:  optionalTimeToProto(event.OccurredAt),
+	}
+}
+
+// ShelfSnapshotsToProto maps shelf snapshots to gRPC shelves.
+func ShelfSnapshotsToProto(shelfSnapshots []*entity.ShelfSnapshot) []*kitchenpb.Shelf {
+	shelves := make([]*kitchenpb.Shelf, 0, len(shelfSnapshots))
+	for _, shelfSnapshot := range shelfSnapshots {
+		shelves = append(shelves, ShelfSnapshotToProto(*shelfSnapshot))
+	}
+
+	return shelves
+}
+
+// ShelfSnapshotToProto maps a shelf snapshot to a gRPC shelf.
+func ShelfSnapshotToProto(shelfSnapshot entity.ShelfSnapshot) *kitchenpb.Shelf {
+	temps := make([]string, 0, len(shelfSnapshot.Shelf.Temps))
+	for _, temp := range shelfSnapshot.Shelf.Temps {
+		temps = append(temps, string(temp))
+	}
+
+	orders := make([]*kitchenpb.ShelfOrder, 0, len(shelfSnapshot.Orders))
+	for _, snapshotOrder := range shelfSnapshot.Orders {
+		orders = append(orders, &kitchenpb.ShelfOrder{
+			OrderUuid:       snapshotOrder.Order.UUID.String(),
+			Name:            snapshotOrder.Order.Name,
+			Temp:            string(snapshotOrder.Order.Temp),
+			Priority:        string(snapshotOrder.ShelfOrder.Priority),
+			Status:          string(snapshotOrder.ShelfOrder.OrderStatus),
+			PlacedAt:        optionalTimeToProto(snapshotOrder.ShelfOrder.CreatedAt),
+			ExpiresAt:       optionalTimeToProto(snapshotOrder.ShelfOrder.ExpiresAt),
+			Value:           snapshotOrder.Value,
+			NormalizedValue: snapshotOrder.NormalizedValue,
+		})
+	}
+
+	return &kitchenpb.Shelf{
+		Type:      string(shelfSnapshot.Shelf.Type),
+		Temps:     temps,
+		Overflow:  shelfSnapshot.Shelf.Overflow,
+		Capacity:  int32(shelfSnapshot.Shelf.Capacity),
+		Reserved:  int32(shelfSnapshot.Shelf.Reserved),
+		Occupancy: int32(shelfSnapshot.Occupancy),
+		Available: int32(shelfSnapshot.GetAvailable()),
+		Orders:    orders,
+		TakenAt:   optionalTimeToProto(shelfSnapshot.TakenAt),
+	}
+}
+
+// optionalTimeToProto leaves zero times unset.
+func optionalTimeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,95 @@
+package mapper
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ScheduledOrderToRecord maps a scheduled order entity to a scheduled order record.
+func ScheduledOrderToRecord(scheduledOrder entity.ScheduledOrder) record.ScheduledOrder {
+	record := record.ScheduledOrder{
+		OrderUUID:   scheduledOrder.OrderUUID.String(),
+		KitchenUUID: scheduledOrder.KitchenUUID.String(),
+		OrderStatus: string(scheduledOrder.OrderStatus),
+		ReadyAt:     scheduledOrder.ReadyAt,
+		ReleaseAt:   scheduledOrder.ReleaseAt,
+		Version:     scheduledOrder.Version,
+		CreatedAt:   scheduledOrder.CreatedAt,
+		UpdatedAt:   scheduledOrder.UpdatedAt,
+	}
+
+	// We schedule an order at the default kitchen if there is not one passed in.
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == scheduledOrder.KitchenUUID {
+		record.KitchenUUID = entity.DefaultKitchenUUID.String()
+	}
+
+	return record
+}
+
+// RecordsToScheduledOrders maps scheduled order records to scheduled order entities.
+func RecordsToScheduledOrders(records []*record.ScheduledOrder) ([]*entity.ScheduledOrder, error) {
+	var scheduledOrders []*entity.ScheduledOrder
+
+	for _, record := range records {
+		scheduledOrder, err := RecordToScheduledOrder(*record)
+		if err != nil {
+			return nil, err
+		}
+
+		scheduledOrders = append(scheduledOrders, scheduledOrder)
+	}
+
+	return scheduledOrders, nil
+}
+
+// RecordToScheduledOrder maps a scheduled order record to a scheduled order entity.
+func RecordToScheduledOrder(record record.ScheduledOrder) (*entity.ScheduledOrder, error) {
+	orderUUID, err := guuid.FromString(record.OrderUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "order uuid is not valid, uuid: %s", record.OrderUUID)
+	}
+
+	kitchenUUID, err := recordToKitchenUUID(record.KitchenUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderStatus := entity.OrderStatus(record.OrderStatus)
+	if _, ok := entity.AllOrderStatuses[orderStatus]; !ok {
+		return nil, errors.Errorf("order status %s is invalid", record.OrderStatus)
+	}
+
+	scheduledOrder := entity.ScheduledOrder{
+		OrderUUID:   orderUUID,
+		KitchenUUID: kitchenUUID,
+		OrderStatus: orderStatus,
+		ReadyAt:     record.ReadyAt,
+		ReleaseAt:   record.ReleaseAt,
+		Version:     record.Version,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+	}
+
+	return &scheduledOrder, nil
+}
+
+// ScheduledOrdersToJSON maps scheduled order entities to a scheduled order response.
+func ScheduledOrdersToJSON(scheduledOrders []*entity.ScheduledOrder) []endpoint.ScheduledOrderJSON {
+	scheduledOrdersJSON := make([]endpoint.ScheduledOrderJSON, 0, len(scheduledOrders))
+	for _, scheduledOrder := range scheduledOrders {
+		scheduledOrdersJSON = append(scheduledOrdersJSON, endpoint.ScheduledOrderJSON{
+			OrderUUID:   scheduledOrder.OrderUUID.String(),
+			KitchenUUID: scheduledOrder.KitchenUUID.String(),
+			Status:      string(scheduledOrder.OrderStatus),
+			ReadyAt:     scheduledOrder.ReadyAt,
+			ReleaseAt:   scheduledOrder.ReleaseAt,
+		})
+	}
+
+	return scheduledOrdersJSON
+}
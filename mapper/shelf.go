@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+)
+
+// ShelfSnapshotsToJSON maps shelf snapshots to a shelves response.
+func ShelfSnapshotsToJSON(shelfSnapshots []*entity.ShelfSnapshot) []endpoint.ShelfJSON {
+	shelvesJSON := make([]endpoint.ShelfJSON, 0, len(shelfSnapshots))
+	for _, shelfSnapshot := range shelfSnapshots {
+		shelvesJSON = append(shelvesJSON, ShelfSnapshotToJSON(*shelfSnapshot))
+	}
+
+	return shelvesJSON
+}
+
+// ShelfSnapshotToJSON maps a shelf snapshot to a shelf response.
+func ShelfSnapshotToJSON(shelfSnapshot entity.ShelfSnapshot) endpoint.ShelfJSON {
+	temps := make([]string, 0, len(shelfSnapshot.Shelf.Temps))
+	for _, temp := range shelfSnapshot.Shelf.Temps {
+		temps = append(temps, string(temp))
+	}
+
+	ordersJSON := make([]endpoint.ShelfOrderJSON, 0, len(shelfSnapshot.Orders))
+	for _, snapshotOrder := range shelfSnapshot.Orders {
+		ordersJSON = append(ordersJSON, endpoint.ShelfOrderJSON{
+			OrderUUID:       snapshotOrder.Order.UUID.String(),
+			Name:            snapshotOrder.Order.Name,
+			Temp:            string(snapshotOrder.Order.Temp),
+			Priority:        string(snapshotOrder.ShelfOrder.Priority),
+			Status:          string(snapshotOrder.ShelfOrder.OrderStatus),
+			PlacedAt:        snapshotOrder.ShelfOrder.CreatedAt,
+			ExpiresAt:       snapshotOrder.ShelfOrder.ExpiresAt,
+			Value:           snapshotOrder.Value,
+			NormalizedValue: snapshotOrder.NormalizedValue,
+		})
+	}
+
+	return endpoint.ShelfJSON{
+		Type:      string(shelfSnapshot.Shelf.Type),
+		Temps:     temps,
+		Overflow:  shelfSnapshot.Shelf.Overflow,
+		Capacity:  shelfSnapshot.Shelf.Capacity,
+		Reserved:  shelfSnapshot.Shelf.Reserved,
+		Occupancy: shelfSnapshot.Occupancy,
+		Available: shelfSnapshot.GetAvailable(),
+		Orders:    ordersJSON,
+		TakenAt:   shelfSnapshot.TakenAt,
+	}
+}
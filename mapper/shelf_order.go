@@ -11,14 +11,17 @@ import (
 // ShelfOrderToRecord maps an order entity to an order record.
 func ShelfOrderToRecord(shelfOrder entity.ShelfOrder) record.ShelfOrder {
 	record := record.ShelfOrder{
-		UUID:        shelfOrder.UUID.String(),
-		OrderUUID:   shelfOrder.OrderUUID.String(),
-		ShelfType:   string(shelfOrder.ShelfType),
-		OrderStatus: string(shelfOrder.OrderStatus),
-		Version:     shelfOrder.Version,
-		ExpiresAt:   shelfOrder.ExpiresAt,
-		CreatedAt:   shelfOrder.CreatedAt,
-		UpdatedAt:   shelfOrder.UpdatedAt,
+		UUID:            shelfOrder.UUID.String(),
+		OrderUUID:       shelfOrder.OrderUUID.String(),
+		KitchenUUID:     shelfOrder.KitchenUUID.String(),
+		ParentOrderUUID: optionalUUIDToRecord(shelfOrder.ParentOrderUUID),
+		Priority:        string(toOrderPriority(string(shelfOrder.Priority))),
+		ShelfType:       string(shelfOrder.ShelfType),
+		OrderStatus:     string(shelfOrder.OrderStatus),
+		Version:         shelfOrder.Version,
+		ExpiresAt:       shelfOrder.ExpiresAt,
+		CreatedAt:       shelfOrder.CreatedAt,
+		UpdatedAt:       shelfOrder.UpdatedAt,
 	}
 
 	// We set a random uuid for shelf order if there is not one passed in.
@@ -27,6 +30,11 @@ func ShelfOrderToRecord(shelfOrder entity.ShelfOrder) record.ShelfOrder {
 		record.UUID = guuid.NewV4().String()
 	}
 
+	// We place a shelf order at the default kitchen if there is not one passed in.
+	if nullUUID.UUID == shelfOrder.KitchenUUID {
+		record.KitchenUUID = entity.DefaultKitchenUUID.String()
+	}
+
 	return record
 }
 
@@ -58,15 +66,28 @@ func RecordToShelfOrder(record record.ShelfOrder) (*entity.ShelfOrder, error) {
 		return nil, errors.Wrapf(err, "order uuid is not valid, uuid: %s", record.OrderUUID)
 	}
 
+	kitchenUUID, err := recordToKitchenUUID(record.KitchenUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	parentOrderUUID, err := recordToOptionalUUID(record.ParentOrderUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "parent order uuid is not valid")
+	}
+
 	shelfOrder := entity.ShelfOrder{
-		UUID:        uuid,
-		OrderUUID:   orderUUID,
-		ShelfType:   entity.ShelfType(record.ShelfType),
-		OrderStatus: entity.OrderStatus(record.OrderStatus),
-		Version:     record.Version,
-		ExpiresAt:   record.ExpiresAt,
-		CreatedAt:   record.CreatedAt,
-		UpdatedAt:   record.UpdatedAt,
+		UUID:            uuid,
+		OrderUUID:       orderUUID,
+		KitchenUUID:     kitchenUUID,
+		ParentOrderUUID: parentOrderUUID,
+		Priority:        toOrderPriority(record.Priority),
+		ShelfType:       entity.ShelfType(record.ShelfType),
+		OrderStatus:     entity.OrderStatus(record.OrderStatus),
+		Version:         record.Version,
+		ExpiresAt:       record.ExpiresAt,
+		CreatedAt:       record.CreatedAt,
+		UpdatedAt:       record.UpdatedAt,
 	}
 
 	err = shelfOrder.Validate()
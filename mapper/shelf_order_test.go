@@ -0,0 +1,33 @@
+package mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordToShelfOrder_RemovedShelfType(t *testing.T) {
+	record := record.ShelfOrder{
+		UUID:        guuid.NewV4().String(),
+		OrderUUID:   guuid.NewV4().String(),
+		Priority:    string(entity.OrderPriorityStandard),
+		ShelfType:   "warm-holding", // a shelf since removed from the shelf catalog
+		OrderStatus: string(entity.OrderStatusPickedUp),
+		ExpiresAt:   time.Now(),
+	}
+
+	// Orders stored on a removed shelf are still read.
+	shelfOrder, err := RecordToShelfOrder(record)
+	assert.Nil(t, err, "no error mapping record to shelf order")
+	assert.Equal(t, entity.ShelfType("warm-holding"), shelfOrder.ShelfType)
+	assert.Error(t, shelfOrder.ValidateShelfType())
+
+	record.ShelfType = ""
+	_, err = RecordToShelfOrder(record)
+	assert.Error(t, err, "error mapping record without a shelf type")
+}
@@ -0,0 +1,281 @@
+package mapper
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// WebhookSubscriptionRequestToWebhookSubscription maps a HTTP webhook subscription request
+// to a webhook subscription entity.
+func WebhookSubscriptionRequestToWebhookSubscription(
+	webhookSubscriptionRequest endpoint.WebhookSubscriptionRequest) (*entity.WebhookSubscription, error) {
+	var err error
+
+	// We support idempotency by checking if a subscription UUID is passed,
+	// otherwise we generate a new one.
+	subscriptionUUID := guuid.NewV4()
+	if webhookSubscriptionRequest.UUID != "" {
+		subscriptionUUID, err = guuid.FromString(webhookSubscriptionRequest.UUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "webhook subscription request uuid is invalid - uuid: %s", webhookSubscriptionRequest.UUID)
+		}
+	}
+
+	var kitchenUUID guuid.UUID
+	if webhookSubscriptionRequest.KitchenUUID != "" {
+		kitchenUUID, err = guuid.FromString(webhookSubscriptionRequest.KitchenUUID)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "webhook subscription request kitchen uuid is invalid - uuid: %s", webhookSubscriptionRequest.KitchenUUID)
+		}
+	}
+
+	webhookSubscription := entity.WebhookSubscription{
+		UUID:        subscriptionUUID,
+		URL:         webhookSubscriptionRequest.URL,
+		Secret:      webhookSubscriptionRequest.Secret,
+		KitchenUUID: kitchenUUID,
+		EventTypes:  toEventTypes(webhookSubscriptionRequest.EventTypes),
+	}
+
+	err = webhookSubscription.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookSubscription, nil
+}
+
+// WebhookSubscriptionToRecord maps a webhook subscription entity to a webhook subscription record.
+func WebhookSubscriptionToRecord(webhookSubscription entity.WebhookSubscription) record.WebhookSubscription {
+	eventTypes := make([]string, 0, len(webhookSubscription.EventTypes))
+	for _, eventType := range webhookSubscription.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	return record.WebhookSubscription{
+		UUID:        webhookSubscription.UUID.String(),
+		URL:         webhookSubscription.URL,
+		Secret:      webhookSubscription.Secret,
+		KitchenUUID: optionalUUIDToRecord(webhookSubscription.KitchenUUID),
+		EventTypes:  strings.Join(eventTypes, ","),
+		CreatedAt:   webhookSubscription.CreatedAt,
+		UpdatedAt:   webhookSubscription.UpdatedAt,
+	}
+}
+
+// RecordToWebhookSubscription maps a webhook subscription record to a webhook subscription entity.
+func RecordToWebhookSubscription(record record.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	subscriptionUUID, err := guuid.FromString(record.UUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "webhook subscription uuid is not valid, uuid: %s", record.UUID)
+	}
+
+	kitchenUUID, err := recordToOptionalUUID(record.KitchenUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "kitchen uuid is not valid, uuid: %s", record.KitchenUUID)
+	}
+
+	eventTypes := toEventTypes(record.EventTypes)
+	for _, eventType := range eventTypes {
+		if !entity.AllEventTypes[eventType] {
+			return nil, errors.Errorf("event type %s is invalid", eventType)
+		}
+	}
+
+	webhookSubscription := entity.WebhookSubscription{
+		UUID:        subscriptionUUID,
+		URL:         record.URL,
+		Secret:      record.Secret,
+		KitchenUUID: kitchenUUID,
+		EventTypes:  eventTypes,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+	}
+
+	return &webhookSubscription, nil
+}
+
+// WebhookSubscriptionsToJSON maps webhook subscription entities to a webhook subscriptions response.
+func WebhookSubscriptionsToJSON(webhookSubscriptions []*entity.WebhookSubscription) []endpoint.WebhookSubscriptionJSON {
+	webhookSubscriptionsJSON := make([]endpoint.WebhookSubscriptionJSON, 0, len(webhookSubscriptions))
+	for _, webhookSubscription := range webhookSubscriptions {
+		webhookSubscriptionsJSON = append(webhookSubscriptionsJSON, WebhookSubscriptionToJSON(*webhookSubscription))
+	}
+
+	return webhookSubscriptionsJSON
+}
+
+// WebhookSubscriptionToJSON maps a webhook subscription entity to a webhook subscription response.
+func WebhookSubscriptionToJSON(webhookSubscription entity.WebhookSubscription) endpoint.WebhookSubscriptionJSON {
+	eventTypes := make([]string, 0, len(webhookSubscription.EventTypes))
+	for _, eventType := range webhookSubscription.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	return endpoint.WebhookSubscriptionJSON{
+		UUID:        webhookSubscription.UUID.String(),
+		URL:         webhookSubscription.URL,
+		KitchenUUID: optionalUUIDToRecord(webhookSubscription.KitchenUUID),
+		EventTypes:  eventTypes,
+		CreatedAt:   webhookSubscription.CreatedAt,
+		UpdatedAt:   webhookSubscription.UpdatedAt,
+	}
+}
+
+// WebhookDeliveryToRecord maps a webhook delivery entity to a webhook delivery record.
+func WebhookDeliveryToRecord(webhookDelivery entity.WebhookDelivery) record.WebhookDelivery {
+	return record.WebhookDelivery{
+		UUID:             webhookDelivery.UUID.String(),
+		SubscriptionUUID: webhookDelivery.SubscriptionUUID.String(),
+		EventType:        string(webhookDelivery.EventType),
+		OrderUUID:        webhookDelivery.OrderUUID.String(),
+		Payload:          webhookDelivery.Payload,
+		Status:           string(webhookDelivery.Status),
+		Attempts:         webhookDelivery.Attempts,
+		NextAttemptAt:    webhookDelivery.NextAttemptAt,
+		LastStatusCode:   webhookDelivery.LastStatusCode,
+		LastError:        webhookDelivery.LastError,
+		LastAttemptAt:    optionalTimeToRecord(webhookDelivery.LastAttemptAt),
+		DeliveredAt:      optionalTimeToRecord(webhookDelivery.DeliveredAt),
+		Version:          webhookDelivery.Version,
+		CreatedAt:        webhookDelivery.CreatedAt,
+		UpdatedAt:        webhookDelivery.UpdatedAt,
+	}
+}
+
+// RecordsToWebhookDeliveries maps webhook delivery records to webhook delivery entities.
+func RecordsToWebhookDeliveries(records []*record.WebhookDelivery) ([]*entity.WebhookDelivery, error) {
+	var webhookDeliveries []*entity.WebhookDelivery
+
+	for _, record := range records {
+		webhookDelivery, err := RecordToWebhookDelivery(*record)
+		if err != nil {
+			return nil, err
+		}
+
+		webhookDeliveries = append(webhookDeliveries, webhookDelivery)
+	}
+
+	return webhookDeliveries, nil
+}
+
+// RecordToWebhookDelivery maps a webhook delivery record to a webhook delivery entity.
+func RecordToWebhookDelivery(record record.WebhookDelivery) (*entity.WebhookDelivery, error) {
+	deliveryUUID, err := guuid.FromString(record.UUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "webhook delivery uuid is not valid, uuid: %s", record.UUID)
+	}
+
+	subscriptionUUID, err := guuid.FromString(record.SubscriptionUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "webhook subscription uuid is not valid, uuid: %s", record.SubscriptionUUID)
+	}
+
+	orderUUID, err := guuid.FromString(record.OrderUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "order uuid is not valid, uuid: %s", record.OrderUUID)
+	}
+
+	eventType := entity.EventType(record.EventType)
+	if !entity.AllEventTypes[eventType] {
+		return nil, errors.Errorf("event type %s is invalid", record.EventType)
+	}
+
+	status := entity.WebhookDeliveryStatus(record.Status)
+	if !entity.AllWebhookDeliveryStatuses[status] {
+		return nil, errors.Errorf("webhook delivery status %s is invalid", record.Status)
+	}
+
+	webhookDelivery := entity.WebhookDelivery{
+		UUID:             deliveryUUID,
+		SubscriptionUUID: subscriptionUUID,
+		EventType:        eventType,
+		OrderUUID:        orderUUID,
+		Payload:          record.Payload,
+		Status:           status,
+		Attempts:         record.Attempts,
+		NextAttemptAt:    record.NextAttemptAt,
+		LastStatusCode:   record.LastStatusCode,
+		LastError:        record.LastError,
+		LastAttemptAt:    recordToOptionalTime(record.LastAttemptAt),
+		DeliveredAt:      recordToOptionalTime(record.DeliveredAt),
+		Version:          record.Version,
+		CreatedAt:        record.CreatedAt,
+		UpdatedAt:        record.UpdatedAt,
+	}
+
+	return &webhookDelivery, nil
+}
+
+// WebhookDeliveriesToJSON maps webhook delivery entities to a delivery log response.
+func WebhookDeliveriesToJSON(webhookDeliveries []*entity.WebhookDelivery) []endpoint.WebhookDeliveryJSON {
+	webhookDeliveriesJSON := make([]endpoint.WebhookDeliveryJSON, 0, len(webhookDeliveries))
+	for _, webhookDelivery := range webhookDeliveries {
+		webhookDeliveriesJSON = append(webhookDeliveriesJSON, WebhookDeliveryToJSON(*webhookDelivery))
+	}
+
+	return webhookDeliveriesJSON
+}
+
+// WebhookDeliveryToJSON maps a webhook delivery entity to a delivery log response.
+func WebhookDeliveryToJSON(webhookDelivery entity.WebhookDelivery) endpoint.WebhookDeliveryJSON {
+	webhookDeliveryJSON := endpoint.WebhookDeliveryJSON{
+		UUID:             webhookDelivery.UUID.String(),
+		SubscriptionUUID: webhookDelivery.SubscriptionUUID.String(),
+		EventType:        string(webhookDelivery.EventType),
+		OrderUUID:        webhookDelivery.OrderUUID.String(),
+		Payload:          webhookDelivery.Payload,
+		Status:           string(webhookDelivery.Status),
+		Attempts:         webhookDelivery.Attempts,
+		LastStatusCode:   webhookDelivery.LastStatusCode,
+		LastError:        webhookDelivery.LastError,
+		LastAttemptAt:    optionalTimeToRecord(webhookDelivery.LastAttemptAt),
+		DeliveredAt:      optionalTimeToRecord(webhookDelivery.DeliveredAt),
+		CreatedAt:        webhookDelivery.CreatedAt,
+	}
+
+	if webhookDelivery.Status == entity.WebhookDeliveryStatusPending {
+		webhookDeliveryJSON.NextAttemptAt = &webhookDelivery.NextAttemptAt
+	}
+
+	return webhookDeliveryJSON
+}
+
+// toEventTypes splits comma separated event types.
+func toEventTypes(eventTypesStr string) []entity.EventType {
+	var eventTypes []entity.EventType
+	for _, eventType := range strings.Split(eventTypesStr, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes = append(eventTypes, entity.EventType(eventType))
+		}
+	}
+
+	return eventTypes
+}
+
+// optionalTimeToRecord maps an optional time to a column that is NULL when it is not set.
+func optionalTimeToRecord(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+// recordToOptionalTime maps a column that is NULL when a time is not set to a time.
+func recordToOptionalTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
@@ -1,9 +1,35 @@
+CREATE TABLE `menu_items` (
+  `uuid`                            char(36)           NOT NULL,
+  `name`                            varchar(191)       NOT NULL,
+  `temp`                            varchar(191)       NOT NULL,
+  `shelf_life`                      INTEGER            NOT NULL,
+  `decay_rate`                      FLOAT              NOT NULL,
+  `prep_time`                       INTEGER            NOT NULL DEFAULT 0,
+  `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at`                      DATETIME           DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`uuid`),
+  UNIQUE KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `parent_orders` (
+  `uuid`                            char(36)           NOT NULL,
+  `kitchen_uuid`                    char(36)           NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001',
+  `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 CREATE TABLE `orders` (
   `uuid`                            char(36)           NOT NULL,
+  `kitchen_uuid`                    char(36)           NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001',
+  `menu_item_uuid`                  char(36)           NOT NULL DEFAULT '',
+  `parent_order_uuid`               char(36)           NOT NULL DEFAULT '',
   `name`                            varchar(255)       NOT NULL,
   `temp`                            varchar(191)       NOT NULL,
+  `priority`                        varchar(191)       NOT NULL DEFAULT 'standard',
   `shelf_life`                      INTEGER            NOT NULL,
   `decay_rate`                      FLOAT              NOT NULL,
+  `prep_time`                       INTEGER            NOT NULL DEFAULT 0,
+  `ready_at`                        DATETIME           NULL,
   `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
   PRIMARY KEY (`uuid`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -11,10 +37,16 @@ CREATE TABLE `orders` (
 ALTER TABLE `orders` ADD INDEX (`temp`);
 ALTER TABLE `orders` ADD INDEX (`shelf_life`, `decay_rate`);
 ALTER TABLE `orders` ADD INDEX (`created_at`);
+ALTER TABLE `orders` ADD INDEX (`kitchen_uuid`);
+ALTER TABLE `orders` ADD INDEX (`menu_item_uuid`);
+ALTER TABLE `orders` ADD INDEX (`parent_order_uuid`);
 
 CREATE TABLE `shelf_orders` (
   `uuid`                            char(36)           NOT NULL,
   `order_uuid`                      char(36)           NOT NULL,
+  `kitchen_uuid`                    char(36)           NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001',
+  `parent_order_uuid`               char(36)           NOT NULL DEFAULT '',
+  `priority`                        varchar(191)       NOT NULL DEFAULT 'standard',
   `shelf_type`                      varchar(191)       NOT NULL,
   `order_status`                    varchar(191)       NOT NULL,
   `version`                         INTEGER            NOT NULL,
@@ -22,9 +54,134 @@ CREATE TABLE `shelf_orders` (
   `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at`                      DATETIME           DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`uuid`),
+  UNIQUE KEY (`order_uuid`),
+  FOREIGN KEY (`order_uuid`) REFERENCES orders(`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `shelf_orders` ADD INDEX (`kitchen_uuid`, `shelf_type`, `order_status`);
+ALTER TABLE `shelf_orders` ADD INDEX (`kitchen_uuid`, `order_status`, `parent_order_uuid`);
+ALTER TABLE `shelf_orders` ADD INDEX (`expires_at`);
+ALTER TABLE `shelf_orders` ADD INDEX (`kitchen_uuid`, `order_status`, `priority`, `expires_at`);
+
+CREATE TABLE `scheduled_orders` (
+  `order_uuid`                      char(36)           NOT NULL,
+  `kitchen_uuid`                    char(36)           NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001',
+  `order_status`                    varchar(191)       NOT NULL,
+  `ready_at`                        DATETIME           NOT NULL,
+  `release_at`                      DATETIME           NOT NULL,
+  `version`                         INTEGER            NOT NULL,
+  `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at`                      DATETIME           DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`order_uuid`),
+  FOREIGN KEY (`order_uuid`) REFERENCES orders(`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `scheduled_orders` ADD INDEX (`order_status`, `release_at`);
+ALTER TABLE `scheduled_orders` ADD INDEX (`kitchen_uuid`, `order_status`, `ready_at`);
+
+CREATE TABLE `pickups` (
+  `uuid`                            char(36)           NOT NULL,
+  `kitchen_uuid`                    char(36)           NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001',
+  `courier_uuid`                    char(36)           NOT NULL,
+  `order_uuid`                      char(36)           NOT NULL,
+  `shelf_order_uuid`                char(36)           NOT NULL,
+  `strategy`                        varchar(191)       NOT NULL,
+  `food_wait_ms`                    BIGINT             NOT NULL,
+  `courier_wait_ms`                 BIGINT             NOT NULL,
+  `picked_up_at`                    DATETIME           NOT NULL,
+  `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`uuid`),
+  FOREIGN KEY (`order_uuid`) REFERENCES orders(`uuid`),
+  FOREIGN KEY (`shelf_order_uuid`) REFERENCES shelf_orders(`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `pickups` ADD INDEX (`strategy`);
+ALTER TABLE `pickups` ADD INDEX (`order_uuid`);
+ALTER TABLE `pickups` ADD INDEX (`kitchen_uuid`);
+
+CREATE TABLE `order_events` (
+  `uuid`                            char(36)           NOT NULL,
+  `order_uuid`                      char(36)           NOT NULL,
+  `from_status`                     varchar(191)       NOT NULL DEFAULT '',
+  `to_status`                       varchar(191)       NOT NULL,
+  `reason`                          varchar(255)       NOT NULL DEFAULT '',
+  `created_at`                      DATETIME(6)        NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (`uuid`),
+  FOREIGN KEY (`order_uuid`) REFERENCES orders(`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `order_events` ADD INDEX (`order_uuid`, `created_at`);
+
+CREATE TABLE `deliveries` (
+  `uuid`                            char(36)           NOT NULL,
+  `order_uuid`                      char(36)           NOT NULL,
+  `shelf_order_uuid`                char(36)           NOT NULL,
+  `order_age_ms`                    BIGINT             NOT NULL,
+  `value`                           FLOAT              NOT NULL,
+  `normalized_value`                FLOAT              NOT NULL,
+  `delivered_at`                    DATETIME           NOT NULL,
+  `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`uuid`),
+  UNIQUE KEY (`order_uuid`),
+  FOREIGN KEY (`order_uuid`) REFERENCES orders(`uuid`),
+  FOREIGN KEY (`shelf_order_uuid`) REFERENCES shelf_orders(`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `deliveries` ADD INDEX (`delivered_at`);
+
+CREATE TABLE `webhook_subscriptions` (
+  `uuid`                            char(36)           NOT NULL,
+  `url`                             varchar(2048)      NOT NULL,
+  `secret`                          varchar(255)       NOT NULL,
+  `kitchen_uuid`                    varchar(36)        NOT NULL DEFAULT '',
+  `event_types`                     varchar(1024)      NOT NULL DEFAULT '',
+  `created_at`                      DATETIME           NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at`                      DATETIME           DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `webhook_deliveries` (
+  `uuid`                            char(36)           NOT NULL,
+  `subscription_uuid`               char(36)           NOT NULL,
+  `event_type`                      varchar(191)       NOT NULL,
+  `order_uuid`                      char(36)           NOT NULL,
+  `payload`                         TEXT               NOT NULL,
+  `status`                          varchar(191)       NOT NULL,
+  `attempts`                        INTEGER            NOT NULL DEFAULT 0,
+  `next_attempt_at`                 DATETIME(6)        NOT NULL,
+  `last_status_code`                INTEGER            NOT NULL DEFAULT 0,
+  `last_error`                      varchar(1024)      NOT NULL DEFAULT '',
+  `last_attempt_at`                 DATETIME(6)        NULL,
+  `delivered_at`                    DATETIME(6)        NULL,
+  `version`                         INTEGER            NOT NULL,
+  `created_at`                      DATETIME(6)        NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  `updated_at`                      DATETIME           DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`uuid`),
+  FOREIGN KEY (`subscription_uuid`) REFERENCES webhook_subscriptions(`uuid`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `webhook_deliveries` ADD INDEX (`status`, `next_attempt_at`);
+ALTER TABLE `webhook_deliveries` ADD INDEX (`subscription_uuid`, `created_at`);
+
+CREATE TABLE `outbox` (
+  `id`                              BIGINT             NOT NULL AUTO_INCREMENT,
+  `dedup_id`                        char(36)           NOT NULL,
+  `kitchen_uuid`                    char(36)           NOT NULL,
+  `order_uuid`                      char(36)           NOT NULL,
+  `event_type`                      varchar(191)       NOT NULL,
+  `shelf_type`                      varchar(191)       NOT NULL DEFAULT '',
+  `from_status`                     varchar(191)       NOT NULL DEFAULT '',
+  `to_status`                       varchar(191)       NOT NULL,
+  `reason`                          varchar(255)       NOT NULL DEFAULT '',
+  `occurred_at`                     DATETIME(6)        NOT NULL,
+  `attempts`                        INTEGER            NOT NULL DEFAULT 0,
+  `last_error`                      varchar(1024)      NOT NULL DEFAULT '',
+  `published_at`                    DATETIME(6)        NULL,
+  `failed_at`                       DATETIME(6)        NULL,
+  `created_at`                      DATETIME(6)        NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY (`dedup_id`),
   FOREIGN KEY (`order_uuid`) REFERENCES orders(`uuid`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
-ALTER TABLE `shelf_orders` ADD INDEX (`order_uuid`);
-ALTER TABLE `shelf_orders` ADD INDEX (`shelf_type`, `order_status`);
-ALTER TABLE `shelf_orders` ADD INDEX (`expires_at`);
\ No newline at end of file
+ALTER TABLE `outbox` ADD INDEX (`published_at`, `failed_at`, `id`);
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// DeliveryService is delivery service interface.
+type DeliveryService interface {
+	StartDelivery(orderUUID guuid.UUID) error
+	ConfirmDelivery(orderUUID guuid.UUID) (*entity.Delivery, error)
+	GetDelivery(orderUUID guuid.UUID) (*entity.Delivery, error)
+}
+
+type deliveryService struct {
+	cfg                  config.AppConfig
+	kitchenService       KitchenService
+	orderRepository      repository.OrderRepository
+	shelfOrderRepository repository.ShelfOrderRepository
+	deliveryRepository   repository.DeliveryRepository
+}
+
+// NewDeliveryService returns a new delivery service.
+func NewDeliveryService(cfg config.AppConfig, kitchenService KitchenService, orderRepository repository.OrderRepository, shelfOrderRepository repository.ShelfOrderRepository, deliveryRepository repository.DeliveryRepository) DeliveryService {
+	return &deliveryService{
+		cfg:                  cfg,
+		kitchenService:       kitchenService,
+		orderRepository:      orderRepository,
+		shelfOrderRepository: shelfOrderRepository,
+		deliveryRepository:   deliveryRepository,
+	}
+}
+
+// StartDelivery marks a picked up order as out for delivery.
+func (d *deliveryService) StartDelivery(orderUUID guuid.UUID) error {
+	var err error
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var shelfOrder *entity.ShelfOrder
+		shelfOrder, err = d.getShelfOrder(orderUUID)
+		if err != nil {
+			return err
+		}
+
+		// Starting a delivery is idempotent.
+		if shelfOrder.OrderStatus == entity.OrderStatusOutForDelivery {
+			return nil
+		}
+
+		var orderEvent *entity.OrderEvent
+		orderEvent, err = entity.NewOrderEvent(
+			orderUUID, shelfOrder.OrderStatus, entity.OrderStatusOutForDelivery, "driver left the kitchen")
+		if err != nil {
+			return err
+		}
+
+		err = d.shelfOrderRepository.UpdateOrderStatus(*shelfOrder, *orderEvent)
+		if errors.Cause(err) == exception.ErrVersionInvalid {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(
+				err, "failed to update status of shelf order %+v", shelfOrder)
+		}
+
+		return nil
+	}
+
+	return errors.Wrapf(err, "failed to start delivery of order %s", orderUUID.String())
+}
+
+// ConfirmDelivery marks an order as delivered and records
+// the value of the order when it reached the customer.
+func (d *deliveryService) ConfirmDelivery(orderUUID guuid.UUID) (*entity.Delivery, error) {
+	var err error
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var shelfOrder *entity.ShelfOrder
+		shelfOrder, err = d.getShelfOrder(orderUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Confirming a delivery is idempotent.
+		if shelfOrder.OrderStatus == entity.OrderStatusDelivered {
+			return d.GetDelivery(orderUUID)
+		}
+
+		var orderEvent *entity.OrderEvent
+		orderEvent, err = entity.NewOrderEvent(
+			orderUUID, shelfOrder.OrderStatus, entity.OrderStatusDelivered, "delivered by driver")
+		if err != nil {
+			return nil, err
+		}
+
+		var order *entity.Order
+		order, err = d.orderRepository.GetOrder(orderUUID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get order")
+		}
+
+		var decayModifier float64
+		decayModifier, err = d.getDecayModifier(*order, *shelfOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		// Food keeps decaying after it leaves the shelf, so we age
+		// the order from when it was placed on a shelf.
+		now := time.Now()
+		orderAge := now.Sub(shelfOrder.CreatedAt)
+		delivery := entity.Delivery{
+			UUID:            guuid.NewV5(orderUUID, "delivery"),
+			OrderUUID:       orderUUID,
+			ShelfOrderUUID:  shelfOrder.UUID,
+			OrderAge:        orderAge,
+			Value:           order.GetValueOnShelf(orderAge, decayModifier),
+			NormalizedValue: order.GetNormalizedValueOnShelf(orderAge, decayModifier),
+			DeliveredAt:     now,
+		}
+
+		// We store the delivery before updating the status so a delivered order
+		// always has a delivery, retries keep the first delivery that was stored.
+		err = d.deliveryRepository.CreateDelivery(delivery)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to store delivery %s", delivery.String())
+		}
+
+		err = d.shelfOrderRepository.UpdateOrderStatus(*shelfOrder, *orderEvent)
+		if errors.Cause(err) == exception.ErrVersionInvalid {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "failed to update status of shelf order %+v", shelfOrder)
+		}
+
+		return d.GetDelivery(orderUUID)
+	}
+
+	return nil, errors.Wrapf(err, "failed to confirm delivery of order %s", orderUUID.String())
+}
+
+// getDecayModifier returns the decay modifier of the shelf an order was placed on,
+// the same one its value was shown with while it was on the shelf.
+func (d *deliveryService) getDecayModifier(order entity.Order, shelfOrder entity.ShelfOrder) (float64, error) {
+	kitchen, err := d.kitchenService.GetKitchen(order.KitchenUUID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get kitchen of order %s", order.UUID.String())
+	}
+
+	// A shelf can be removed from the catalog on restart after the order left it.
+	shelf, ok := kitchen.ShelfCatalog.GetShelf(shelfOrder.ShelfType)
+	if !ok {
+		return 1.0, nil
+	}
+
+	return shelf.DecayModifier, nil
+}
+
+// GetDelivery returns the delivery of an order.
+func (d *deliveryService) GetDelivery(orderUUID guuid.UUID) (*entity.Delivery, error) {
+	delivery, err := d.deliveryRepository.GetDeliveryByOrderUUID(orderUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch delivery")
+	}
+
+	return delivery, nil
+}
+
+// getShelfOrder returns the shelf order of an order that has been placed on a shelf.
+func (d *deliveryService) getShelfOrder(orderUUID guuid.UUID) (*entity.ShelfOrder, error) {
+	shelfOrder, err := d.shelfOrderRepository.GetShelfOrderByOrderUUID(orderUUID)
+	if errors.Cause(err) == exception.ErrNotFound {
+		return nil, errors.Wrapf(
+			exception.ErrInvalidResourceState, "order %s has not been picked up", orderUUID.String())
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch shelf order")
+	}
+
+	return shelfOrder, nil
+}
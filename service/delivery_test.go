@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	// Orders decay twice as fast on the overflow shelf.
+	s.cfg.Shelves[3].DecayModifier = 2
+	deliveryService := s.newDeliveryService()
+
+	order := newTestOrder()
+	shelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   order.UUID,
+		ShelfType:   entity.OverflowShelf,
+		OrderStatus: entity.OrderStatusOutForDelivery,
+		Version:     2,
+		CreatedAt:   time.Now().Add(-100 * time.Second),
+	}
+	expectedDelivery := &entity.Delivery{
+		OrderUUID:      order.UUID,
+		ShelfOrderUUID: shelfOrder.UUID,
+	}
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(shelfOrder, nil),
+		s.orderRepository.EXPECT().GetOrder(order.UUID).Return(&order, nil),
+		s.deliveryRepository.EXPECT().CreateDelivery(gomock.Any()).Do(func(delivery entity.Delivery) {
+			// value = (300 - 100) - (0.45 * 2 * 100) = 110
+			assert.Equal(t, order.UUID, delivery.OrderUUID)
+			assert.InDelta(t, 110.0, delivery.Value, 1.0)
+			assert.InDelta(t, 110.0/300.0, delivery.NormalizedValue, 0.01)
+			assert.True(t, delivery.IsAcceptable())
+		}).Return(nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatus(*shelfOrder, &orderEventMatcher{entity.OrderEvent{
+			FromStatus: entity.OrderStatusOutForDelivery,
+			ToStatus:   entity.OrderStatusDelivered,
+		}}).Return(nil),
+		s.deliveryRepository.EXPECT().GetDeliveryByOrderUUID(order.UUID).Return(expectedDelivery, nil),
+	)
+
+	delivery, err := deliveryService.ConfirmDelivery(order.UUID)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedDelivery, delivery)
+}
+
+func TestConfirmDelivery_InvalidResourceState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	deliveryService := s.newDeliveryService()
+
+	// Orders that are not out for delivery cannot be delivered.
+	for _, orderStatus := range []entity.OrderStatus{
+		entity.OrderStatusReadyForPickup, entity.OrderStatusPickedUp, entity.OrderStatusWasted, entity.OrderStatusCancelled} {
+		shelfOrder := &entity.ShelfOrder{
+			UUID:        guuid.NewV4(),
+			OrderUUID:   guuid.NewV4(),
+			ShelfType:   entity.HotShelf,
+			OrderStatus: orderStatus,
+		}
+
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(shelfOrder.OrderUUID).Return(shelfOrder, nil)
+
+		_, err := deliveryService.ConfirmDelivery(shelfOrder.OrderUUID)
+		assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// DriverService is driver service interface.
+type DriverService interface {
+	Authenticate(driverUUID guuid.UUID, token string) (*entity.Driver, error)
+}
+
+type driverService struct {
+	cfg     config.AppConfig
+	drivers map[guuid.UUID]*entity.Driver
+}
+
+// NewDriverService returns a new driver service
+// holding the drivers set in configuration.
+func NewDriverService(cfg config.AppConfig) (DriverService, error) {
+	drivers, err := cfg.GetDrivers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load drivers")
+	}
+
+	driversByUUID := make(map[guuid.UUID]*entity.Driver)
+	for _, driver := range drivers {
+		driversByUUID[driver.UUID] = driver
+	}
+
+	return &driverService{
+		cfg:     cfg,
+		drivers: driversByUUID,
+	}, nil
+}
+
+// Authenticate returns the driver a uuid and token belong to.
+func (d *driverService) Authenticate(driverUUID guuid.UUID, token string) (*entity.Driver, error) {
+	driver, ok := d.drivers[driverUUID]
+	if !ok || !driver.Authenticate(token) {
+		// Unknown drivers and bad tokens are not told apart so driver uuids cannot be probed.
+		return nil, errors.Wrapf(exception.ErrUnauthorized, "invalid credentials for driver %s", driverUUID.String())
+	}
+
+	return driver, nil
+}
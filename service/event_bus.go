@@ -0,0 +1,155 @@
+package service
+
+import (
+	"log"
+	"sync"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+
+	guuid "github.com/satori/go.uuid"
+)
+
+// subscriberBufferSize is how many events a subscriber can fall behind by
+// before it is dropped, dropped subscribers resume from their last event.
+const subscriberBufferSize = 64
+
+// EventBus is event bus interface.
+type EventBus interface {
+	Publish(event entity.Event) entity.Event
+	Subscribe(filter entity.EventFilter, lastEventID uint64) *Subscription
+	Unsubscribe(subscription *Subscription)
+}
+
+// Subscription receives the events of an event bus that pass its filter.
+type Subscription struct {
+	filter entity.EventFilter
+	events chan entity.Event
+
+	Replay []entity.Event      // events published after the last event seen, oldest first
+	Events <-chan entity.Event // closed once the subscriber falls behind or unsubscribes
+}
+
+type eventBus struct {
+	cfg config.AppConfig
+
+	lock          sync.Mutex
+	lastEventID   uint64
+	recentEvents  []entity.Event // ring buffer of the most recent events
+	nextEvent     int            // index in the ring buffer the next event is stored at
+	subscriptions map[*Subscription]bool
+}
+
+// NewEventBus returns a new in memory event bus keeping
+// the most recent events to replay to subscribers that resume.
+func NewEventBus(cfg config.AppConfig) EventBus {
+	return &eventBus{
+		cfg:           cfg,
+		recentEvents:  make([]entity.Event, 0, cfg.Events.BufferSize),
+		subscriptions: make(map[*Subscription]bool),
+	}
+}
+
+// Publish assigns an event its ID and sends it to every subscriber of the event.
+// It never blocks on a subscriber, subscribers that fall behind are dropped.
+func (e *eventBus) Publish(event entity.Event) entity.Event {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.lastEventID++
+	event.ID = e.lastEventID
+	e.addRecentEvent(event)
+
+	for subscription := range e.subscriptions {
+		if !subscription.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			log.Printf("event bus | dropping subscriber that fell behind at event %d", event.ID)
+			e.removeSubscription(subscription)
+		}
+	}
+
+	return event
+}
+
+// Subscribe returns a subscription to the events that pass a filter. Recent events
+// published after lastEventID are replayed, 0 replays nothing. Event IDs start over
+// when the service restarts so a lastEventID ahead of the bus replays every recent event.
+func (e *eventBus) Subscribe(filter entity.EventFilter, lastEventID uint64) *Subscription {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	events := make(chan entity.Event, subscriberBufferSize)
+	subscription := &Subscription{
+		filter: filter,
+		events: events,
+		Events: events,
+	}
+
+	if lastEventID > 0 {
+		isRestarted := lastEventID > e.lastEventID
+		for _, event := range e.getRecentEvents() {
+			if (isRestarted || event.ID > lastEventID) && filter.Matches(event) {
+				subscription.Replay = append(subscription.Replay, event)
+			}
+		}
+	}
+
+	e.subscriptions[subscription] = true
+	return subscription
+}
+
+// Unsubscribe stops sending events to a subscription.
+func (e *eventBus) Unsubscribe(subscription *Subscription) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.removeSubscription(subscription)
+}
+
+func (e *eventBus) removeSubscription(subscription *Subscription) {
+	if !e.subscriptions[subscription] {
+		return
+	}
+
+	delete(e.subscriptions, subscription)
+	close(subscription.events)
+}
+
+// addRecentEvent stores an event in the ring buffer, overwriting the oldest event once it is full.
+func (e *eventBus) addRecentEvent(event entity.Event) {
+	if e.cfg.Events.BufferSize == 0 {
+		return
+	}
+
+	if len(e.recentEvents) < e.cfg.Events.BufferSize {
+		e.recentEvents = append(e.recentEvents, event)
+	} else {
+		e.recentEvents[e.nextEvent] = event
+	}
+	e.nextEvent = (e.nextEvent + 1) % e.cfg.Events.BufferSize
+}
+
+// getRecentEvents returns the events in the ring buffer, oldest first.
+func (e *eventBus) getRecentEvents() []entity.Event {
+	if len(e.recentEvents) < e.cfg.Events.BufferSize {
+		return e.recentEvents
+	}
+
+	return append(append([]entity.Event{}, e.recentEvents[e.nextEvent:]...), e.recentEvents[:e.nextEvent]...)
+}
+
+// publishOrderEvent publishes an order event recorded at a kitchen on the event bus,
+// order events without an event type are not published.
+func publishOrderEvent(eventBus EventBus, kitchenUUID guuid.UUID, shelfType entity.ShelfType, orderEvent entity.OrderEvent) {
+	event, err := entity.NewEvent(kitchenUUID, shelfType, orderEvent)
+	if err != nil {
+		return
+	}
+
+	eventBus.Publish(*event)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+
+	"github.com/golang/mock/gomock"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBus_Subscribe(t *testing.T) {
+	cfg := config.AppConfig{}
+	cfg.Events.BufferSize = 10
+	eventBus := NewEventBus(cfg)
+
+	otherKitchenUUID := guuid.NewV4()
+	kitchenSubscription := eventBus.Subscribe(entity.EventFilter{KitchenUUID: entity.DefaultKitchenUUID}, 0)
+	orderUUID := guuid.NewV4()
+	orderSubscription := eventBus.Subscribe(entity.EventFilter{OrderUUID: orderUUID}, 0)
+
+	first := eventBus.Publish(newTestEvent(entity.DefaultKitchenUUID, guuid.NewV4()))
+	second := eventBus.Publish(newTestEvent(otherKitchenUUID, orderUUID))
+	assert.Equal(t, uint64(1), first.ID)
+	assert.Equal(t, uint64(2), second.ID)
+
+	// Subscribers only receive the events that pass their filter.
+	assert.Equal(t, first, <-kitchenSubscription.Events)
+	assert.Equal(t, 0, len(kitchenSubscription.Events))
+	assert.Equal(t, second, <-orderSubscription.Events)
+
+	eventBus.Unsubscribe(kitchenSubscription)
+	_, ok := <-kitchenSubscription.Events
+	assert.False(t, ok)
+}
+
+func TestEventBus_Replay(t *testing.T) {
+	cfg := config.AppConfig{}
+	cfg.Events.BufferSize = 3
+	eventBus := NewEventBus(cfg)
+
+	for i := 0; i < 5; i++ {
+		eventBus.Publish(newTestEvent(entity.DefaultKitchenUUID, guuid.NewV4()))
+	}
+
+	// Events published after the last event seen are replayed.
+	subscription := eventBus.Subscribe(entity.EventFilter{}, 3)
+	assert.Equal(t, 2, len(subscription.Replay))
+	assert.Equal(t, uint64(4), subscription.Replay[0].ID)
+	assert.Equal(t, uint64(5), subscription.Replay[1].ID)
+
+	// Only the most recent events are kept.
+	subscription = eventBus.Subscribe(entity.EventFilter{}, 1)
+	assert.Equal(t, 3, len(subscription.Replay))
+	assert.Equal(t, uint64(3), subscription.Replay[0].ID)
+
+	// Event ids are ahead of the bus after a restart, every recent event is replayed.
+	subscription = eventBus.Subscribe(entity.EventFilter{}, 42)
+	assert.Equal(t, 3, len(subscription.Replay))
+
+	// New subscribers do not replay anything.
+	subscription = eventBus.Subscribe(entity.EventFilter{}, 0)
+	assert.Equal(t, 0, len(subscription.Replay))
+}
+
+func TestEventBus_SlowSubscriber(t *testing.T) {
+	cfg := config.AppConfig{}
+	eventBus := NewEventBus(cfg)
+
+	subscription := eventBus.Subscribe(entity.EventFilter{}, 0)
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		eventBus.Publish(newTestEvent(entity.DefaultKitchenUUID, guuid.NewV4()))
+	}
+
+	// A subscriber that falls behind is dropped after the events it was sent.
+	for i := 0; i < subscriberBufferSize; i++ {
+		<-subscription.Events
+	}
+	_, ok := <-subscription.Events
+	assert.False(t, ok)
+}
+
+func TestCreateOrder_PublishesEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+
+	s.orderRepository.EXPECT().CreateOrder(order, gomock.Any())
+
+	subscription := s.getEventBus().Subscribe(entity.EventFilter{OrderUUID: order.UUID}, 0)
+	err := orderService.CreateOrder(order)
+	assert.Nil(t, err)
+
+	event := <-subscription.Events
+	assert.Equal(t, entity.EventTypeOrderCreated, event.Type)
+	assert.Equal(t, entity.DefaultKitchenUUID, event.KitchenUUID)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+)
+
+// JobService is job service interface.
+type JobService interface {
+	UpdateJobStatus(jobStatus entity.JobStatus)
+	GetJobStatuses() []entity.JobStatus
+	GetJobStatus(name string) (*entity.JobStatus, error)
+}
+
+type jobService struct {
+	cfg config.AppConfig
+
+	lock        sync.RWMutex
+	jobStatuses map[string]entity.JobStatus
+}
+
+// NewJobService returns a new in memory record of how this instance's scheduled jobs are running.
+func NewJobService(cfg config.AppConfig) JobService {
+	return &jobService{
+		cfg:         cfg,
+		jobStatuses: make(map[string]entity.JobStatus),
+	}
+}
+
+// UpdateJobStatus records the latest status of a scheduled job.
+func (j *jobService) UpdateJobStatus(jobStatus entity.JobStatus) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.jobStatuses[jobStatus.Name] = jobStatus
+}
+
+// GetJobStatuses returns the status of every scheduled job sorted by name.
+func (j *jobService) GetJobStatuses() []entity.JobStatus {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	jobStatuses := make([]entity.JobStatus, 0, len(j.jobStatuses))
+	for _, jobStatus := range j.jobStatuses {
+		jobStatuses = append(jobStatuses, jobStatus)
+	}
+	sort.Slice(jobStatuses, func(a, b int) bool {
+		return jobStatuses[a].Name < jobStatuses[b].Name
+	})
+
+	return jobStatuses
+}
+
+// GetJobStatus returns the status of a scheduled job.
+func (j *jobService) GetJobStatus(name string) (*entity.JobStatus, error) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	jobStatus, ok := j.jobStatuses[name]
+	if !ok {
+		return nil, errors.Wrapf(exception.ErrNotFound, "job not found - name: %s", name)
+	}
+
+	return &jobStatus, nil
+}
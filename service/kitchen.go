@@ -0,0 +1,138 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// KitchenService is kitchen service interface.
+type KitchenService interface {
+	GetKitchen(kitchenUUID guuid.UUID) (*entity.Kitchen, error)
+	GetKitchens() []*entity.Kitchen
+	UpdateKitchens(kitchens []*entity.Kitchen)
+	HasFreeCookingStation(kitchenUUID guuid.UUID) bool
+	AcquireCookingStation(kitchenUUID guuid.UUID) bool
+	ReleaseCookingStation(kitchenUUID guuid.UUID)
+	CountOrdersCooking(kitchenUUID guuid.UUID) int
+}
+
+type kitchenService struct {
+	cfg          config.AppConfig
+	kitchensLock sync.RWMutex // kitchens are resized on config reload
+	kitchens     []*entity.Kitchen
+
+	stationsLock  sync.Mutex
+	ordersCooking map[guuid.UUID]int // cooking stations in use per kitchen
+}
+
+// NewKitchenService returns a new kitchen service
+// holding the kitchens set in configuration.
+func NewKitchenService(cfg config.AppConfig) (KitchenService, error) {
+	kitchens, err := cfg.GetKitchens()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kitchens")
+	}
+
+	return &kitchenService{
+		cfg:           cfg,
+		kitchens:      kitchens,
+		ordersCooking: make(map[guuid.UUID]int),
+	}, nil
+}
+
+// GetKitchen returns a kitchen by uuid.
+func (k *kitchenService) GetKitchen(kitchenUUID guuid.UUID) (*entity.Kitchen, error) {
+	k.kitchensLock.RLock()
+	defer k.kitchensLock.RUnlock()
+
+	for _, kitchen := range k.kitchens {
+		if kitchen.UUID == kitchenUUID {
+			return kitchen, nil
+		}
+	}
+
+	return nil, errors.Wrapf(exception.ErrNotFound, "kitchen %s does not exist", kitchenUUID.String())
+}
+
+// GetKitchens returns every kitchen.
+func (k *kitchenService) GetKitchens() []*entity.Kitchen {
+	k.kitchensLock.RLock()
+	defer k.kitchensLock.RUnlock()
+
+	return k.kitchens
+}
+
+// UpdateKitchens swaps kitchens while orders are being placed.
+// Shrinking a shelf does not remove orders already on it, the shelf
+// simply accepts no new orders until it drains below its new capacity.
+// Order temps and shelf types are validated against the new shelves.
+func (k *kitchenService) UpdateKitchens(kitchens []*entity.Kitchen) {
+	k.kitchensLock.Lock()
+	defer k.kitchensLock.Unlock()
+
+	var shelfCatalogs []*entity.ShelfCatalog
+	for _, kitchen := range kitchens {
+		shelfCatalogs = append(shelfCatalogs, kitchen.ShelfCatalog)
+	}
+	entity.UseShelfCatalogs(shelfCatalogs...)
+
+	k.kitchens = kitchens
+}
+
+// HasFreeCookingStation returns true if a kitchen has a cooking station that is not in use.
+func (k *kitchenService) HasFreeCookingStation(kitchenUUID guuid.UUID) bool {
+	kitchen, err := k.GetKitchen(kitchenUUID)
+	if err != nil {
+		return false
+	}
+
+	k.stationsLock.Lock()
+	defer k.stationsLock.Unlock()
+
+	return k.ordersCooking[kitchenUUID] < kitchen.CookingStations
+}
+
+// AcquireCookingStation takes a free cooking station at a kitchen and returns
+// false if every station is in use.
+func (k *kitchenService) AcquireCookingStation(kitchenUUID guuid.UUID) bool {
+	kitchen, err := k.GetKitchen(kitchenUUID)
+	if err != nil {
+		return false
+	}
+
+	k.stationsLock.Lock()
+	defer k.stationsLock.Unlock()
+
+	// Removing stations on config reload does not interrupt orders already cooking,
+	// the kitchen simply starts no new orders until enough stations free up.
+	if k.ordersCooking[kitchenUUID] >= kitchen.CookingStations {
+		return false
+	}
+
+	k.ordersCooking[kitchenUUID]++
+	return true
+}
+
+// ReleaseCookingStation frees a cooking station once its order is off of it.
+func (k *kitchenService) ReleaseCookingStation(kitchenUUID guuid.UUID) {
+	k.stationsLock.Lock()
+	defer k.stationsLock.Unlock()
+
+	if k.ordersCooking[kitchenUUID] > 0 {
+		k.ordersCooking[kitchenUUID]--
+	}
+}
+
+// CountOrdersCooking returns how many cooking stations are in use at a kitchen.
+func (k *kitchenService) CountOrdersCooking(kitchenUUID guuid.UUID) int {
+	k.stationsLock.Lock()
+	defer k.stationsLock.Unlock()
+
+	return k.ordersCooking[kitchenUUID]
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireCookingStation(t *testing.T) {
+	// Load app config.
+	cfg := config.AppConfig{}
+	cfg.LoadConfig("../config/development.yaml")
+	cfg.Cooking.Stations = 2
+
+	kitchenService, err := NewKitchenService(cfg)
+	assert.Nil(t, err)
+
+	// Orders only cook while a station is free.
+	assert.True(t, kitchenService.AcquireCookingStation(entity.DefaultKitchenUUID))
+	assert.True(t, kitchenService.AcquireCookingStation(entity.DefaultKitchenUUID))
+	assert.False(t, kitchenService.AcquireCookingStation(entity.DefaultKitchenUUID))
+	assert.Equal(t, 2, kitchenService.CountOrdersCooking(entity.DefaultKitchenUUID))
+
+	kitchenService.ReleaseCookingStation(entity.DefaultKitchenUUID)
+	assert.True(t, kitchenService.AcquireCookingStation(entity.DefaultKitchenUUID))
+
+	// Kitchens that do not exist have no stations.
+	assert.False(t, kitchenService.AcquireCookingStation(guuid.NewV4()))
+}
+
+func TestUpdateKitchens_ShelfCatalogs(t *testing.T) {
+	// Load app config.
+	cfg := config.AppConfig{}
+	cfg.LoadConfig("../config/development.yaml")
+
+	kitchenService, err := NewKitchenService(cfg)
+	assert.Nil(t, err)
+	// Other tests validate orders against the default shelves.
+	defer entity.UseShelfCatalogs(entity.DefaultShelfCatalog())
+
+	warmShelfCatalog, err := entity.NewShelfCatalog([]entity.Shelf{
+		{Type: entity.ShelfType("warm-holding"), Capacity: 10, Temps: []entity.OrderTemp{"warm"}, DecayModifier: 1},
+	})
+	assert.Nil(t, err)
+
+	// Reloaded kitchens replace the order temps and shelf types that are valid.
+	kitchenService.UpdateKitchens([]*entity.Kitchen{
+		{UUID: entity.DefaultKitchenUUID, Name: "default", ShelfCatalog: warmShelfCatalog},
+	})
+	assert.True(t, entity.IsValidOrderTemp("warm"))
+	assert.True(t, entity.IsValidShelfType("warm-holding"))
+	assert.False(t, entity.IsValidOrderTemp(entity.OrderTempHot))
+	assert.False(t, entity.IsValidShelfType(entity.HotShelf))
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// MenuService is menu service interface.
+type MenuService interface {
+	CreateMenuItem(menuItem entity.MenuItem) error
+	GetMenuItem(menuItemUUID guuid.UUID) (*entity.MenuItem, error)
+	GetMenuItems() ([]entity.MenuItem, error)
+	UpdateMenuItem(menuItem entity.MenuItem) error
+	DeleteMenuItem(menuItemUUID guuid.UUID) error
+	SeedMenu(menuItems []entity.MenuItem) error
+}
+
+type menuService struct {
+	cfg                config.AppConfig
+	menuItemRepository repository.MenuItemRepository
+}
+
+// NewMenuService returns a new menu service
+// holding the catalog of items orders are created from.
+func NewMenuService(cfg config.AppConfig, menuItemRepository repository.MenuItemRepository) MenuService {
+	return &menuService{
+		cfg:                cfg,
+		menuItemRepository: menuItemRepository,
+	}
+}
+
+// CreateMenuItem adds an item to the menu.
+func (m *menuService) CreateMenuItem(menuItem entity.MenuItem) error {
+	err := menuItem.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = m.menuItemRepository.CreateMenuItem(menuItem)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create menu item %s", menuItem.Name)
+	}
+
+	return nil
+}
+
+// GetMenuItem returns a menu item.
+func (m *menuService) GetMenuItem(menuItemUUID guuid.UUID) (*entity.MenuItem, error) {
+	menuItem, err := m.menuItemRepository.GetMenuItem(menuItemUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch menu item %s", menuItemUUID.String())
+	}
+
+	return menuItem, nil
+}
+
+// GetMenuItems returns every item on the menu.
+func (m *menuService) GetMenuItems() ([]entity.MenuItem, error) {
+	menuItems, err := m.menuItemRepository.GetMenuItems()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch menu items")
+	}
+
+	return menuItems, nil
+}
+
+// UpdateMenuItem changes a menu item, orders already
+// created from the menu item keep their values.
+func (m *menuService) UpdateMenuItem(menuItem entity.MenuItem) error {
+	err := menuItem.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = m.menuItemRepository.UpdateMenuItem(menuItem)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update menu item %s", menuItem.UUID.String())
+	}
+
+	return nil
+}
+
+// DeleteMenuItem removes an item from the menu.
+func (m *menuService) DeleteMenuItem(menuItemUUID guuid.UUID) error {
+	err := m.menuItemRepository.DeleteMenuItem(menuItemUUID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete menu item %s", menuItemUUID.String())
+	}
+
+	return nil
+}
+
+// SeedMenu adds menu items that are not on the menu yet,
+// items already on the menu are left untouched.
+func (m *menuService) SeedMenu(menuItems []entity.MenuItem) error {
+	for _, menuItem := range menuItems {
+		_, err := m.menuItemRepository.GetMenuItem(menuItem.UUID)
+		if err == nil {
+			continue
+		}
+		if errors.Cause(err) != exception.ErrNotFound {
+			return errors.Wrapf(err, "failed to fetch menu item %s", menuItem.Name)
+		}
+
+		err = m.CreateMenuItem(menuItem)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMenuItem_InvalidMenuItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	menuService := s.newMenuService()
+
+	menuItem := entity.MenuItem{
+		UUID:      entity.NewMenuItemUUID("Cheeze Pizza"),
+		Name:      "Cheeze Pizza",
+		Temp:      entity.OrderTempHot,
+		ShelfLife: 0, // orders would be waste as soon as they are made
+		DecayRate: 0.45,
+	}
+
+	// Invalid menu items are never stored.
+	err := menuService.CreateMenuItem(menuItem)
+	assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err))
+}
+
+func TestSeedMenu(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	menuService := s.newMenuService()
+
+	pizza := entity.MenuItem{
+		UUID:      entity.NewMenuItemUUID("Cheeze Pizza"),
+		Name:      "Cheeze Pizza",
+		Temp:      entity.OrderTempHot,
+		ShelfLife: 300,
+		DecayRate: 0.45,
+	}
+	yogurt := entity.MenuItem{
+		UUID:      entity.NewMenuItemUUID("Yogurt"),
+		Name:      "Yogurt",
+		Temp:      entity.OrderTempCold,
+		ShelfLife: 263,
+		DecayRate: 0.37,
+	}
+
+	// Pizza is already on the menu so only yogurt is created.
+	gomock.InOrder(
+		s.menuItemRepository.EXPECT().GetMenuItem(pizza.UUID).Return(&pizza, nil),
+		s.menuItemRepository.EXPECT().GetMenuItem(yogurt.UUID).Return(nil, exception.ErrNotFound),
+		s.menuItemRepository.EXPECT().CreateMenuItem(yogurt).Return(nil),
+	)
+
+	err := menuService.SeedMenu([]entity.MenuItem{pizza, yogurt})
+	assert.Nil(t, err)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/kitchen-delivery/config"
@@ -15,117 +16,317 @@ import (
 // OrderService is order serivce interface.
 type OrderService interface {
 	CreateOrder(order entity.Order) error
+	StartCooking(order entity.Order) (time.Duration, error)
 	PlaceOrderOnShelf(order entity.Order) error
 	GetOrder(orderUUID guuid.UUID) (*entity.Order, error)
-	PickupOrder() (*entity.Order, error)
+	PickupOrder(kitchenUUID guuid.UUID) (*entity.Order, error)
+	PickupOrderByUUID(orderUUID guuid.UUID) (*entity.Order, error)
+	CancelOrder(orderUUID guuid.UUID) error
 	GetExpiredOrdersOnShelf() ([]*entity.ShelfOrder, error)
+	GetStuckCookingOrders() ([]*entity.Order, error)
+	MarkOrderAsQueued(orderUUID guuid.UUID) error
 	MarkOrderAsWasted(entity.ShelfOrder) error
+	MarkOrderAsEvicted(orderUUID guuid.UUID, reason string) error
+	GetOrderEvents(orderUUID guuid.UUID) ([]*entity.OrderEvent, error)
 }
 
+// maxUpdateAttempts is how many times we retry updating the status of a
+// specific order when its shelf order was updated underneath us.
+const maxUpdateAttempts = 3
+
 type orderService struct {
 	cfg                  config.AppConfig
+	kitchenService       KitchenService
 	orderRepository      repository.OrderRepository
 	shelfOrderRepository repository.ShelfOrderRepository
-	shelfSpace           map[entity.ShelfType]int
+	orderEventRepository repository.OrderEventRepository
+	eventBus             EventBus
 }
 
-// NewOrderService returns a new user service.
-// switch to userRepositories
-func NewOrderService(cfg config.AppConfig, orderRepository repository.OrderRepository, shelfOrderRepository repository.ShelfOrderRepository) OrderService {
-	// Holds how many items each type of shelf can hold at any given time.
-	shelfSpace := map[entity.ShelfType]int{
-		entity.HotShelf:      cfg.ShelfSpace.Hot,
-		entity.ColdShelf:     cfg.ShelfSpace.Cold,
-		entity.FrozenShelf:   cfg.ShelfSpace.Frozen,
-		entity.OverflowShelf: cfg.ShelfSpace.Overflow,
-	}
-
+// NewOrderService returns a new order service placing orders on the shelves
+// of their kitchen and publishing what happens to them on the event bus.
+func NewOrderService(cfg config.AppConfig, kitchenService KitchenService, orderRepository repository.OrderRepository, shelfOrderRepository repository.ShelfOrderRepository, orderEventRepository repository.OrderEventRepository, eventBus EventBus) OrderService {
 	return &orderService{
 		cfg:                  cfg,
+		kitchenService:       kitchenService,
 		orderRepository:      orderRepository,
 		shelfOrderRepository: shelfOrderRepository,
-		shelfSpace:           shelfSpace,
+		orderEventRepository: orderEventRepository,
+		eventBus:             eventBus,
+	}
+}
+
+// getKitchenForOrder returns the kitchen an order was placed at.
+func (o *orderService) getKitchenForOrder(order entity.Order) (*entity.Kitchen, error) {
+	kitchen, err := o.kitchenService.GetKitchen(order.KitchenUUID)
+	if errors.Cause(err) == exception.ErrNotFound {
+		return nil, errors.Wrapf(exception.ErrInvalidInput, "order %s kitchen does not exist - err: %s", order.UUID.String(), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return kitchen, nil
+}
+
+// getShelvesForOrder returns the shelves of an order's kitchen
+// the order can be placed on in placement order.
+func (o *orderService) getShelvesForOrder(order entity.Order) ([]entity.Shelf, error) {
+	kitchen, err := o.getKitchenForOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	shelves := kitchen.ShelfCatalog.GetShelvesForTemp(order.Temp)
+	if len(shelves) == 0 {
+		return nil, errors.Wrapf(
+			exception.ErrInvalidInput, "no shelf at kitchen %s accepts temp %s", kitchen.Name, order.Temp)
 	}
+
+	return shelves, nil
 }
 
 // Create stores an order in the orders table.
 func (o *orderService) CreateOrder(order entity.Order) error {
-	// Store an immutable record of incoming orders.
-	err := o.orderRepository.CreateOrder(order)
+	// Orders are only accepted if their kitchen has a shelf for them.
+	_, err := o.getShelvesForOrder(order)
+	if err != nil {
+		return err
+	}
+
+	orderEvent, err := entity.NewOrderEvent(order.UUID, "", entity.OrderStatusReceived, "order received")
+	if err != nil {
+		return err
+	}
+
+	// Store an immutable record of incoming orders along with their received event.
+	err = o.orderRepository.CreateOrder(order, *orderEvent)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create order, order: %+v", order)
 	}
 
+	publishOrderEvent(o.eventBus, order.KitchenUUID, "", *orderEvent)
 	return nil
 }
 
-// PlaceOrderOnShelf places an order on the shelf.
+// StartCooking moves an order pulled off of the order queue onto a cooking station
+// and returns how long it cooks for. Orders with no prep time are not cooked.
+func (o *orderService) StartCooking(order entity.Order) (time.Duration, error) {
+	kitchen, err := o.getKitchenForOrder(order)
+	if err != nil {
+		return 0, err
+	}
+
+	prepTime := kitchen.GetPrepTime(order)
+	if prepTime == 0 {
+		return 0, nil
+	}
+
+	// Orders cancelled while waiting on the order queue are never cooked.
+	isShelved, err := o.isShelved(order)
+	if err != nil {
+		return 0, err
+	}
+	if isShelved {
+		return 0, nil
+	}
+
+	reason := fmt.Sprintf("cooking for %s", prepTime)
+	orderEvent, err := entity.NewOrderEvent(order.UUID, entity.OrderStatusQueued, entity.OrderStatusCooking, reason)
+	if err != nil {
+		return 0, err
+	}
+
+	// An order redelivered after its worker stopped mid-cook already entered cooking,
+	// it is cooked again from the start.
+	err = o.orderEventRepository.CreateOrderEvent(*orderEvent)
+	if errors.Cause(err) == exception.ErrInvalidResourceState {
+		return prepTime, nil
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to mark order as cooking %s", order.UUID.String())
+	}
+
+	return prepTime, nil
+}
+
+// isShelved returns true if a previous worker already placed an order on a shelf
+// and an ErrInvalidResourceState if the order was cancelled. Items of a parent order
+// are cancelled once another item was wasted, evicted or cancelled.
+func (o *orderService) isShelved(order entity.Order) (bool, error) {
+	existingShelfOrder, err := o.shelfOrderRepository.GetShelfOrderByOrderUUID(order.UUID)
+	if err != nil && errors.Cause(err) != exception.ErrNotFound {
+		return false, errors.Wrapf(err, "failed to fetch shelf order of order %+v", order)
+	}
+	if existingShelfOrder != nil {
+		if existingShelfOrder.OrderStatus == entity.OrderStatusCancelled {
+			return false, errors.Wrapf(
+				exception.ErrInvalidResourceState, "order %s was cancelled", order.UUID.String())
+		}
+
+		return true, nil
+	}
+
+	// A parent order can no longer be picked up once one of its items failed,
+	// so we do not cook or shelve the rest of its items.
+	failedStatus, err := o.getFailedItemStatus(order.ParentOrderUUID)
+	if err != nil {
+		return false, err
+	}
+	if failedStatus != "" {
+		reason := fmt.Sprintf("item of parent order was %s", failedStatus)
+		err = o.retireUnshelvedOrder(order.UUID, entity.OrderStatusCancelled, reason)
+		if err != nil {
+			return false, err
+		}
+
+		return false, errors.Wrapf(
+			exception.ErrInvalidResourceState, "order %s was cancelled, %s", order.UUID.String(), reason)
+	}
+
+	return false, nil
+}
+
+// PlaceOrderOnShelf places an order on the shelf once it is cooked.
 func (o *orderService) PlaceOrderOnShelf(order entity.Order) error {
-	// Check count of orders in "hot" w/ status of ready for pick up.
-	correspondingShelfType := order.GetShelfType()
-	numOfOrders, err := o.shelfOrderRepository.CountOrdersOnShelf(correspondingShelfType)
+	// Orders can be cancelled while waiting on the order queue or cooking.
+	// We also skip orders that a previous worker already placed on a shelf.
+	isShelved, err := o.isShelved(order)
+	if err != nil {
+		return err
+	}
+	if isShelved {
+		return nil
+	}
+
+	kitchen, err := o.getKitchenForOrder(order)
+	if err != nil {
+		return err
+	}
+
+	shelves, err := o.getShelvesForOrder(order)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create order %+v", order)
+		return err
 	}
 
-	// Determine if the corresponding shelf has space.
-	var isOverflowShelfFull bool
-	isCorrespondingShelfFull := numOfOrders >= o.shelfSpace[correspondingShelfType]
-	if isCorrespondingShelfFull {
-		// If the corresponding shelf is full we count orders on overflow shelf.
-		numOfOrders, err = o.shelfOrderRepository.CountOrdersOnShelf(entity.OverflowShelf)
+	// Place the order on the first shelf with space, shelves dedicated
+	// to the order's temp are checked before overflow shelves.
+	// Standard orders leave space reserved for express and vip orders free.
+	var shelf *entity.Shelf
+	for i := range shelves {
+		numOfOrders, err := o.shelfOrderRepository.CountOrdersOnShelf(order.KitchenUUID, shelves[i].Type)
 		if err != nil {
 			return errors.Wrapf(err, "failed to count orders on shelf %+v", order)
 		}
 
-		isOverflowShelfFull = numOfOrders >= o.shelfSpace[entity.OverflowShelf]
+		if numOfOrders < shelves[i].GetCapacityFor(order.Priority) {
+			shelf = &shelves[i]
+			break
+		}
 	}
 
-	// First, if both the corresponding shelf and the overflow shelf are full
-	// we throw a retriable service full shelf exception so a caller can handle it explictly.
-	areAllShelvesFull := isCorrespondingShelfFull && isOverflowShelfFull
-	if areAllShelvesFull {
-		return errors.Wrap(
-			exception.ErrFullShelf, "all shelves are filled, please retry again later")
+	// If every shelf accepting the order is full we make room by evicting a lower priority order.
+	if shelf == nil {
+		shelf, err = o.evictForOrder(order, shelves)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Now we know that either the corresponding shelf has
-	// space or the overflow shelf has space.
-	var shelfType entity.ShelfType
-
-	// Second, we check if we can place the order on the corresponding shelf.
-	if !isCorrespondingShelfFull {
-		shelfType = order.GetShelfType()
-	} else { // If we cannot, we know we can place it on the overflow shelf is not full.
-		shelfType = entity.OverflowShelf
+	// If there is still no room we throw a retriable
+	// service full shelf exception so a caller can handle it explictly.
+	if shelf == nil {
+		return errors.Wrap(
+			exception.ErrFullShelf, "all shelves are filled, please retry again later")
 	}
+	shelfType := shelf.Type
 
 	// Next:
-	//    a. Calculate ttl and expiration date
+	//    a. Calculate ttl and expiration date, decay starts once the order is cooked
 	//    b. Form a shelf order w/ version 0
 	//    c. Place shelf order on a queue that the kitchen pulls off of.
 
-	ttl := order.GetTTL()
+	ttl := order.GetTTLOnShelf(shelf.DecayModifier)
 	now := time.Now()
 	expirationDate := now.Add(time.Second * time.Duration(ttl))
 
 	shelfOrder := entity.ShelfOrder{
-		UUID:        guuid.NewV4(),
-		OrderUUID:   order.UUID,
-		ShelfType:   shelfType,
-		OrderStatus: entity.OrderStatusReadyForPickup,
-		Version:     0,
-		ExpiresAt:   expirationDate,
+		UUID:            guuid.NewV4(),
+		OrderUUID:       order.UUID,
+		KitchenUUID:     order.KitchenUUID,
+		ParentOrderUUID: order.ParentOrderUUID,
+		Priority:        order.Priority,
+		ShelfType:       shelfType,
+		OrderStatus:     entity.OrderStatusReadyForPickup,
+		Version:         0,
+		ExpiresAt:       expirationDate,
+	}
+
+	// Orders with a prep time come off of a cooking station.
+	fromStatus := entity.OrderStatusQueued
+	if kitchen.GetPrepTime(order) > 0 {
+		fromStatus = entity.OrderStatusCooking
+	}
+
+	reason := fmt.Sprintf("placed on %s shelf", shelfType)
+	orderEvent, err := entity.NewOrderEvent(order.UUID, fromStatus, shelfOrder.OrderStatus, reason)
+	if err != nil {
+		return err
 	}
 
-	err = o.shelfOrderRepository.AddOrderToShelf(shelfOrder)
+	err = o.shelfOrderRepository.AddOrderToShelf(shelfOrder, *orderEvent)
 	if err != nil {
 		return errors.Wrapf(err, "failed to add order, order: %+v", order)
 	}
 
+	publishOrderEvent(o.eventBus, order.KitchenUUID, shelfType, *orderEvent)
 	return nil
 }
 
+// evictForOrder evicts the lowest priority order that expires soonest from the shelves
+// accepting an order and returns the shelf it made room on. No order is evicted
+// and no shelf is returned if every order on the shelves has at least the order's priority.
+func (o *orderService) evictForOrder(order entity.Order, shelves []entity.Shelf) (*entity.Shelf, error) {
+	// Lowest priority orders are evicted first.
+	for i := len(entity.OrderPrioritiesByRank) - 1; i >= 0; i-- {
+		priority := entity.OrderPrioritiesByRank[i]
+		if !order.Priority.IsHigherThan(priority) {
+			break
+		}
+
+		for j := range shelves {
+			shelfOrder, err := o.shelfOrderRepository.GetOrderToEvict(order.KitchenUUID, shelves[j].Type, priority)
+			if errors.Cause(err) == exception.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to find order to evict from shelf %s", shelves[j].Type)
+			}
+
+			reason := fmt.Sprintf("evicted to make room for %s order %s", order.Priority, order.UUID.String())
+			orderEvent, err := entity.NewOrderEvent(
+				shelfOrder.OrderUUID, shelfOrder.OrderStatus, entity.OrderStatusEvicted, reason)
+			if err != nil {
+				return nil, err
+			}
+
+			// If the order left the shelf underneath us, ex: it was picked up,
+			// its space is free all the same.
+			err = o.shelfOrderRepository.UpdateOrderStatus(*shelfOrder, *orderEvent)
+			if err != nil && errors.Cause(err) != exception.ErrVersionInvalid {
+				return nil, errors.Wrapf(err, "failed to evict shelf order %+v", shelfOrder)
+			}
+			if err == nil {
+				publishOrderEvent(o.eventBus, shelfOrder.KitchenUUID, shelfOrder.ShelfType, *orderEvent)
+			}
+
+			return &shelves[j], nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (o *orderService) GetOrder(orderUUID guuid.UUID) (*entity.Order, error) {
 	// Fetch the corresponding order so the consumer (driver) has all the details.
 	order, err := o.orderRepository.GetOrder(orderUUID)
@@ -136,21 +337,28 @@ func (o *orderService) GetOrder(orderUUID guuid.UUID) (*entity.Order, error) {
 	return order, nil
 }
 
-func (o *orderService) PickupOrder() (*entity.Order, error) {
-	// Get order that is ready for pickup from shelf that
+func (o *orderService) PickupOrder(kitchenUUID guuid.UUID) (*entity.Order, error) {
+	// Get order that is ready for pickup from a kitchen's shelf that
 	// has an expiration date that is the most soon.
 	// We do this to minimize waste.
-	shelfOrder, err := o.shelfOrderRepository.GetOpenOrder()
+	shelfOrder, err := o.shelfOrderRepository.GetOpenOrder(kitchenUUID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch open order")
 	}
 
+	orderEvent, err := entity.NewOrderEvent(
+		shelfOrder.OrderUUID, shelfOrder.OrderStatus, entity.OrderStatusPickedUp, "picked up by driver")
+	if err != nil {
+		return nil, err
+	}
+
 	// Update shelf order status to be "picked_up".
-	err = o.shelfOrderRepository.UpdateOrderStatus(*shelfOrder, entity.OrderStatusPickedUp)
+	err = o.shelfOrderRepository.UpdateOrderStatus(*shelfOrder, *orderEvent)
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "failed to update status of shelf order %+v", shelfOrder)
 	}
+	publishOrderEvent(o.eventBus, shelfOrder.KitchenUUID, shelfOrder.ShelfType, *orderEvent)
 
 	// Fetch the corresponding order so the consumer (driver) has all the details.
 	order, err := o.orderRepository.GetOrder(shelfOrder.OrderUUID)
@@ -161,6 +369,269 @@ func (o *orderService) PickupOrder() (*entity.Order, error) {
 	return order, nil
 }
 
+// PickupOrderByUUID picks up a specific order off of its shelf.
+func (o *orderService) PickupOrderByUUID(orderUUID guuid.UUID) (*entity.Order, error) {
+	var err error
+
+	// A shelf order can be updated between reading it and updating it,
+	// ex: it expires. We re-read it so we return why the pickup failed.
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var shelfOrder *entity.ShelfOrder
+		shelfOrder, err = o.getShelfOrderForPickup(orderUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		var orderEvent *entity.OrderEvent
+		orderEvent, err = entity.NewOrderEvent(
+			orderUUID, shelfOrder.OrderStatus, entity.OrderStatusPickedUp, "picked up by driver")
+		if err != nil {
+			return nil, err
+		}
+
+		// Update shelf order status to be "picked_up".
+		err = o.shelfOrderRepository.UpdateOrderStatus(*shelfOrder, *orderEvent)
+		if errors.Cause(err) == exception.ErrVersionInvalid {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "failed to update status of shelf order %+v", shelfOrder)
+		}
+		publishOrderEvent(o.eventBus, shelfOrder.KitchenUUID, shelfOrder.ShelfType, *orderEvent)
+
+		// Fetch the corresponding order so the consumer (driver) has all the details.
+		order, err := o.orderRepository.GetOrder(orderUUID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get order")
+		}
+
+		return order, nil
+	}
+
+	return nil, errors.Wrapf(err, "failed to pickup order %s", orderUUID.String())
+}
+
+// getShelfOrderForPickup returns the shelf order of an order if it is ready for pickup.
+func (o *orderService) getShelfOrderForPickup(orderUUID guuid.UUID) (*entity.ShelfOrder, error) {
+	shelfOrder, err := o.shelfOrderRepository.GetShelfOrderByOrderUUID(orderUUID)
+	if errors.Cause(err) == exception.ErrNotFound {
+		// Order might exist but has not been placed on a shelf yet.
+		_, err = o.orderRepository.GetOrder(orderUUID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get order")
+		}
+
+		return nil, errors.Wrapf(
+			exception.ErrInvalidResourceState, "order %s has not been placed on a shelf yet", orderUUID.String())
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch shelf order")
+	}
+
+	// Items of a parent order are only picked up together.
+	nullUUID := guuid.NullUUID{}
+	if shelfOrder.ParentOrderUUID != nullUUID.UUID {
+		return nil, errors.Wrapf(exception.ErrInvalidResourceState,
+			"order %s is an item of parent order %s", orderUUID.String(), shelfOrder.ParentOrderUUID.String())
+	}
+
+	switch shelfOrder.OrderStatus {
+	case entity.OrderStatusReadyForPickup:
+		return shelfOrder, nil
+	case entity.OrderStatusPickedUp:
+		return nil, errors.Wrapf(
+			exception.ErrInvalidResourceState, "order %s has already been picked up", orderUUID.String())
+	case entity.OrderStatusWasted:
+		return nil, errors.Wrapf(
+			exception.ErrInvalidResourceState, "order %s has been wasted", orderUUID.String())
+	default:
+		return nil, errors.Wrapf(
+			exception.ErrInvalidResourceState, "order %s is %s", orderUUID.String(), shelfOrder.OrderStatus)
+	}
+}
+
+// CancelOrder takes an order off of its shelf or marks it
+// so workers skip it if it has not been placed yet.
+func (o *orderService) CancelOrder(orderUUID guuid.UUID) error {
+	var err error
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var shelfOrder *entity.ShelfOrder
+		shelfOrder, err = o.shelfOrderRepository.GetShelfOrderByOrderUUID(orderUUID)
+		if errors.Cause(err) == exception.ErrNotFound {
+			// If a worker placed the order on a shelf underneath us we take it off of the shelf.
+			err = o.retireUnshelvedOrder(orderUUID, entity.OrderStatusCancelled, "cancelled by customer")
+			if errors.Cause(err) == exception.ErrInvalidResourceState {
+				continue
+			}
+			return err
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch shelf order")
+		}
+
+		// Cancellation is idempotent.
+		if shelfOrder.OrderStatus == entity.OrderStatusCancelled {
+			return nil
+		}
+
+		// Orders that have left the shelf can no longer be cancelled.
+		// Cancelled orders no longer count towards shelf capacity.
+		err = o.retireShelfOrder(*shelfOrder, entity.OrderStatusCancelled, "cancelled by customer")
+		if errors.Cause(err) == exception.ErrVersionInvalid {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(
+				err, "failed to update status of shelf order %+v", shelfOrder)
+		}
+
+		return nil
+	}
+
+	return errors.Wrapf(err, "failed to cancel order %s", orderUUID.String())
+}
+
+// retireShelfOrder moves an order on a shelf to a failed status, ex: wasted, together with
+// every other item of its parent order on a shelf, since a parent order is only picked up
+// with all of its items. Every shelf order is updated in one transaction.
+func (o *orderService) retireShelfOrder(shelfOrder entity.ShelfOrder, toStatus entity.OrderStatus, reason string) error {
+	orderEvent, err := entity.NewOrderEvent(shelfOrder.OrderUUID, shelfOrder.OrderStatus, toStatus, reason)
+	if err != nil {
+		return err
+	}
+
+	siblingShelfOrders, siblingEvents, err := o.getSiblingsToRetire(
+		shelfOrder.OrderUUID, shelfOrder.ParentOrderUUID, toStatus)
+	if err != nil {
+		return err
+	}
+
+	shelfOrders := append([]entity.ShelfOrder{shelfOrder}, siblingShelfOrders...)
+	orderEvents := append([]entity.OrderEvent{*orderEvent}, siblingEvents...)
+	err = o.shelfOrderRepository.UpdateOrderStatuses(shelfOrders, orderEvents)
+	if err != nil {
+		return err
+	}
+
+	for i := range shelfOrders {
+		publishOrderEvent(o.eventBus, shelfOrders[i].KitchenUUID, shelfOrders[i].ShelfType, orderEvents[i])
+	}
+	return nil
+}
+
+// retireUnshelvedOrder moves an order that has not been placed on a shelf to a failed status,
+// ex: cancelled. We store a shelf order that never takes up shelf space, workers check for it
+// before placing an order on a shelf. Every other item of its parent order on a shelf leaves
+// the shelf in the same transaction.
+func (o *orderService) retireUnshelvedOrder(orderUUID guuid.UUID, toStatus entity.OrderStatus, reason string) error {
+	order, err := o.orderRepository.GetOrder(orderUUID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get order")
+	}
+
+	orderStatus, err := o.getOrderStatus(orderUUID)
+	if err != nil {
+		return err
+	}
+
+	orderEvent, err := entity.NewOrderEvent(orderUUID, orderStatus, toStatus, reason)
+	if err != nil {
+		return err
+	}
+
+	shelves, err := o.getShelvesForOrder(*order)
+	if err != nil {
+		return err
+	}
+
+	shelfOrder := entity.ShelfOrder{
+		UUID:            guuid.NewV4(),
+		OrderUUID:       order.UUID,
+		KitchenUUID:     order.KitchenUUID,
+		ParentOrderUUID: order.ParentOrderUUID,
+		ShelfType:       shelves[0].Type,
+		OrderStatus:     toStatus,
+		Version:         0,
+		ExpiresAt:       time.Now(),
+	}
+
+	siblingShelfOrders, siblingEvents, err := o.getSiblingsToRetire(order.UUID, order.ParentOrderUUID, toStatus)
+	if err != nil {
+		return err
+	}
+
+	err = o.shelfOrderRepository.AddOrderToShelfAndUpdate(shelfOrder, *orderEvent, siblingShelfOrders, siblingEvents)
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark order as %s, order: %+v", toStatus, order)
+	}
+
+	// The order was never on a shelf.
+	publishOrderEvent(o.eventBus, order.KitchenUUID, "", *orderEvent)
+	for i := range siblingShelfOrders {
+		publishOrderEvent(o.eventBus, siblingShelfOrders[i].KitchenUUID, siblingShelfOrders[i].ShelfType, siblingEvents[i])
+	}
+	return nil
+}
+
+// getSiblingsToRetire returns the shelf orders of the other items of a parent order still on
+// a shelf with the order events of them being cancelled because an item failed.
+func (o *orderService) getSiblingsToRetire(orderUUID guuid.UUID, parentOrderUUID guuid.UUID, failedStatus entity.OrderStatus) ([]entity.ShelfOrder, []entity.OrderEvent, error) {
+	if parentOrderUUID == guuid.Nil {
+		return nil, nil, nil
+	}
+
+	itemShelfOrders, err := o.shelfOrderRepository.GetShelfOrdersByParentOrderUUID(parentOrderUUID)
+	if err != nil {
+		return nil, nil, errors.Wrapf(
+			err, "failed to fetch shelf orders of parent order %s", parentOrderUUID.String())
+	}
+
+	var shelfOrders []entity.ShelfOrder
+	var orderEvents []entity.OrderEvent
+	reason := fmt.Sprintf("item %s of parent order was %s", orderUUID.String(), failedStatus)
+	for _, itemShelfOrder := range itemShelfOrders {
+		if itemShelfOrder.OrderUUID == orderUUID || itemShelfOrder.OrderStatus != entity.OrderStatusReadyForPickup {
+			continue
+		}
+
+		orderEvent, err := entity.NewOrderEvent(
+			itemShelfOrder.OrderUUID, itemShelfOrder.OrderStatus, entity.OrderStatusCancelled, reason)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		shelfOrders = append(shelfOrders, *itemShelfOrder)
+		orderEvents = append(orderEvents, *orderEvent)
+	}
+
+	return shelfOrders, orderEvents, nil
+}
+
+// getFailedItemStatus returns the status of an item of a parent order that was wasted,
+// evicted or cancelled, or an empty status if every item is still on its way.
+func (o *orderService) getFailedItemStatus(parentOrderUUID guuid.UUID) (entity.OrderStatus, error) {
+	if parentOrderUUID == guuid.Nil {
+		return "", nil
+	}
+
+	itemShelfOrders, err := o.shelfOrderRepository.GetShelfOrdersByParentOrderUUID(parentOrderUUID)
+	if err != nil {
+		return "", errors.Wrapf(
+			err, "failed to fetch shelf orders of parent order %s", parentOrderUUID.String())
+	}
+
+	for _, itemShelfOrder := range itemShelfOrders {
+		switch itemShelfOrder.OrderStatus {
+		case entity.OrderStatusWasted, entity.OrderStatusEvicted, entity.OrderStatusCancelled:
+			return itemShelfOrder.OrderStatus, nil
+		}
+	}
+
+	return "", nil
+}
+
 func (o *orderService) GetExpiredOrdersOnShelf() ([]*entity.ShelfOrder, error) {
 	shelfOrders, err := o.shelfOrderRepository.GetExpiredOrders()
 	if err != nil {
@@ -170,12 +641,95 @@ func (o *orderService) GetExpiredOrdersOnShelf() ([]*entity.ShelfOrder, error) {
 	return shelfOrders, nil
 }
 
+// GetStuckCookingOrders returns orders that are still cooking cooking.requeue_after seconds
+// after they started, ex: the instance cooking them stopped.
+func (o *orderService) GetStuckCookingOrders() ([]*entity.Order, error) {
+	cookingSince := time.Now().Add(-time.Duration(o.cfg.Cooking.RequeueAfter) * time.Second)
+	orders, err := o.orderRepository.GetOrdersCookingSince(cookingSince)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch stuck cooking orders")
+	}
+
+	return orders, nil
+}
+
+// MarkOrderAsQueued records that an order was placed on the order queue.
+func (o *orderService) MarkOrderAsQueued(orderUUID guuid.UUID) error {
+	orderEvent, err := entity.NewOrderEvent(
+		orderUUID, entity.OrderStatusReceived, entity.OrderStatusQueued, "placed on order queue")
+	if err != nil {
+		return err
+	}
+
+	// Requests are retried once an order is queued, ex: its push failed.
+	err = o.orderEventRepository.CreateOrderEvent(*orderEvent)
+	if errors.Cause(err) == exception.ErrInvalidResourceState {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark order as queued %s", orderUUID.String())
+	}
+
+	return nil
+}
+
 func (o *orderService) MarkOrderAsWasted(shelfOrder entity.ShelfOrder) error {
-	newOrderStatus := entity.OrderStatusWasted
-	err := o.shelfOrderRepository.UpdateOrderStatus(shelfOrder, newOrderStatus)
+	err := o.retireShelfOrder(shelfOrder, entity.OrderStatusWasted, "shelf life expired")
 	if err != nil {
 		return errors.Wrapf(err, "faield to mark order as wasted %s", err.Error())
 	}
 
 	return nil
 }
+
+// MarkOrderAsEvicted records that the kitchen dropped an order, ex: all shelves are full.
+func (o *orderService) MarkOrderAsEvicted(orderUUID guuid.UUID, reason string) error {
+	shelfOrder, err := o.shelfOrderRepository.GetShelfOrderByOrderUUID(orderUUID)
+	if err != nil && errors.Cause(err) != exception.ErrNotFound {
+		return errors.Wrap(err, "failed to fetch shelf order")
+	}
+
+	// If the order is on a shelf we take it off of the shelf.
+	if shelfOrder != nil {
+		err = o.retireShelfOrder(*shelfOrder, entity.OrderStatusEvicted, reason)
+		if err != nil {
+			return errors.Wrapf(err, "failed to mark order as evicted %s", orderUUID.String())
+		}
+
+		return nil
+	}
+
+	// The order was dropped before it was placed on a shelf.
+	return o.retireUnshelvedOrder(orderUUID, entity.OrderStatusEvicted, reason)
+}
+
+// GetOrderEvents returns the status history of an order.
+func (o *orderService) GetOrderEvents(orderUUID guuid.UUID) ([]*entity.OrderEvent, error) {
+	// Verify the order exists so callers can tell a missing order from an empty history.
+	_, err := o.orderRepository.GetOrder(orderUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get order")
+	}
+
+	orderEvents, err := o.orderEventRepository.GetOrderEvents(orderUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch order events")
+	}
+
+	return orderEvents, nil
+}
+
+// getOrderStatus returns the current status of an order from its history.
+func (o *orderService) getOrderStatus(orderUUID guuid.UUID) (entity.OrderStatus, error) {
+	orderEvents, err := o.orderEventRepository.GetOrderEvents(orderUUID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch order events")
+	}
+
+	// Orders created before we recorded history went straight onto the order queue.
+	if len(orderEvents) == 0 {
+		return entity.OrderStatusQueued, nil
+	}
+
+	return orderEvents[len(orderEvents)-1].ToStatus, nil
+}
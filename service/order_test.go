@@ -7,7 +7,6 @@ import (
 	"github.com/kitchen-delivery/config"
 	"github.com/kitchen-delivery/entity"
 	"github.com/kitchen-delivery/entity/exception"
-	"github.com/kitchen-delivery/service/repository"
 	"github.com/pkg/errors"
 
 	"github.com/golang/mock/gomock"
@@ -19,50 +18,33 @@ func TestNewOrderService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Load app config.
-	cfg := config.AppConfig{}
-	cfg.LoadConfig("../config/development.yaml")
-
-	orderRepository := repository.NewMockOrderRepository(ctrl)
-	shelfOrderRepository := repository.NewMockShelfOrderRepository(ctrl)
+	s := newTestServices(t, ctrl)
 
 	expected := &orderService{
-		cfg:                  cfg,
-		orderRepository:      orderRepository,
-		shelfOrderRepository: shelfOrderRepository,
-		shelfSpace: map[entity.ShelfType]int{
-			entity.HotShelf:      cfg.ShelfSpace.Hot,
-			entity.ColdShelf:     cfg.ShelfSpace.Cold,
-			entity.FrozenShelf:   cfg.ShelfSpace.Frozen,
-			entity.OverflowShelf: cfg.ShelfSpace.Overflow,
-		},
+		cfg:                  s.cfg,
+		kitchenService:       s.getKitchenService(),
+		orderRepository:      s.orderRepository,
+		shelfOrderRepository: s.shelfOrderRepository,
+		orderEventRepository: s.orderEventRepository,
+		eventBus:             s.getEventBus(),
 	}
 
-	orderService := NewOrderService(cfg, orderRepository, shelfOrderRepository)
-	assert.Equal(t, expected, orderService)
+	assert.Equal(t, expected, s.newOrderService())
 }
 
 func TestCreateOrder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Load app config.
-	cfg := config.AppConfig{}
-	cfg.LoadConfig("../config/development.yaml")
-
-	orderRepository := repository.NewMockOrderRepository(ctrl)
-	shelfOrderRepository := repository.NewMockShelfOrderRepository(ctrl)
-	orderService := NewOrderService(cfg, orderRepository, shelfOrderRepository)
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
 
-	order := entity.Order{
-		UUID:      guuid.NewV4(),
-		Name:      "Cheeze Pizza",
-		Temp:      entity.OrderTempHot,
-		ShelfLife: 300,
-		DecayRate: 0.45,
-	}
+	order := newTestOrder()
 
-	orderRepository.EXPECT().CreateOrder(order)
+	s.orderRepository.EXPECT().CreateOrder(order, &orderEventMatcher{entity.OrderEvent{
+		OrderUUID: order.UUID,
+		ToStatus:  entity.OrderStatusReceived,
+	}})
 
 	err := orderService.CreateOrder(order)
 	assert.Nil(t, err)
@@ -72,22 +54,11 @@ func TestPlaceOrderOnShelf(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Load app config.
-	cfg := config.AppConfig{}
-	cfg.LoadConfig("../config/development.yaml")
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
 
-	orderRepository := repository.NewMockOrderRepository(ctrl)
-	shelfOrderRepository := repository.NewMockShelfOrderRepository(ctrl)
-	orderService := NewOrderService(cfg, orderRepository, shelfOrderRepository)
-
-	order := entity.Order{
-		UUID:      guuid.NewV4(),
-		Name:      "Cheeze Pizza",
-		Temp:      entity.OrderTempHot,
-		ShelfLife: 300,
-		DecayRate: 0.45,
-	}
-	shelfType := order.GetShelfType()
+	order := newTestOrder()
+	shelfType := entity.HotShelf
 
 	// Prepare expected shelf order.
 	ttl := order.GetTTL()
@@ -96,43 +67,143 @@ func TestPlaceOrderOnShelf(t *testing.T) {
 
 	expectedShelfOrder := entity.ShelfOrder{
 		OrderUUID:   order.UUID,
-		ShelfType:   order.GetShelfType(),
+		ShelfType:   entity.HotShelf,
 		OrderStatus: entity.OrderStatusReadyForPickup,
 		Version:     0,
 		ExpiresAt:   expirationDate,
 	}
 
 	gomock.InOrder(
-		shelfOrderRepository.EXPECT().CountOrdersOnShelf(shelfType).Return(3, nil),
-		shelfOrderRepository.EXPECT().AddOrderToShelf(&shelfOrderMatcher{expectedShelfOrder}).Return(nil),
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, shelfType).Return(3, nil),
+		// Hot orders are cooked before they are placed on a shelf.
+		s.shelfOrderRepository.EXPECT().AddOrderToShelf(&shelfOrderMatcher{expectedShelfOrder}, &orderEventMatcher{entity.OrderEvent{
+			OrderUUID:  order.UUID,
+			FromStatus: entity.OrderStatusCooking,
+			ToStatus:   entity.OrderStatusReadyForPickup,
+		}}).Return(nil),
 	)
 
 	err := orderService.PlaceOrderOnShelf(order)
 	assert.Nil(t, err)
 }
 
-func TestPlaceOrderOnShelf_CountOrdersOnShelfError(t *testing.T) {
+func TestStartCooking(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Load app config.
-	cfg := config.AppConfig{}
-	cfg.LoadConfig("../config/development.yaml")
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
 
-	orderRepository := repository.NewMockOrderRepository(ctrl)
-	shelfOrderRepository := repository.NewMockShelfOrderRepository(ctrl)
-	orderService := NewOrderService(cfg, orderRepository, shelfOrderRepository)
+	order := newTestOrder()
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.orderEventRepository.EXPECT().CreateOrderEvent(&orderEventMatcher{entity.OrderEvent{
+			OrderUUID:  order.UUID,
+			FromStatus: entity.OrderStatusQueued,
+			ToStatus:   entity.OrderStatusCooking,
+		}}).Return(nil),
+	)
+
+	// Hot orders are cooked for the kitchen's hot prep time.
+	prepTime, err := orderService.StartCooking(order)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(s.cfg.Cooking.PrepTimes["hot"])*time.Second, prepTime)
+
+	// Frozen orders have no prep time and go straight to a shelf.
+	order.Temp = entity.OrderTempFrozen
+	prepTime, err = orderService.StartCooking(order)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), prepTime)
+}
 
+func TestStartCooking_MenuItemPrepTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	// An order's own prep time wins over the kitchen's prep time for its temp.
 	order := entity.Order{
-		UUID:      guuid.NewV4(),
-		Name:      "Cheeze Pizza",
-		Temp:      entity.OrderTempHot,
-		ShelfLife: 300,
-		DecayRate: 0.45,
+		UUID:        guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		Name:        "Ice Cream",
+		Temp:        entity.OrderTempFrozen,
+		ShelfLife:   300,
+		DecayRate:   0.45,
+		PrepTime:    7,
 	}
-	shelfType := order.GetShelfType()
 
-	shelfOrderRepository.EXPECT().CountOrdersOnShelf(shelfType).Return(0, exception.ErrDatabase)
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.orderEventRepository.EXPECT().CreateOrderEvent(&orderEventMatcher{entity.OrderEvent{
+			OrderUUID:  order.UUID,
+			FromStatus: entity.OrderStatusQueued,
+			ToStatus:   entity.OrderStatusCooking,
+		}}).Return(nil),
+	)
+
+	prepTime, err := orderService.StartCooking(order)
+	assert.Nil(t, err)
+	assert.Equal(t, 7*time.Second, prepTime)
+}
+
+func TestStartCooking_CancelledOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+
+	// Orders cancelled while waiting on the order queue are never cooked.
+	s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(&entity.ShelfOrder{
+		OrderUUID:   order.UUID,
+		OrderStatus: entity.OrderStatusCancelled,
+	}, nil)
+
+	_, err := orderService.StartCooking(order)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+}
+
+func TestStartCooking_AlreadyCooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+
+	// The order was redelivered after its worker stopped mid-cook, it is cooked again.
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.orderEventRepository.EXPECT().CreateOrderEvent(gomock.Any()).Return(
+			errors.Wrap(exception.ErrInvalidResourceState, "order already entered status cooking")),
+	)
+
+	prepTime, err := orderService.StartCooking(order)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(s.cfg.Cooking.PrepTimes["hot"])*time.Second, prepTime)
+}
+
+func TestPlaceOrderOnShelf_CountOrdersOnShelfError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+	shelfType := entity.HotShelf
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, shelfType).Return(0, exception.ErrDatabase),
+	)
 
 	err := orderService.PlaceOrderOnShelf(order)
 	assert.Equal(t, exception.ErrDatabase, errors.Cause(err))
@@ -142,52 +213,505 @@ func TestPlaceOrderOnShelf_ShelfSpaceIsFull(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Load app config.
-	cfg := config.AppConfig{}
-	cfg.LoadConfig("../config/development.yaml")
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
 
-	orderRepository := repository.NewMockOrderRepository(ctrl)
-	shelfOrderRepository := repository.NewMockShelfOrderRepository(ctrl)
-	orderService := NewOrderService(cfg, orderRepository, shelfOrderRepository)
+	order := newTestOrder()
+	shelfType := entity.HotShelf
+
+	shelfCatalog, err := s.cfg.GetShelfCatalog()
+	assert.Nil(t, err)
+	hotShelf, _ := shelfCatalog.GetShelf(entity.HotShelf)
+	overflowShelf, _ := shelfCatalog.GetShelf(entity.OverflowShelf)
+	hotLimit := hotShelf.Capacity
+	overflowLimit := overflowShelf.Capacity
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, shelfType).Return(hotLimit, nil),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.OverflowShelf).Return(overflowLimit, nil),
+	)
+
+	err = orderService.PlaceOrderOnShelf(order)
+	assert.Equal(t, exception.ErrFullShelf, errors.Cause(err))
+}
+
+func TestPlaceOrderOnShelf_ReservedSpace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+
+	shelfCatalog, err := s.cfg.GetShelfCatalog()
+	assert.Nil(t, err)
+	hotShelf, _ := shelfCatalog.GetShelf(entity.HotShelf)
+	reservedLimit := hotShelf.Capacity - hotShelf.Reserved
+
+	// Standard orders move on to the overflow shelf once only reserved space is left.
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.HotShelf).Return(reservedLimit, nil),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.OverflowShelf).Return(0, nil),
+		s.shelfOrderRepository.EXPECT().AddOrderToShelf(&shelfOrderMatcher{entity.ShelfOrder{
+			OrderUUID:   order.UUID,
+			ShelfType:   entity.OverflowShelf,
+			OrderStatus: entity.OrderStatusReadyForPickup,
+		}}, gomock.Any()).Return(nil),
+	)
+
+	err = orderService.PlaceOrderOnShelf(order)
+	assert.Nil(t, err)
+
+	// Express orders can use the reserved space.
+	order.UUID = guuid.NewV4()
+	order.Priority = entity.OrderPriorityExpress
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.HotShelf).Return(reservedLimit, nil),
+		s.shelfOrderRepository.EXPECT().AddOrderToShelf(&shelfOrderMatcher{entity.ShelfOrder{
+			OrderUUID:   order.UUID,
+			ShelfType:   entity.HotShelf,
+			OrderStatus: entity.OrderStatusReadyForPickup,
+		}}, gomock.Any()).Return(nil),
+	)
+
+	err = orderService.PlaceOrderOnShelf(order)
+	assert.Nil(t, err)
+}
+
+func TestPlaceOrderOnShelf_EvictsLowerPriorityOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+	order.Priority = entity.OrderPriorityVIP
+
+	shelfCatalog, err := s.cfg.GetShelfCatalog()
+	assert.Nil(t, err)
+	hotShelf, _ := shelfCatalog.GetShelf(entity.HotShelf)
+	overflowShelf, _ := shelfCatalog.GetShelf(entity.OverflowShelf)
+
+	standardShelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		Priority:    entity.OrderPriorityStandard,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+	}
+
+	// Every shelf is full, so the standard order expiring soonest makes room for the vip order.
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.HotShelf).Return(hotShelf.Capacity, nil),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.OverflowShelf).Return(overflowShelf.Capacity, nil),
+		s.shelfOrderRepository.EXPECT().GetOrderToEvict(
+			entity.DefaultKitchenUUID, entity.HotShelf, entity.OrderPriorityStandard).Return(standardShelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatus(*standardShelfOrder, &orderEventMatcher{entity.OrderEvent{
+			OrderUUID:  standardShelfOrder.OrderUUID,
+			FromStatus: entity.OrderStatusReadyForPickup,
+			ToStatus:   entity.OrderStatusEvicted,
+		}}).Return(nil),
+		s.shelfOrderRepository.EXPECT().AddOrderToShelf(&shelfOrderMatcher{entity.ShelfOrder{
+			OrderUUID:   order.UUID,
+			ShelfType:   entity.HotShelf,
+			OrderStatus: entity.OrderStatusReadyForPickup,
+		}}, gomock.Any()).Return(nil),
+	)
+
+	err = orderService.PlaceOrderOnShelf(order)
+	assert.Nil(t, err)
+}
+
+func TestPlaceOrderOnShelf_NoLowerPriorityOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+	order.Priority = entity.OrderPriorityExpress
+
+	shelfCatalog, err := s.cfg.GetShelfCatalog()
+	assert.Nil(t, err)
+	hotShelf, _ := shelfCatalog.GetShelf(entity.HotShelf)
+	overflowShelf, _ := shelfCatalog.GetShelf(entity.OverflowShelf)
+
+	// Express orders only evict standard orders, never other express or vip orders.
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.HotShelf).Return(hotShelf.Capacity, nil),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.OverflowShelf).Return(overflowShelf.Capacity, nil),
+		s.shelfOrderRepository.EXPECT().GetOrderToEvict(
+			entity.DefaultKitchenUUID, entity.HotShelf, entity.OrderPriorityStandard).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().GetOrderToEvict(
+			entity.DefaultKitchenUUID, entity.OverflowShelf, entity.OrderPriorityStandard).Return(nil, exception.ErrNotFound),
+	)
+
+	err = orderService.PlaceOrderOnShelf(order)
+	assert.Equal(t, exception.ErrFullShelf, errors.Cause(err))
+}
+
+func TestPlaceOrderOnShelf_ShelfCatalog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	// The kitchen has a warm holding zone.
+	s.cfg.Shelves = []config.Shelf{
+		{Name: "warm-holding", Capacity: 5, Temps: []string{"hot", "warm"}},
+		{Name: "ambient", Capacity: 5, Temps: []string{"ambient"}},
+		{Name: "overflow", Capacity: 10, Temps: []string{"warm", "ambient"}, DecayModifier: 2, Overflow: true},
+	}
+	orderService := s.newOrderService()
 
 	order := entity.Order{
-		UUID:      guuid.NewV4(),
-		Name:      "Cheeze Pizza",
-		Temp:      entity.OrderTempHot,
-		ShelfLife: 300,
-		DecayRate: 0.45,
+		UUID:        guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		Name:        "Banh Mi",
+		Temp:        entity.OrderTemp("warm"),
+		ShelfLife:   300,
+		DecayRate:   0.5,
 	}
-	shelfType := order.GetShelfType()
 
-	hotLimit := cfg.ShelfSpace.Hot
-	overflowLimit := cfg.ShelfSpace.Overflow
+	// Warm holding is full so the order goes on the overflow shelf
+	// where it decays twice as fast: 300 / (1 + 0.5 * 2) = 150s.
+	expectedShelfOrder := entity.ShelfOrder{
+		OrderUUID:   order.UUID,
+		ShelfType:   entity.ShelfType("overflow"),
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		ExpiresAt:   time.Now().Add(150 * time.Second),
+	}
 
 	gomock.InOrder(
-		shelfOrderRepository.EXPECT().CountOrdersOnShelf(shelfType).Return(hotLimit, nil),
-		shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.OverflowShelf).Return(overflowLimit, nil),
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.ShelfType("warm-holding")).Return(5, nil),
+		s.shelfOrderRepository.EXPECT().CountOrdersOnShelf(entity.DefaultKitchenUUID, entity.ShelfType("overflow")).Return(0, nil),
+		s.shelfOrderRepository.EXPECT().AddOrderToShelf(&shelfOrderMatcher{expectedShelfOrder}, gomock.Any()).Do(
+			func(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) {
+				assert.WithinDuration(t, expectedShelfOrder.ExpiresAt, shelfOrder.ExpiresAt, time.Second)
+			}).Return(nil),
 	)
 
 	err := orderService.PlaceOrderOnShelf(order)
-	assert.Equal(t, exception.ErrFullShelf, errors.Cause(err))
+	assert.Nil(t, err)
 }
 
-// shelfOrderMatcher holds shelf order matchers.
-type shelfOrderMatcher struct {
-	ShelfOrder entity.ShelfOrder
+func TestPickupOrderByUUID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+	shelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   order.UUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		Version:     0,
+	}
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(shelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatus(*shelfOrder, &orderEventMatcher{entity.OrderEvent{ToStatus: entity.OrderStatusPickedUp}}).Return(nil),
+		s.orderRepository.EXPECT().GetOrder(order.UUID).Return(&order, nil),
+	)
+
+	pickedUpOrder, err := orderService.PickupOrderByUUID(order.UUID)
+	assert.Nil(t, err)
+	assert.Equal(t, &order, pickedUpOrder)
 }
 
-func (s *shelfOrderMatcher) String() string {
-	return "shelf order matches expected parameters"
+func TestPickupOrderByUUID_VersionInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	orderUUID := guuid.NewV4()
+	shelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   orderUUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		Version:     0,
+	}
+	// Shelf order expired between reading and updating it.
+	wastedShelfOrder := *shelfOrder
+	wastedShelfOrder.OrderStatus = entity.OrderStatusWasted
+	wastedShelfOrder.Version = 1
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(orderUUID).Return(shelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatus(*shelfOrder, &orderEventMatcher{entity.OrderEvent{ToStatus: entity.OrderStatusPickedUp}}).Return(exception.ErrVersionInvalid),
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(orderUUID).Return(&wastedShelfOrder, nil),
+	)
+
+	_, err := orderService.PickupOrderByUUID(orderUUID)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
 }
 
-func (s *shelfOrderMatcher) Matches(x interface{}) bool {
-	shelfOrder := x.(entity.ShelfOrder)
-	doesMatch := (s.ShelfOrder.OrderUUID == shelfOrder.OrderUUID &&
-		s.ShelfOrder.ShelfType == shelfOrder.ShelfType &&
-		s.ShelfOrder.OrderStatus == shelfOrder.OrderStatus &&
-		s.ShelfOrder.Version == shelfOrder.Version)
+func TestPickupOrderByUUID_InvalidResourceState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	now := time.Now()
-	isExpiresAtInFuture := shelfOrder.ExpiresAt.After(now)
-	return doesMatch && isExpiresAtInFuture
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	for _, orderStatus := range []entity.OrderStatus{entity.OrderStatusPickedUp, entity.OrderStatusWasted} {
+		orderUUID := guuid.NewV4()
+		shelfOrder := &entity.ShelfOrder{
+			UUID:        guuid.NewV4(),
+			OrderUUID:   orderUUID,
+			ShelfType:   entity.HotShelf,
+			OrderStatus: orderStatus,
+			Version:     1,
+		}
+
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(orderUUID).Return(shelfOrder, nil)
+
+		_, err := orderService.PickupOrderByUUID(orderUUID)
+		assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+	}
+
+	// Order exists but has not been placed on a shelf yet.
+	order := newTestOrder()
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.orderRepository.EXPECT().GetOrder(order.UUID).Return(&order, nil),
+	)
+
+	_, err := orderService.PickupOrderByUUID(order.UUID)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+}
+
+func TestPlaceOrderOnShelf_CancelledOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+	cancelledShelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   order.UUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusCancelled,
+	}
+
+	s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(cancelledShelfOrder, nil)
+
+	err := orderService.PlaceOrderOnShelf(order)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	// Order is on a shelf, so we take it off.
+	shelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   guuid.NewV4(),
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+	}
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(shelfOrder.OrderUUID).Return(shelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatuses([]entity.ShelfOrder{*shelfOrder}, gomock.Any()).Do(func(shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) {
+			assert.Len(t, orderEvents, 1)
+			assert.Equal(t, entity.OrderStatusCancelled, orderEvents[0].ToStatus)
+		}).Return(nil),
+	)
+
+	err := orderService.CancelOrder(shelfOrder.OrderUUID)
+	assert.Nil(t, err)
+
+	// Order has not been placed yet, so we mark it as cancelled.
+	order := newTestOrder()
+	expectedShelfOrder := entity.ShelfOrder{
+		OrderUUID:   order.UUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusCancelled,
+	}
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.orderRepository.EXPECT().GetOrder(order.UUID).Return(&order, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(order.UUID).Return(nil, nil),
+		s.shelfOrderRepository.EXPECT().AddOrderToShelfAndUpdate(gomock.Any(), gomock.Any(), nil, nil).Do(func(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent, shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) {
+			assert.Equal(t, expectedShelfOrder.OrderUUID, shelfOrder.OrderUUID)
+			assert.Equal(t, expectedShelfOrder.ShelfType, shelfOrder.ShelfType)
+			assert.Equal(t, expectedShelfOrder.OrderStatus, shelfOrder.OrderStatus)
+			assert.Equal(t, entity.OrderStatusQueued, orderEvent.FromStatus)
+			assert.Equal(t, entity.OrderStatusCancelled, orderEvent.ToStatus)
+		}).Return(nil),
+	)
+
+	err = orderService.CancelOrder(order.UUID)
+	assert.Nil(t, err)
+}
+
+func TestCancelOrder_PlacedWhileCancelling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+	shelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   order.UUID,
+		KitchenUUID: order.KitchenUUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+	}
+
+	// A worker places the order on a shelf before the cancelled shelf order is stored,
+	// so we take the order off of the shelf instead.
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(nil, exception.ErrNotFound),
+		s.orderRepository.EXPECT().GetOrder(order.UUID).Return(&order, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(order.UUID).Return(nil, nil),
+		s.shelfOrderRepository.EXPECT().AddOrderToShelfAndUpdate(gomock.Any(), gomock.Any(), nil, nil).Return(
+			errors.Wrap(exception.ErrInvalidResourceState, "order already has a shelf order")),
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(order.UUID).Return(shelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatuses([]entity.ShelfOrder{*shelfOrder}, gomock.Any()).Return(nil),
+	)
+
+	err := orderService.CancelOrder(order.UUID)
+	assert.Nil(t, err)
+}
+
+func TestCancelOrder_InvalidResourceState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	for _, orderStatus := range []entity.OrderStatus{entity.OrderStatusPickedUp, entity.OrderStatusWasted} {
+		shelfOrder := &entity.ShelfOrder{
+			UUID:        guuid.NewV4(),
+			OrderUUID:   guuid.NewV4(),
+			ShelfType:   entity.HotShelf,
+			OrderStatus: orderStatus,
+		}
+
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(shelfOrder.OrderUUID).Return(shelfOrder, nil)
+
+		err := orderService.CancelOrder(shelfOrder.OrderUUID)
+		assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+	}
+}
+
+func TestMarkOrderAsWasted_ParentOrderItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	parentOrder := newTestParentOrder()
+	pizza, yogurt := parentOrder.Items[0], parentOrder.Items[1]
+	pizzaShelfOrder := &entity.ShelfOrder{
+		UUID:            guuid.NewV4(),
+		OrderUUID:       pizza.UUID,
+		ParentOrderUUID: parentOrder.UUID,
+		ShelfType:       entity.HotShelf,
+		OrderStatus:     entity.OrderStatusReadyForPickup,
+	}
+	yogurtShelfOrder := &entity.ShelfOrder{
+		UUID:            guuid.NewV4(),
+		OrderUUID:       yogurt.UUID,
+		ParentOrderUUID: parentOrder.UUID,
+		ShelfType:       entity.ColdShelf,
+		OrderStatus:     entity.OrderStatusReadyForPickup,
+	}
+
+	// The parent order can no longer be picked up, so the yogurt leaves the shelf with the pizza.
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrdersByParentOrderUUID(parentOrder.UUID).Return(
+			[]*entity.ShelfOrder{pizzaShelfOrder, yogurtShelfOrder}, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatuses(
+			[]entity.ShelfOrder{*pizzaShelfOrder, *yogurtShelfOrder}, gomock.Any()).Do(
+			func(shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) {
+				assert.Len(t, orderEvents, 2)
+				assert.Equal(t, entity.OrderStatusWasted, orderEvents[0].ToStatus)
+				assert.Equal(t, yogurt.UUID, orderEvents[1].OrderUUID)
+				assert.Equal(t, entity.OrderStatusCancelled, orderEvents[1].ToStatus)
+			}).Return(nil),
+	)
+
+	err := orderService.MarkOrderAsWasted(*pizzaShelfOrder)
+	assert.Nil(t, err)
+}
+
+func TestPlaceOrderOnShelf_FailedParentOrderItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	parentOrder := newTestParentOrder()
+	pizza, yogurt := parentOrder.Items[0], parentOrder.Items[1]
+	wastedShelfOrder := &entity.ShelfOrder{
+		UUID:            guuid.NewV4(),
+		OrderUUID:       pizza.UUID,
+		ParentOrderUUID: parentOrder.UUID,
+		ShelfType:       entity.HotShelf,
+		OrderStatus:     entity.OrderStatusWasted,
+	}
+	itemShelfOrders := []*entity.ShelfOrder{wastedShelfOrder}
+
+	// The pizza was wasted while the yogurt was queued, so the yogurt is cancelled instead of shelved.
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(yogurt.UUID).Return(nil, exception.ErrNotFound),
+		s.shelfOrderRepository.EXPECT().GetShelfOrdersByParentOrderUUID(parentOrder.UUID).Return(itemShelfOrders, nil),
+		s.orderRepository.EXPECT().GetOrder(yogurt.UUID).Return(&yogurt, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(yogurt.UUID).Return(nil, nil),
+		s.shelfOrderRepository.EXPECT().GetShelfOrdersByParentOrderUUID(parentOrder.UUID).Return(itemShelfOrders, nil),
+		s.shelfOrderRepository.EXPECT().AddOrderToShelfAndUpdate(gomock.Any(), gomock.Any(), nil, nil).Do(
+			func(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent, shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) {
+				assert.Equal(t, yogurt.UUID, shelfOrder.OrderUUID)
+				assert.Equal(t, entity.OrderStatusCancelled, shelfOrder.OrderStatus)
+				assert.Equal(t, entity.OrderStatusCancelled, orderEvent.ToStatus)
+			}).Return(nil),
+	)
+
+	err := orderService.PlaceOrderOnShelf(yogurt)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+}
+
+func TestCreateOrder_UnknownKitchen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	orderService := s.newOrderService()
+
+	order := newTestOrder()
+	order.KitchenUUID = guuid.NewV4() // kitchen is not configured
+
+	// The order is rejected before it is stored.
+	err := orderService.CreateOrder(order)
+	assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err))
 }
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+)
+
+// maxOutboxErrorLength is the max length of an error kept on an outbox event.
+const maxOutboxErrorLength = 1024
+
+// OutboxService is outbox service interface.
+type OutboxService interface {
+	GetUnpublishedEvents() ([]*entity.OutboxEvent, error)
+	MarkEventPublished(outboxEvent entity.OutboxEvent) error
+	RecordFailedAttempt(outboxEvent entity.OutboxEvent, relayErr error) (bool, error)
+}
+
+type outboxService struct {
+	cfg              config.AppConfig
+	outboxRepository repository.OutboxRepository
+}
+
+// NewOutboxService returns a new outbox service. Order events are recorded in the outbox
+// by the repositories in the same DB transaction as the status change they describe.
+func NewOutboxService(cfg config.AppConfig, outboxRepository repository.OutboxRepository) OutboxService {
+	return &outboxService{
+		cfg:              cfg,
+		outboxRepository: outboxRepository,
+	}
+}
+
+// GetUnpublishedEvents returns the next batch of events to relay, oldest first.
+func (o *outboxService) GetUnpublishedEvents() ([]*entity.OutboxEvent, error) {
+	outboxEvents, err := o.outboxRepository.GetUnpublishedEvents(o.cfg.Outbox.BatchSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch unpublished outbox events")
+	}
+
+	return outboxEvents, nil
+}
+
+// MarkEventPublished records that every sink accepted an event, it is not relayed again.
+func (o *outboxService) MarkEventPublished(outboxEvent entity.OutboxEvent) error {
+	err := o.outboxRepository.MarkEventPublished(outboxEvent, time.Now())
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark outbox event %d published", outboxEvent.Event.ID)
+	}
+
+	return nil
+}
+
+// RecordFailedAttempt records why an event could not be relayed, it is relayed again on the next poll.
+// Once an event used up its attempts it is parked instead, so it no longer holds up later events.
+// Returns whether the event was parked.
+func (o *outboxService) RecordFailedAttempt(outboxEvent entity.OutboxEvent, relayErr error) (bool, error) {
+	lastError := truncate(relayErr.Error(), maxOutboxErrorLength)
+	if outboxEvent.Attempts+1 >= o.cfg.Outbox.MaxAttempts {
+		err := o.outboxRepository.MarkEventFailed(outboxEvent, lastError, time.Now())
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to park outbox event %d", outboxEvent.Event.ID)
+		}
+
+		return true, nil
+	}
+
+	err := o.outboxRepository.RecordFailedAttempt(outboxEvent, lastError)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to record failed attempt of outbox event %d", outboxEvent.Event.ID)
+	}
+
+	return false, nil
+}
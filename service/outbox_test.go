@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kitchen-delivery/entity"
+
+	"github.com/golang/mock/gomock"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOutboxEvent(t *testing.T) {
+	orderUUID := guuid.NewV4()
+	kitchenUUID := guuid.NewV4()
+
+	// Order events without an event type are not relayed.
+	queued, err := entity.NewOrderEvent(orderUUID, entity.OrderStatusReceived, entity.OrderStatusQueued, "placed on order queue")
+	assert.Nil(t, err)
+	_, err = entity.NewOutboxEvent(kitchenUUID, "", *queued)
+	assert.NotNil(t, err)
+
+	// The dedup ID is the same however many times the status change is retried.
+	shelved, err := entity.NewOrderEvent(orderUUID, entity.OrderStatusCooking, entity.OrderStatusReadyForPickup, "placed on hot shelf")
+	assert.Nil(t, err)
+	retried, err := entity.NewOrderEvent(orderUUID, entity.OrderStatusCooking, entity.OrderStatusReadyForPickup, "placed on hot shelf")
+	assert.Nil(t, err)
+
+	outboxEvent, err := entity.NewOutboxEvent(kitchenUUID, entity.HotShelf, *shelved)
+	assert.Nil(t, err)
+	retriedOutboxEvent, err := entity.NewOutboxEvent(kitchenUUID, entity.HotShelf, *retried)
+	assert.Nil(t, err)
+
+	assert.Equal(t, outboxEvent.DedupID, retriedOutboxEvent.DedupID)
+	assert.Equal(t, entity.EventTypeOrderShelved, outboxEvent.Event.Type)
+	assert.Equal(t, kitchenUUID, outboxEvent.Event.KitchenUUID)
+	assert.Equal(t, entity.HotShelf, outboxEvent.Event.ShelfType)
+}
+
+func TestGetUnpublishedEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	outboxService := s.newOutboxService()
+
+	outboxEvents := []*entity.OutboxEvent{{DedupID: guuid.NewV4(), Event: entity.Event{ID: 1}}}
+	s.outboxRepository.EXPECT().GetUnpublishedEvents(s.cfg.Outbox.BatchSize).Return(outboxEvents, nil)
+
+	unpublishedEvents, err := outboxService.GetUnpublishedEvents()
+	assert.Nil(t, err)
+	assert.Equal(t, outboxEvents, unpublishedEvents)
+}
+
+func TestRecordFailedAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	outboxService := s.newOutboxService()
+
+	// Long errors are cut to fit the outbox table.
+	outboxEvent := entity.OutboxEvent{DedupID: guuid.NewV4(), Event: entity.Event{ID: 1}}
+	s.outboxRepository.EXPECT().RecordFailedAttempt(outboxEvent, strings.Repeat("x", maxOutboxErrorLength)).Return(nil)
+
+	parked, err := outboxService.RecordFailedAttempt(outboxEvent, errors.New(strings.Repeat("x", 2*maxOutboxErrorLength)))
+	assert.Nil(t, err)
+	assert.False(t, parked)
+
+	// Events that used up their attempts are parked.
+	outboxEvent.Attempts = s.cfg.Outbox.MaxAttempts - 1
+	s.outboxRepository.EXPECT().MarkEventFailed(outboxEvent, "sink rejected event", gomock.Any()).Return(nil)
+
+	parked, err = outboxService.RecordFailedAttempt(outboxEvent, errors.New("sink rejected event"))
+	assert.Nil(t, err)
+	assert.True(t, parked)
+}
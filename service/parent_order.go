@@ -0,0 +1,169 @@
+package service
+
+import (
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ParentOrderService is parent order service interface.
+type ParentOrderService interface {
+	CreateParentOrder(parentOrder entity.ParentOrder) error
+	GetParentOrder(parentOrderUUID guuid.UUID) (*entity.ParentOrder, error)
+	PickupParentOrder(parentOrderUUID guuid.UUID) (*entity.ParentOrder, error)
+}
+
+type parentOrderService struct {
+	cfg                   config.AppConfig
+	kitchenService        KitchenService
+	parentOrderRepository repository.ParentOrderRepository
+	shelfOrderRepository  repository.ShelfOrderRepository
+	orderEventRepository  repository.OrderEventRepository
+	eventBus              EventBus
+}
+
+// NewParentOrderService returns a new parent order service
+// handling customer orders made up of several items.
+func NewParentOrderService(cfg config.AppConfig, kitchenService KitchenService, parentOrderRepository repository.ParentOrderRepository, shelfOrderRepository repository.ShelfOrderRepository, orderEventRepository repository.OrderEventRepository, eventBus EventBus) ParentOrderService {
+	return &parentOrderService{
+		cfg:                   cfg,
+		kitchenService:        kitchenService,
+		parentOrderRepository: parentOrderRepository,
+		shelfOrderRepository:  shelfOrderRepository,
+		orderEventRepository:  orderEventRepository,
+		eventBus:              eventBus,
+	}
+}
+
+// CreateParentOrder stores a parent order and its items, every item
+// is then queued and placed on a shelf like any other order.
+func (p *parentOrderService) CreateParentOrder(parentOrder entity.ParentOrder) error {
+	err := parentOrder.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Parent orders are only accepted if their kitchen has a shelf for every item.
+	kitchen, err := p.kitchenService.GetKitchen(parentOrder.KitchenUUID)
+	if errors.Cause(err) == exception.ErrNotFound {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "parent order %s kitchen does not exist - err: %s", parentOrder.UUID.String(), err)
+	}
+	if err != nil {
+		return err
+	}
+
+	var orderEvents []entity.OrderEvent
+	for _, item := range parentOrder.Items {
+		if len(kitchen.ShelfCatalog.GetShelvesForTemp(item.Temp)) == 0 {
+			return errors.Wrapf(
+				exception.ErrInvalidInput, "no shelf at kitchen %s accepts temp %s of item %s", kitchen.Name, item.Temp, item.Name)
+		}
+
+		orderEvent, err := entity.NewOrderEvent(item.UUID, "", entity.OrderStatusReceived, "order received")
+		if err != nil {
+			return err
+		}
+		orderEvents = append(orderEvents, *orderEvent)
+	}
+
+	err = p.parentOrderRepository.CreateParentOrder(parentOrder, orderEvents)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create parent order %s", parentOrder.UUID.String())
+	}
+
+	for _, orderEvent := range orderEvents {
+		publishOrderEvent(p.eventBus, parentOrder.KitchenUUID, "", orderEvent)
+	}
+	return nil
+}
+
+// GetParentOrder returns a parent order with the status of every item.
+func (p *parentOrderService) GetParentOrder(parentOrderUUID guuid.UUID) (*entity.ParentOrder, error) {
+	parentOrder, err := p.parentOrderRepository.GetParentOrder(parentOrderUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch parent order %s", parentOrderUUID.String())
+	}
+
+	parentOrder.ItemStatuses = make(map[guuid.UUID]entity.OrderStatus)
+	for _, item := range parentOrder.Items {
+		orderEvents, err := p.orderEventRepository.GetOrderEvents(item.UUID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch order events of item %s", item.UUID.String())
+		}
+
+		// Every item has at least been received once it is stored.
+		itemStatus := entity.OrderStatusReceived
+		if len(orderEvents) > 0 {
+			itemStatus = orderEvents[len(orderEvents)-1].ToStatus
+		}
+		parentOrder.ItemStatuses[item.UUID] = itemStatus
+	}
+
+	return parentOrder, nil
+}
+
+// PickupParentOrder picks up every item of a parent order together
+// once all of them are on a shelf.
+func (p *parentOrderService) PickupParentOrder(parentOrderUUID guuid.UUID) (*entity.ParentOrder, error) {
+	var err error
+
+	// An item can be updated between reading it and updating it,
+	// ex: it expires. We re-read it so we return why the pickup failed.
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var parentOrder *entity.ParentOrder
+		parentOrder, err = p.GetParentOrder(parentOrderUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		status := parentOrder.GetStatus()
+		if status != entity.OrderStatusReadyForPickup {
+			return nil, errors.Wrapf(
+				exception.ErrInvalidResourceState, "parent order %s is %s", parentOrderUUID.String(), status)
+		}
+
+		var shelfOrders []entity.ShelfOrder
+		var orderEvents []entity.OrderEvent
+		for _, item := range parentOrder.Items {
+			var shelfOrder *entity.ShelfOrder
+			shelfOrder, err = p.shelfOrderRepository.GetShelfOrderByOrderUUID(item.UUID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to fetch shelf order of item %s", item.UUID.String())
+			}
+
+			var orderEvent *entity.OrderEvent
+			orderEvent, err = entity.NewOrderEvent(
+				item.UUID, shelfOrder.OrderStatus, entity.OrderStatusPickedUp, "picked up by driver with parent order")
+			if err != nil {
+				return nil, err
+			}
+
+			shelfOrders = append(shelfOrders, *shelfOrder)
+			orderEvents = append(orderEvents, *orderEvent)
+		}
+
+		// Every item is picked up or none of them are.
+		err = p.shelfOrderRepository.UpdateOrderStatuses(shelfOrders, orderEvents)
+		if errors.Cause(err) == exception.ErrVersionInvalid {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "failed to update status of items of parent order %s", parentOrderUUID.String())
+		}
+
+		for i, item := range parentOrder.Items {
+			parentOrder.ItemStatuses[item.UUID] = entity.OrderStatusPickedUp
+			publishOrderEvent(p.eventBus, shelfOrders[i].KitchenUUID, shelfOrders[i].ShelfType, orderEvents[i])
+		}
+
+		return parentOrder, nil
+	}
+
+	return nil, errors.Wrapf(err, "failed to pickup parent order %s", parentOrderUUID.String())
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickupParentOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	parentOrderService := s.newParentOrderService()
+
+	parentOrder := newTestParentOrder()
+	pizza, yogurt := parentOrder.Items[0], parentOrder.Items[1]
+	pizzaShelfOrder := &entity.ShelfOrder{
+		UUID:            guuid.NewV4(),
+		OrderUUID:       pizza.UUID,
+		ParentOrderUUID: parentOrder.UUID,
+		ShelfType:       entity.HotShelf,
+		OrderStatus:     entity.OrderStatusReadyForPickup,
+	}
+	yogurtShelfOrder := &entity.ShelfOrder{
+		UUID:            guuid.NewV4(),
+		OrderUUID:       yogurt.UUID,
+		ParentOrderUUID: parentOrder.UUID,
+		ShelfType:       entity.ColdShelf,
+		OrderStatus:     entity.OrderStatusReadyForPickup,
+	}
+	readyEvents := func(orderUUID guuid.UUID) []*entity.OrderEvent {
+		return []*entity.OrderEvent{{OrderUUID: orderUUID, ToStatus: entity.OrderStatusReadyForPickup}}
+	}
+
+	gomock.InOrder(
+		s.parentOrderRepository.EXPECT().GetParentOrder(parentOrder.UUID).Return(&parentOrder, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(pizza.UUID).Return(readyEvents(pizza.UUID), nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(yogurt.UUID).Return(readyEvents(yogurt.UUID), nil),
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(pizza.UUID).Return(pizzaShelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().GetShelfOrderByOrderUUID(yogurt.UUID).Return(yogurtShelfOrder, nil),
+		// Both items are picked up in one update.
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatuses(
+			[]entity.ShelfOrder{*pizzaShelfOrder, *yogurtShelfOrder}, gomock.Any()).Do(
+			func(shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) {
+				assert.Len(t, orderEvents, 2)
+				for _, orderEvent := range orderEvents {
+					assert.Equal(t, entity.OrderStatusPickedUp, orderEvent.ToStatus)
+				}
+			}).Return(nil),
+	)
+
+	pickedUpParentOrder, err := parentOrderService.PickupParentOrder(parentOrder.UUID)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.OrderStatusPickedUp, pickedUpParentOrder.GetStatus())
+}
+
+func TestPickupParentOrder_ItemNotReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	parentOrderService := s.newParentOrderService()
+
+	parentOrder := newTestParentOrder()
+	pizza, yogurt := parentOrder.Items[0], parentOrder.Items[1]
+
+	// The yogurt is still on the order queue so nothing is picked up.
+	gomock.InOrder(
+		s.parentOrderRepository.EXPECT().GetParentOrder(parentOrder.UUID).Return(&parentOrder, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(pizza.UUID).Return([]*entity.OrderEvent{
+			{OrderUUID: pizza.UUID, ToStatus: entity.OrderStatusReadyForPickup},
+		}, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(yogurt.UUID).Return([]*entity.OrderEvent{
+			{OrderUUID: yogurt.UUID, ToStatus: entity.OrderStatusQueued},
+		}, nil),
+	)
+
+	_, err := parentOrderService.PickupParentOrder(parentOrder.UUID)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+}
+
+func TestGetParentOrder_WastedItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	parentOrderService := s.newParentOrderService()
+
+	parentOrder := newTestParentOrder()
+	pizza, yogurt := parentOrder.Items[0], parentOrder.Items[1]
+
+	gomock.InOrder(
+		s.parentOrderRepository.EXPECT().GetParentOrder(parentOrder.UUID).Return(&parentOrder, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(pizza.UUID).Return([]*entity.OrderEvent{
+			{OrderUUID: pizza.UUID, ToStatus: entity.OrderStatusReadyForPickup},
+		}, nil),
+		s.orderEventRepository.EXPECT().GetOrderEvents(yogurt.UUID).Return([]*entity.OrderEvent{
+			{OrderUUID: yogurt.UUID, ToStatus: entity.OrderStatusWasted},
+		}, nil),
+	)
+
+	// Waste of a single item is reported on the parent order.
+	fetchedParentOrder, err := parentOrderService.GetParentOrder(parentOrder.UUID)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.OrderStatusWasted, fetchedParentOrder.GetStatus())
+	assert.Equal(t, []entity.Order{yogurt}, fetchedParentOrder.GetItemsWithStatus(entity.OrderStatusWasted))
+}
+
+func TestCreateParentOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	parentOrderService := s.newParentOrderService()
+
+	parentOrder := newTestParentOrder()
+
+	// Every item is received with the parent order.
+	s.parentOrderRepository.EXPECT().CreateParentOrder(parentOrder, gomock.Any()).Do(
+		func(parentOrder entity.ParentOrder, orderEvents []entity.OrderEvent) {
+			assert.Len(t, orderEvents, len(parentOrder.Items))
+			for i, orderEvent := range orderEvents {
+				assert.Equal(t, parentOrder.Items[i].UUID, orderEvent.OrderUUID)
+				assert.Equal(t, entity.OrderStatusReceived, orderEvent.ToStatus)
+			}
+		}).Return(nil)
+
+	err := parentOrderService.CreateParentOrder(parentOrder)
+	assert.Nil(t, err)
+
+	// Items must belong to the parent order.
+	parentOrder.Items[1].ParentOrderUUID = guuid.NewV4()
+	err = parentOrderService.CreateParentOrder(parentOrder)
+	assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err))
+}
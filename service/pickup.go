@@ -0,0 +1,179 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// PickupService is pickup service interface.
+type PickupService interface {
+	PickupOrder(courier entity.Courier) (*entity.Order, error)
+	GetPickupStats() ([]*entity.PickupStats, error)
+}
+
+// CourierMatcher decides which order on the shelves an arriving courier collects.
+type CourierMatcher interface {
+	MatchOrder(courier entity.Courier) (*entity.ShelfOrder, error)
+}
+
+type pickupService struct {
+	cfg                  config.AppConfig
+	strategy             entity.PickupStrategy
+	courierMatcher       CourierMatcher
+	orderRepository      repository.OrderRepository
+	shelfOrderRepository repository.ShelfOrderRepository
+	pickupRepository     repository.PickupRepository
+	eventBus             EventBus
+}
+
+// NewPickupService returns a new pickup service using
+// the courier matching strategy set in configuration.
+func NewPickupService(cfg config.AppConfig, orderRepository repository.OrderRepository, shelfOrderRepository repository.ShelfOrderRepository, pickupRepository repository.PickupRepository, eventBus EventBus) (PickupService, error) {
+	strategy := entity.PickupStrategy(cfg.Pickup.Strategy)
+	courierMatcher, err := NewCourierMatcher(strategy, shelfOrderRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pickupService{
+		cfg:                  cfg,
+		strategy:             strategy,
+		courierMatcher:       courierMatcher,
+		orderRepository:      orderRepository,
+		shelfOrderRepository: shelfOrderRepository,
+		pickupRepository:     pickupRepository,
+		eventBus:             eventBus,
+	}, nil
+}
+
+// NewCourierMatcher returns the courier matcher for a pickup strategy.
+func NewCourierMatcher(strategy entity.PickupStrategy, shelfOrderRepository repository.ShelfOrderRepository) (CourierMatcher, error) {
+	switch strategy {
+	case entity.PickupStrategyMatched:
+		return &matchedCourierMatcher{shelfOrderRepository: shelfOrderRepository}, nil
+	case entity.PickupStrategyFIFO:
+		return &fifoCourierMatcher{shelfOrderRepository: shelfOrderRepository}, nil
+	default:
+		return nil, errors.Wrapf(
+			exception.ErrInvalidInput, "pickup strategy is invalid, strategy: %s", strategy)
+	}
+}
+
+// PickupOrder hands a courier an order based on the configured strategy
+// and records how long both the food and the courier waited.
+func (p *pickupService) PickupOrder(courier entity.Courier) (*entity.Order, error) {
+	shelfOrder, err := p.courierMatcher.MatchOrder(courier)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to match courier to an order %s", courier.String())
+	}
+
+	reason := fmt.Sprintf("picked up by courier %s", courier.UUID.String())
+	orderEvent, err := entity.NewOrderEvent(
+		shelfOrder.OrderUUID, shelfOrder.OrderStatus, entity.OrderStatusPickedUp, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update shelf order status to be "picked_up".
+	err = p.shelfOrderRepository.UpdateOrderStatus(*shelfOrder, *orderEvent)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "failed to update status of shelf order %+v", shelfOrder)
+	}
+	publishOrderEvent(p.eventBus, shelfOrder.KitchenUUID, shelfOrder.ShelfType, *orderEvent)
+
+	// Fetch the corresponding order so the courier has all the details.
+	order, err := p.orderRepository.GetOrder(shelfOrder.OrderUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get order")
+	}
+
+	now := time.Now()
+	pickup := entity.Pickup{
+		UUID:            guuid.NewV4(),
+		KitchenUUID:     shelfOrder.KitchenUUID,
+		CourierUUID:     courier.UUID,
+		OrderUUID:       shelfOrder.OrderUUID,
+		ShelfOrderUUID:  shelfOrder.UUID,
+		Strategy:        p.strategy,
+		FoodWaitTime:    now.Sub(shelfOrder.CreatedAt),
+		CourierWaitTime: now.Sub(courier.ArrivedAt),
+		PickedUpAt:      now,
+	}
+
+	// The order has already left the shelf at this point so we fail open,
+	// we would rather lose a data point than the courier's order.
+	err = p.pickupRepository.CreatePickup(pickup)
+	if err != nil {
+		log.Printf("failed to record pickup %s - err: %s", pickup.String(), err.Error())
+	}
+
+	return order, nil
+}
+
+// GetPickupStats returns wait times aggregated by pickup strategy.
+func (p *pickupService) GetPickupStats() ([]*entity.PickupStats, error) {
+	pickupStats, err := p.pickupRepository.GetPickupStats()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch pickup stats")
+	}
+
+	return pickupStats, nil
+}
+
+// matchedCourierMatcher only hands a courier the order they were dispatched for.
+type matchedCourierMatcher struct {
+	shelfOrderRepository repository.ShelfOrderRepository
+}
+
+func (m *matchedCourierMatcher) MatchOrder(courier entity.Courier) (*entity.ShelfOrder, error) {
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == courier.OrderUUID {
+		return nil, errors.Wrap(
+			exception.ErrInvalidInput, "courier must be dispatched for an order")
+	}
+
+	shelfOrder, err := m.shelfOrderRepository.GetOpenOrderByOrderUUID(courier.OrderUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch dispatched order")
+	}
+
+	// A courier that names their kitchen must be at the order's kitchen.
+	if nullUUID.UUID != courier.KitchenUUID && courier.KitchenUUID != shelfOrder.KitchenUUID {
+		return nil, errors.Wrapf(
+			exception.ErrInvalidInput, "order %s is not at kitchen %s", courier.OrderUUID.String(), courier.KitchenUUID.String())
+	}
+
+	return shelfOrder, nil
+}
+
+// fifoCourierMatcher hands a courier the order that has been
+// waiting on a shelf of their kitchen the longest.
+type fifoCourierMatcher struct {
+	shelfOrderRepository repository.ShelfOrderRepository
+}
+
+func (f *fifoCourierMatcher) MatchOrder(courier entity.Courier) (*entity.ShelfOrder, error) {
+	// Couriers that do not name their kitchen are at the default kitchen.
+	kitchenUUID := courier.KitchenUUID
+	nullUUID := guuid.NullUUID{}
+	if nullUUID.UUID == kitchenUUID {
+		kitchenUUID = entity.DefaultKitchenUUID
+	}
+
+	shelfOrder, err := f.shelfOrderRepository.GetFirstOpenOrder(kitchenUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch first open order")
+	}
+
+	return shelfOrder, nil
+}
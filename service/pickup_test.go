@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPickupService_InvalidStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	s.cfg.Pickup.Strategy = "not a valid strategy"
+
+	_, err := s.newPickupService()
+	assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err))
+}
+
+func TestPickupOrder_Matched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	s.cfg.Pickup.Strategy = string(entity.PickupStrategyMatched)
+	pickupService, err := s.newPickupService()
+	assert.Nil(t, err)
+
+	order := newTestOrder()
+	shelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   order.UUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		CreatedAt:   time.Now().Add(-5 * time.Second),
+	}
+	courier := entity.Courier{
+		UUID:      guuid.NewV4(),
+		OrderUUID: order.UUID,
+		ArrivedAt: time.Now().Add(-2 * time.Second),
+	}
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetOpenOrderByOrderUUID(order.UUID).Return(shelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatus(*shelfOrder, &orderEventMatcher{entity.OrderEvent{ToStatus: entity.OrderStatusPickedUp}}).Return(nil),
+		s.orderRepository.EXPECT().GetOrder(order.UUID).Return(&order, nil),
+		s.pickupRepository.EXPECT().CreatePickup(gomock.Any()).Do(func(pickup entity.Pickup) {
+			assert.Equal(t, entity.PickupStrategyMatched, pickup.Strategy)
+			assert.Equal(t, courier.UUID, pickup.CourierUUID)
+			assert.Equal(t, shelfOrder.UUID, pickup.ShelfOrderUUID)
+			assert.True(t, pickup.FoodWaitTime >= 5*time.Second)
+			assert.True(t, pickup.CourierWaitTime >= 2*time.Second)
+		}).Return(nil),
+	)
+
+	pickedUpOrder, err := pickupService.PickupOrder(courier)
+	assert.Nil(t, err)
+	assert.Equal(t, &order, pickedUpOrder)
+}
+
+func TestPickupOrder_MatchedWithoutOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	s.cfg.Pickup.Strategy = string(entity.PickupStrategyMatched)
+	pickupService, err := s.newPickupService()
+	assert.Nil(t, err)
+
+	// Courier was not dispatched for a specific order.
+	courier := entity.Courier{
+		UUID:      guuid.NewV4(),
+		ArrivedAt: time.Now(),
+	}
+
+	_, err = pickupService.PickupOrder(courier)
+	assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err))
+}
+
+func TestPickupOrder_FIFO(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	s.cfg.Pickup.Strategy = string(entity.PickupStrategyFIFO)
+	pickupService, err := s.newPickupService()
+	assert.Nil(t, err)
+
+	order := newTestOrder()
+	shelfOrder := &entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   order.UUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		CreatedAt:   time.Now(),
+	}
+
+	// Courier was dispatched for a different order but takes the next available one.
+	courier := entity.Courier{
+		UUID:      guuid.NewV4(),
+		OrderUUID: guuid.NewV4(),
+		ArrivedAt: time.Now(),
+	}
+
+	gomock.InOrder(
+		s.shelfOrderRepository.EXPECT().GetFirstOpenOrder(entity.DefaultKitchenUUID).Return(shelfOrder, nil),
+		s.shelfOrderRepository.EXPECT().UpdateOrderStatus(*shelfOrder, &orderEventMatcher{entity.OrderEvent{ToStatus: entity.OrderStatusPickedUp}}).Return(nil),
+		s.orderRepository.EXPECT().GetOrder(order.UUID).Return(&order, nil),
+		s.pickupRepository.EXPECT().CreatePickup(gomock.Any()).Return(nil),
+	)
+
+	pickedUpOrder, err := pickupService.PickupOrder(courier)
+	assert.Nil(t, err)
+	assert.Equal(t, &order, pickedUpOrder)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// DeliveryRepository is the delivery repository interface.
+type DeliveryRepository interface {
+	CreateDelivery(delivery entity.Delivery) error
+	GetDeliveryByOrderUUID(orderUUID guuid.UUID) (*entity.Delivery, error)
+}
+
+type deliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewDeliveryRepository is a new delivery repository.
+func NewDeliveryRepository(db *gorm.DB) DeliveryRepository {
+	return &deliveryRepository{
+		db: db,
+	}
+}
+
+// CreateDelivery stores a delivery into the deliveries table.
+func (d *deliveryRepository) CreateDelivery(delivery entity.Delivery) error {
+	record := mapper.DeliveryToRecord(delivery)
+
+	// Begin DB transaction.
+	tx := d.db.Begin()
+	err := tx.Create(&record).Error
+
+	// We ensure idempotency on DB create as an order is delivered once.
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+		if mysqlErr.Number == mysqlerr.ER_DUP_ENTRY {
+			tx.Rollback()
+			return nil
+		}
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(exception.ErrDatabase, "failed to store delivery - err: %s", err)
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// GetDeliveryByOrderUUID returns the delivery of a specific order.
+func (d *deliveryRepository) GetDeliveryByOrderUUID(orderUUID guuid.UUID) (*entity.Delivery, error) {
+	var deliveryRecord record.Delivery
+
+	err := d.db.
+		Where("order_uuid = ?", orderUUID.String()).
+		First(&deliveryRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	delivery, err := mapper.RecordToDelivery(deliveryRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to delivery %+v - err: %s", deliveryRecord, err.Error())
+	}
+
+	return delivery, nil
+}
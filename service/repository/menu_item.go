@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// MenuItemRepository is the menu item repository interface.
+type MenuItemRepository interface {
+	CreateMenuItem(menuItem entity.MenuItem) error
+	GetMenuItem(menuItemUUID guuid.UUID) (*entity.MenuItem, error)
+	GetMenuItems() ([]entity.MenuItem, error)
+	UpdateMenuItem(menuItem entity.MenuItem) error
+	DeleteMenuItem(menuItemUUID guuid.UUID) error
+}
+
+type menuItemRepository struct {
+	db *gorm.DB
+}
+
+// NewMenuItemRepository is a new menu item repository.
+func NewMenuItemRepository(db *gorm.DB) MenuItemRepository {
+	return &menuItemRepository{
+		db: db,
+	}
+}
+
+// CreateMenuItem stores a menu item into the menu_items table.
+func (m *menuItemRepository) CreateMenuItem(menuItem entity.MenuItem) error {
+	record := mapper.MenuItemToRecord(menuItem)
+
+	// Begin DB transaction.
+	tx := m.db.Begin()
+	err := tx.Create(&record).Error
+
+	// We ensure idempotency on creation using menu item UUID,
+	// a different menu item with the same name is rejected.
+	if isDuplicateEntry(err) {
+		tx.Rollback()
+
+		_, err = m.GetMenuItem(menuItem.UUID)
+		if err == exception.ErrNotFound {
+			return errors.Wrapf(
+				exception.ErrInvalidInput, "menu item named %s already exists", menuItem.Name)
+		}
+		return err
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(
+			exception.ErrDatabase, "failed to store menu item, err: %s", err)
+	}
+
+	// Commit DB transaction.
+	tx.Commit()
+	return nil
+}
+
+// GetMenuItem returns a specific menu item.
+func (m *menuItemRepository) GetMenuItem(menuItemUUID guuid.UUID) (*entity.MenuItem, error) {
+	var menuItemRecord record.MenuItem
+
+	err := m.db.
+		Where("uuid = ?", menuItemUUID.String()).
+		First(&menuItemRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	menuItem, err := mapper.RecordToMenuItem(menuItemRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to menu item %+v, err: %s", menuItemRecord, err)
+	}
+
+	return menuItem, nil
+}
+
+// GetMenuItems returns every menu item ordered by name.
+func (m *menuItemRepository) GetMenuItems() ([]entity.MenuItem, error) {
+	var menuItemRecords []record.MenuItem
+
+	err := m.db.
+		Order("name asc").
+		Find(&menuItemRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	var menuItems []entity.MenuItem
+	for _, menuItemRecord := range menuItemRecords {
+		menuItem, err := mapper.RecordToMenuItem(menuItemRecord)
+		if err != nil {
+			return nil, errors.Wrapf(
+				exception.ErrDataCorrupted, "failed to map record to menu item %+v, err: %s", menuItemRecord, err)
+		}
+		menuItems = append(menuItems, *menuItem)
+	}
+
+	return menuItems, nil
+}
+
+// UpdateMenuItem replaces the name, temperature, shelf life, decay rate and prep time of a menu item.
+func (m *menuItemRepository) UpdateMenuItem(menuItem entity.MenuItem) error {
+	conditions := map[string]interface{}{
+		"name":       menuItem.Name,
+		"temp":       string(menuItem.Temp),
+		"shelf_life": menuItem.ShelfLife,
+		"decay_rate": menuItem.DecayRate,
+		"prep_time":  menuItem.PrepTime,
+	}
+
+	updateOperation := m.db.Model(&record.MenuItem{}).
+		Where("uuid = ?", menuItem.UUID.String()).
+		Updates(conditions)
+	if isDuplicateEntry(updateOperation.Error) {
+		return errors.Wrapf(
+			exception.ErrInvalidInput, "menu item named %s already exists", menuItem.Name)
+	}
+	if updateOperation.Error != nil {
+		return errors.Wrapf(
+			exception.ErrDatabase, "failed to update menu item, err: %s", updateOperation.Error)
+	}
+
+	// Nothing is updated when the menu item does not exist
+	// or when it already has the same values.
+	if updateOperation.RowsAffected == 0 {
+		_, err := m.GetMenuItem(menuItem.UUID)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMenuItem removes a menu item from the menu, orders
+// already created from the menu item are not affected.
+func (m *menuItemRepository) DeleteMenuItem(menuItemUUID guuid.UUID) error {
+	deleteOperation := m.db.
+		Where("uuid = ?", menuItemUUID.String()).
+		Delete(&record.MenuItem{})
+	if deleteOperation.Error != nil {
+		return errors.Wrapf(
+			exception.ErrDatabase, "failed to delete menu item, err: %s", deleteOperation.Error)
+	}
+
+	if deleteOperation.RowsAffected == 0 {
+		return exception.ErrNotFound
+	}
+
+	return nil
+}
+
+// isDuplicateEntry returns true if an error is a MySQL duplicate entry error.
+func isDuplicateEntry(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && mysqlErr.Number == mysqlerr.ER_DUP_ENTRY
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/kitchen-delivery/entity"
 	"github.com/kitchen-delivery/entity/exception"
 	"github.com/kitchen-delivery/mapper"
@@ -15,8 +17,9 @@ import (
 
 // OrderRepository is the order repository interface.
 type OrderRepository interface {
-	CreateOrder(order entity.Order) error
+	CreateOrder(order entity.Order, orderEvent entity.OrderEvent) error
 	GetOrder(orderUUID guuid.UUID) (*entity.Order, error)
+	GetOrdersCookingSince(cookingSince time.Time) ([]*entity.Order, error)
 }
 
 type orderRepository struct {
@@ -30,8 +33,9 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	}
 }
 
-// CreateOrder stores an order into the orders table.
-func (o *orderRepository) CreateOrder(order entity.Order) error {
+// CreateOrder stores an order into the orders table along with the order event
+// of it being received in one transaction, so the event always reaches the outbox.
+func (o *orderRepository) CreateOrder(order entity.Order, orderEvent entity.OrderEvent) error {
 	// Map order entity to order record.
 	record, err := mapper.OrderToRecord(order)
 	if err != nil {
@@ -58,6 +62,12 @@ func (o *orderRepository) CreateOrder(order entity.Order) error {
 			exception.ErrDatabase, "failed to store order, err: %s", err)
 	}
 
+	err = createOrderEvent(tx, order.KitchenUUID, "", orderEvent)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Commit DB transaction.
 	tx.Commit()
 	return nil
@@ -85,3 +95,34 @@ func (o *orderRepository) GetOrder(orderUUID guuid.UUID) (*entity.Order, error)
 
 	return order, nil
 }
+
+// GetOrdersCookingSince returns orders that started cooking before a time and are still cooking.
+// Every status after cooking stores the order's shelf order, so orders without one are still cooking.
+func (o *orderRepository) GetOrdersCookingSince(cookingSince time.Time) ([]*entity.Order, error) {
+	var orderRecords []record.Order
+
+	err := o.db.
+		Select("orders.*").
+		Joins("JOIN order_events ON order_events.order_uuid = orders.uuid").
+		Joins("LEFT JOIN shelf_orders ON shelf_orders.order_uuid = orders.uuid").
+		Where("order_events.to_status = ?", string(entity.OrderStatusCooking)).
+		Where("order_events.created_at < ?", cookingSince).
+		Where("shelf_orders.uuid IS NULL").
+		Find(&orderRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	orders := make([]*entity.Order, 0, len(orderRecords))
+	for _, orderRecord := range orderRecords {
+		order, err := mapper.RecordToOrder(orderRecord)
+		if err != nil {
+			return nil, errors.Wrapf(
+				exception.ErrDataCorrupted, "failed to map record to order %+v, err: %s", orderRecord, err)
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
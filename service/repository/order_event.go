@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// OrderEventRepository is the order event repository interface.
+type OrderEventRepository interface {
+	CreateOrderEvent(orderEvent entity.OrderEvent) error
+	GetOrderEvents(orderUUID guuid.UUID) ([]*entity.OrderEvent, error)
+}
+
+type orderEventRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderEventRepository is a new order event repository.
+func NewOrderEventRepository(db *gorm.DB) OrderEventRepository {
+	return &orderEventRepository{
+		db: db,
+	}
+}
+
+// CreateOrderEvent stores an order event into the order events table,
+// for an order that is not on a shelf.
+func (o *orderEventRepository) CreateOrderEvent(orderEvent entity.OrderEvent) error {
+	// Begin DB transaction.
+	tx := o.db.Begin()
+
+	// The event of the order event is relayed with the order's kitchen.
+	var orderRecord record.Order
+	err := tx.
+		Select("kitchen_uuid").
+		Where("uuid = ?", orderEvent.OrderUUID.String()).
+		First(&orderRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		tx.Rollback()
+		return errors.Wrapf(exception.ErrNotFound, "order %s does not exist", orderEvent.OrderUUID.String())
+	}
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	kitchenUUID, err := guuid.FromString(orderRecord.KitchenUUID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(
+			exception.ErrDataCorrupted, "order %s kitchen uuid is not valid, uuid: %s", orderEvent.OrderUUID.String(), orderRecord.KitchenUUID)
+	}
+
+	err = createOrderEvent(tx, kitchenUUID, "", orderEvent)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// GetOrderEvents returns the status history of an order from oldest to newest.
+func (o *orderEventRepository) GetOrderEvents(orderUUID guuid.UUID) ([]*entity.OrderEvent, error) {
+	var orderEventRecords []*record.OrderEvent
+
+	err := o.db.
+		Where("order_uuid = ?", orderUUID.String()).
+		Order("created_at asc").
+		Find(&orderEventRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	orderEvents, err := mapper.RecordsToOrderEvents(orderEventRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to order event - err: %s", err.Error())
+	}
+
+	return orderEvents, nil
+}
+
+// createOrderEvent stores an order event as part of a caller's DB transaction
+// so a status change, its history and its outbox event are committed together.
+// The shelf type is the shelf the order is on, empty if the order is not on a shelf.
+// An order enters each status at most once, so a second transition into a status returns
+// an ErrInvalidResourceState and the caller rolls back the rest of its transaction.
+func createOrderEvent(tx *gorm.DB, kitchenUUID guuid.UUID, shelfType entity.ShelfType, orderEvent entity.OrderEvent) error {
+	record := mapper.OrderEventToRecord(orderEvent)
+	err := tx.Create(&record).Error
+	if isDuplicateEntry(err) {
+		return errors.Wrapf(exception.ErrInvalidResourceState,
+			"order %s already entered status %s", orderEvent.OrderUUID.String(), orderEvent.ToStatus)
+	}
+
+	if err != nil {
+		return errors.Wrapf(exception.ErrDatabase, "failed to store order event - err: %s", err)
+	}
+
+	return createOutboxEvent(tx, kitchenUUID, shelfType, orderEvent)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddOrderToShelf_OrderAlreadyEnteredStatus(t *testing.T) {
+	db := newRecordingDB(t)
+	shelfOrderRepository := NewShelfOrderRepository(db)
+
+	// The order already entered ready for pickup, ex: a second worker placed it.
+	recorder.execErr = func(query string) error {
+		if strings.HasPrefix(query, "INSERT INTO `order_events`") {
+			return &mysql.MySQLError{Number: mysqlerr.ER_DUP_ENTRY, Message: "Duplicate entry"}
+		}
+		return nil
+	}
+
+	shelfOrder := entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+	orderEvent, err := entity.NewOrderEvent(
+		shelfOrder.OrderUUID, entity.OrderStatusQueued, entity.OrderStatusReadyForPickup, "placed on hot shelf")
+	assert.Nil(t, err)
+
+	err = shelfOrderRepository.AddOrderToShelf(shelfOrder, *orderEvent)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+
+	// The shelf order is rolled back and no outbox event is stored.
+	queries := recorder.getQueries()
+	assert.Equal(t, "ROLLBACK", queries[len(queries)-1].Query)
+	for _, query := range queries {
+		assert.False(t, strings.Contains(query.Query, "`outbox`"), query.Query)
+		assert.NotEqual(t, "COMMIT", query.Query)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/kitchen-delivery/entity"
 	go_uuid "github.com/satori/go.uuid"
+	time "time"
 )
 
 // MockOrderRepository is a mock of OrderRepository interface
@@ -36,15 +37,15 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 }
 
 // CreateOrder mocks base method
-func (m *MockOrderRepository) CreateOrder(order entity.Order) error {
-	ret := m.ctrl.Call(m, "CreateOrder", order)
+func (m *MockOrderRepository) CreateOrder(order entity.Order, orderEvent entity.OrderEvent) error {
+	ret := m.ctrl.Call(m, "CreateOrder", order, orderEvent)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateOrder indicates an expected call of CreateOrder
-func (mr *MockOrderRepositoryMockRecorder) CreateOrder(order interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrder), order)
+func (mr *MockOrderRepositoryMockRecorder) CreateOrder(order, orderEvent interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrder), order, orderEvent)
 }
 
 // GetOrder mocks base method
@@ -59,3 +60,16 @@ func (m *MockOrderRepository) GetOrder(orderUUID go_uuid.UUID) (*entity.Order, e
 func (mr *MockOrderRepositoryMockRecorder) GetOrder(orderUUID interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderRepository)(nil).GetOrder), orderUUID)
 }
+
+// GetOrdersCookingSince mocks base method
+func (m *MockOrderRepository) GetOrdersCookingSince(cookingSince time.Time) ([]*entity.Order, error) {
+	ret := m.ctrl.Call(m, "GetOrdersCookingSince", cookingSince)
+	ret0, _ := ret[0].([]*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrdersCookingSince indicates an expected call of GetOrdersCookingSince
+func (mr *MockOrderRepositoryMockRecorder) GetOrdersCookingSince(cookingSince interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersCookingSince", reflect.TypeOf((*MockOrderRepository)(nil).GetOrdersCookingSince), cookingSince)
+}
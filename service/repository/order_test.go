@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrdersCookingSince(t *testing.T) {
+	db := newRecordingDB(t)
+	orderRepository := NewOrderRepository(db)
+
+	cookingSince := time.Now().Add(-time.Minute)
+	orders, err := orderRepository.GetOrdersCookingSince(cookingSince)
+	assert.Nil(t, err)
+	assert.Empty(t, orders)
+
+	// Orders that left cooking have a shelf order, ex: they were placed, cancelled or dropped.
+	queries := recorder.getQueries()
+	assert.Len(t, queries, 1)
+	assert.True(t, strings.Contains(queries[0].Query, "LEFT JOIN shelf_orders"), queries[0].Query)
+	assert.True(t, strings.Contains(queries[0].Query, "shelf_orders.uuid IS NULL"), queries[0].Query)
+	assert.Contains(t, queries[0].Args, driver.Value(string(entity.OrderStatusCooking)))
+}
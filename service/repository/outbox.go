@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// OutboxRepository is the outbox repository interface.
+// Outbox events are only ever created along with the order events they describe.
+type OutboxRepository interface {
+	GetUnpublishedEvents(limit int) ([]*entity.OutboxEvent, error)
+	MarkEventPublished(outboxEvent entity.OutboxEvent, publishedAt time.Time) error
+	RecordFailedAttempt(outboxEvent entity.OutboxEvent, lastError string) error
+	MarkEventFailed(outboxEvent entity.OutboxEvent, lastError string, failedAt time.Time) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository is a new outbox repository.
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// GetUnpublishedEvents returns the oldest events that are not relayed yet, in the order they were recorded.
+// Parked events are skipped.
+func (o *outboxRepository) GetUnpublishedEvents(limit int) ([]*entity.OutboxEvent, error) {
+	var outboxEventRecords []*record.OutboxEvent
+
+	err := o.db.
+		Where("published_at IS NULL AND failed_at IS NULL").
+		Order("id asc").
+		Limit(limit).
+		Find(&outboxEventRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	outboxEvents, err := mapper.RecordsToOutboxEvents(outboxEventRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to outbox event - err: %s", err.Error())
+	}
+
+	return outboxEvents, nil
+}
+
+// MarkEventPublished records that every sink accepted an event.
+func (o *outboxRepository) MarkEventPublished(outboxEvent entity.OutboxEvent, publishedAt time.Time) error {
+	updateOperation := o.db.Model(&record.OutboxEvent{}).
+		Where("id = ?", outboxEvent.Event.ID).
+		Updates(map[string]interface{}{"published_at": publishedAt})
+	if updateOperation.Error != nil {
+		return errors.Wrapf(exception.ErrDatabase, "failed to mark outbox event published - err: %s", updateOperation.Error)
+	}
+
+	if updateOperation.RowsAffected == 0 {
+		return exception.ErrNotFound
+	}
+
+	return nil
+}
+
+// RecordFailedAttempt records why an event could not be relayed, the event is retried.
+func (o *outboxRepository) RecordFailedAttempt(outboxEvent entity.OutboxEvent, lastError string) error {
+	conditions := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+	}
+
+	updateOperation := o.db.Model(&record.OutboxEvent{}).
+		Where("id = ?", outboxEvent.Event.ID).
+		Updates(conditions)
+	if updateOperation.Error != nil {
+		return errors.Wrapf(exception.ErrDatabase, "failed to record outbox event attempt - err: %s", updateOperation.Error)
+	}
+
+	return nil
+}
+
+// MarkEventFailed parks an event that failed its last attempt, it is not relayed again.
+func (o *outboxRepository) MarkEventFailed(outboxEvent entity.OutboxEvent, lastError string, failedAt time.Time) error {
+	conditions := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+		"failed_at":  failedAt,
+	}
+
+	updateOperation := o.db.Model(&record.OutboxEvent{}).
+		Where("id = ?", outboxEvent.Event.ID).
+		Updates(conditions)
+	if updateOperation.Error != nil {
+		return errors.Wrapf(exception.ErrDatabase, "failed to mark outbox event failed - err: %s", updateOperation.Error)
+	}
+
+	if updateOperation.RowsAffected == 0 {
+		return exception.ErrNotFound
+	}
+
+	return nil
+}
+
+// createOutboxEvent records the event of an order event as part of the caller's DB transaction,
+// so the event is relayed if and only if the status change is committed.
+// Order events without an event type are not relayed.
+func createOutboxEvent(tx *gorm.DB, kitchenUUID guuid.UUID, shelfType entity.ShelfType, orderEvent entity.OrderEvent) error {
+	outboxEvent, err := entity.NewOutboxEvent(kitchenUUID, shelfType, orderEvent)
+	if err != nil {
+		return nil
+	}
+
+	record := mapper.OutboxEventToRecord(*outboxEvent)
+	err = tx.Create(&record).Error
+
+	// The event was already recorded along with its order event.
+	if isDuplicateEntry(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(exception.ErrDatabase, "failed to store outbox event - err: %s", err)
+	}
+
+	return nil
+}
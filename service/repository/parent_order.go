@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ParentOrderRepository is the parent order repository interface.
+type ParentOrderRepository interface {
+	CreateParentOrder(parentOrder entity.ParentOrder, orderEvents []entity.OrderEvent) error
+	GetParentOrder(parentOrderUUID guuid.UUID) (*entity.ParentOrder, error)
+}
+
+type parentOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewParentOrderRepository is a new parent order repository.
+func NewParentOrderRepository(db *gorm.DB) ParentOrderRepository {
+	return &parentOrderRepository{
+		db: db,
+	}
+}
+
+// CreateParentOrder stores a parent order, its items and the
+// order events of its items being received in one transaction.
+func (p *parentOrderRepository) CreateParentOrder(parentOrder entity.ParentOrder, orderEvents []entity.OrderEvent) error {
+	parentOrderRecord := mapper.ParentOrderToRecord(parentOrder)
+
+	// Begin DB transaction.
+	tx := p.db.Begin()
+	err := tx.Create(&parentOrderRecord).Error
+
+	// We ensure idempotency on creation using parent order UUID.
+	// If the same parent order already exists we rollback transaction.
+	if isDuplicateEntry(err) {
+		tx.Rollback()
+		return nil
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(
+			exception.ErrDatabase, "failed to store parent order, err: %s", err)
+	}
+
+	for _, item := range parentOrder.Items {
+		itemRecord, err := mapper.OrderToRecord(item)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrapf(
+				exception.ErrInvalidInput, "failed to map item to record, err: %s", err)
+		}
+
+		err = tx.Create(itemRecord).Error
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrapf(
+				exception.ErrDatabase, "failed to store item %s, err: %s", item.UUID.String(), err)
+		}
+	}
+
+	for _, orderEvent := range orderEvents {
+		err = createOrderEvent(tx, parentOrder.KitchenUUID, "", orderEvent)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// Commit DB transaction.
+	tx.Commit()
+	return nil
+}
+
+// GetParentOrder returns a specific parent order with its items.
+func (p *parentOrderRepository) GetParentOrder(parentOrderUUID guuid.UUID) (*entity.ParentOrder, error) {
+	var parentOrderRecord record.ParentOrder
+
+	err := p.db.
+		Where("uuid = ?", parentOrderUUID.String()).
+		First(&parentOrderRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	var itemRecords []record.Order
+	err = p.db.
+		Where("parent_order_uuid = ?", parentOrderUUID.String()).
+		Order("created_at asc").
+		Find(&itemRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	parentOrder, err := mapper.RecordToParentOrder(parentOrderRecord, itemRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to parent order %+v, err: %s", parentOrderRecord, err)
+	}
+
+	return parentOrder, nil
+}
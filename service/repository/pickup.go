@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// PickupRepository is the pickup repository interface.
+type PickupRepository interface {
+	CreatePickup(pickup entity.Pickup) error
+	GetPickupStats() ([]*entity.PickupStats, error)
+}
+
+type pickupRepository struct {
+	db *gorm.DB
+}
+
+// NewPickupRepository is a new pickup repository.
+func NewPickupRepository(db *gorm.DB) PickupRepository {
+	return &pickupRepository{
+		db: db,
+	}
+}
+
+// CreatePickup stores a pickup into the pickups table.
+func (p *pickupRepository) CreatePickup(pickup entity.Pickup) error {
+	record := mapper.PickupToRecord(pickup)
+
+	// Begin DB transaction.
+	tx := p.db.Begin()
+	err := tx.Create(&record).Error
+
+	// We ensure idempotency on DB create.
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+		if mysqlErr.Number == mysqlerr.ER_DUP_ENTRY {
+			tx.Rollback()
+			return nil
+		}
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(exception.ErrDatabase, "failed to store pickup - err: %s", err)
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// GetPickupStats returns average wait times grouped by pickup strategy.
+func (p *pickupRepository) GetPickupStats() ([]*entity.PickupStats, error) {
+	var pickupStatsRecords []*record.PickupStats
+
+	err := p.db.Model(&record.Pickup{}).
+		Select("strategy, COUNT(*) AS num_of_pickups, " +
+			"AVG(food_wait_ms) AS avg_food_wait_ms, AVG(courier_wait_ms) AS avg_courier_wait_ms").
+		Group("strategy").
+		Scan(&pickupStatsRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	return mapper.RecordsToPickupStats(pickupStatsRecords), nil
+}
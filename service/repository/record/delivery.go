@@ -0,0 +1,15 @@
+package record
+
+import "time"
+
+// Delivery is an order delivery record.
+type Delivery struct {
+	UUID            string    `gorm:"column:uuid;primary_key"`
+	OrderUUID       string    `gorm:"column:order_uuid"`       // FK on Orders
+	ShelfOrderUUID  string    `gorm:"column:shelf_order_uuid"` // FK on Shelf Orders
+	OrderAgeMs      int64     `gorm:"column:order_age_ms"`     // age of order at delivery in milliseconds
+	Value           float64   `gorm:"column:value"`
+	NormalizedValue float64   `gorm:"column:normalized_value"`
+	DeliveredAt     time.Time `gorm:"column:delivered_at"`
+	CreatedAt       time.Time `gorm:"column:created_at"`
+}
@@ -0,0 +1,15 @@
+package record
+
+import "time"
+
+// MenuItem is a menu item record.
+type MenuItem struct {
+	UUID      string    `gorm:"column:uuid;primary_key"`
+	Name      string    `gorm:"column:name"`
+	Temp      string    `gorm:"column:temp"`
+	ShelfLife int       `gorm:"column:shelf_life"`
+	DecayRate float64   `gorm:"column:decay_rate"`
+	PrepTime  int       `gorm:"column:prep_time"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
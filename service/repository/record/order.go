@@ -4,10 +4,16 @@ import "time"
 
 // Order is an order record.
 type Order struct {
-	UUID      string    `gorm:"column:uuid;primary_key"`
-	Name      string    `gorm:"column:name"`
-	Temp      string    `gorm:"column:temp"`
-	ShelfLife int       `gorm:"column:shelf_life"`
-	DecayRate float64   `gorm:"column:decay_rate"`
-	CreatedAt time.Time `gorm:"column:created_at"`
+	UUID            string     `gorm:"column:uuid;primary_key"`
+	KitchenUUID     string     `gorm:"column:kitchen_uuid"`
+	MenuItemUUID    string     `gorm:"column:menu_item_uuid"`    // empty for orders not created from the menu
+	ParentOrderUUID string     `gorm:"column:parent_order_uuid"` // empty for orders that are not line items
+	Name            string     `gorm:"column:name"`
+	Temp            string     `gorm:"column:temp"`
+	Priority        string     `gorm:"column:priority"` // "standard", "express", "vip"
+	ShelfLife       int        `gorm:"column:shelf_life"`
+	DecayRate       float64    `gorm:"column:decay_rate"`
+	PrepTime        int        `gorm:"column:prep_time"` // 0 for orders cooked for the kitchen's prep time
+	ReadyAt         *time.Time `gorm:"column:ready_at"`  // null for orders wanted as soon as possible
+	CreatedAt       time.Time  `gorm:"column:created_at"`
 }
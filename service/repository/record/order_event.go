@@ -0,0 +1,13 @@
+package record
+
+import "time"
+
+// OrderEvent is an order status transition record.
+type OrderEvent struct {
+	UUID       string    `gorm:"column:uuid;primary_key"`
+	OrderUUID  string    `gorm:"column:order_uuid"`  // FK on Orders
+	FromStatus string    `gorm:"column:from_status"` // empty for the first event of an order
+	ToStatus   string    `gorm:"column:to_status"`
+	Reason     string    `gorm:"column:reason"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
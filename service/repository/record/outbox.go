@@ -0,0 +1,27 @@
+package record
+
+import "time"
+
+// OutboxEvent is an event waiting to be relayed to the outbox sinks record.
+type OutboxEvent struct {
+	ID          uint64     `gorm:"column:id;primary_key"` // AUTO_INCREMENT, events are relayed in this order
+	DedupID     string     `gorm:"column:dedup_id"`       // uuid of the order event
+	KitchenUUID string     `gorm:"column:kitchen_uuid"`
+	OrderUUID   string     `gorm:"column:order_uuid"` // FK on Orders
+	EventType   string     `gorm:"column:event_type"`
+	ShelfType   string     `gorm:"column:shelf_type"` // empty if the order was never shelved
+	FromStatus  string     `gorm:"column:from_status"`
+	ToStatus    string     `gorm:"column:to_status"`
+	Reason      string     `gorm:"column:reason"`
+	OccurredAt  time.Time  `gorm:"column:occurred_at"`
+	Attempts    int        `gorm:"column:attempts"`
+	LastError   string     `gorm:"column:last_error"`
+	PublishedAt *time.Time `gorm:"column:published_at"` // NULL until the event is relayed
+	FailedAt    *time.Time `gorm:"column:failed_at"`    // NULL unless the event is parked after too many failed attempts
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+}
+
+// TableName returns the name of the outbox table.
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
@@ -0,0 +1,10 @@
+package record
+
+import "time"
+
+// ParentOrder is a parent order record, its items are stored in the orders table.
+type ParentOrder struct {
+	UUID        string    `gorm:"column:uuid;primary_key"`
+	KitchenUUID string    `gorm:"column:kitchen_uuid"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
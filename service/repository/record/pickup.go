@@ -0,0 +1,25 @@
+package record
+
+import "time"
+
+// Pickup is a courier pickup record.
+type Pickup struct {
+	UUID           string    `gorm:"column:uuid;primary_key"`
+	KitchenUUID    string    `gorm:"column:kitchen_uuid"`
+	CourierUUID    string    `gorm:"column:courier_uuid"`
+	OrderUUID      string    `gorm:"column:order_uuid"`       // FK on Orders
+	ShelfOrderUUID string    `gorm:"column:shelf_order_uuid"` // FK on Shelf Orders
+	Strategy       string    `gorm:"column:strategy"`         // "matched", "fifo"
+	FoodWaitMs     int64     `gorm:"column:food_wait_ms"`     // time order sat on a shelf in milliseconds
+	CourierWaitMs  int64     `gorm:"column:courier_wait_ms"`  // time courier waited in milliseconds
+	PickedUpAt     time.Time `gorm:"column:picked_up_at"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+// PickupStats is an aggregate of pickup records grouped by strategy.
+type PickupStats struct {
+	Strategy         string  `gorm:"column:strategy"`
+	NumOfPickups     int     `gorm:"column:num_of_pickups"`
+	AvgFoodWaitMs    float64 `gorm:"column:avg_food_wait_ms"`
+	AvgCourierWaitMs float64 `gorm:"column:avg_courier_wait_ms"`
+}
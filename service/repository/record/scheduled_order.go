@@ -0,0 +1,15 @@
+package record
+
+import "time"
+
+// ScheduledOrder is an order held off of the order queue record.
+type ScheduledOrder struct {
+	OrderUUID   string    `gorm:"column:order_uuid;primary_key"` // FK on Orders
+	KitchenUUID string    `gorm:"column:kitchen_uuid"`
+	OrderStatus string    `gorm:"column:order_status"` // "scheduled", "queued", "cancelled"
+	ReadyAt     time.Time `gorm:"column:ready_at"`
+	ReleaseAt   time.Time `gorm:"column:release_at"`
+	Version     int       `gorm:"column:version"` // Used for optimistic locking.
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
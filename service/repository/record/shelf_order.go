@@ -4,12 +4,26 @@ import "time"
 
 // ShelfOrder is an order on a shelf record.
 type ShelfOrder struct {
-	UUID        string    `gorm:"column:uuid;primary_key"`
-	OrderUUID   string    `gorm:"column:order_uuid"`   // FK on Orders
-	ShelfType   string    `gorm:"column:shelf_type"`   // "hot", "cold", "frozen", "overflow"
-	OrderStatus string    `gorm:"column:order_status"` // "ready_for_pickup", "picked_up", "wasted"
-	Version     int       `gorm:"column:version"`      // Used for optimistic locking.
-	ExpiresAt   time.Time `gorm:"column:expires_at"`   // time when order expires
-	CreatedAt   time.Time `gorm:"column:created_at"`
-	UpdatedAt   time.Time `gorm:"column:updated_at"`
+	UUID            string    `gorm:"column:uuid;primary_key"`
+	OrderUUID       string    `gorm:"column:order_uuid"`        // FK on Orders
+	KitchenUUID     string    `gorm:"column:kitchen_uuid"`      // kitchen the shelf belongs to
+	ParentOrderUUID string    `gorm:"column:parent_order_uuid"` // empty for orders that are not line items
+	Priority        string    `gorm:"column:priority"`          // "standard", "express", "vip"
+	ShelfType       string    `gorm:"column:shelf_type"`        // "hot", "cold", "frozen", "overflow"
+	OrderStatus     string    `gorm:"column:order_status"`      // "ready_for_pickup", "picked_up", "wasted", "cancelled"
+	Version         int       `gorm:"column:version"`           // Used for optimistic locking.
+	ExpiresAt       time.Time `gorm:"column:expires_at"`        // time when order expires
+	CreatedAt       time.Time `gorm:"column:created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at"`
+}
+
+// ShelvedOrder is a shelf order record read together with the record of its order.
+type ShelvedOrder struct {
+	ShelfOrder
+	Order Order `gorm:"embedded;embedded_prefix:orders_"` // columns of the order are selected as "orders_<column>"
+}
+
+// TableName returns the name of the shelf orders table orders are joined to.
+func (ShelvedOrder) TableName() string {
+	return "shelf_orders"
 }
@@ -0,0 +1,33 @@
+package record
+
+import "time"
+
+// WebhookSubscription is a partner endpoint called back on order status changes record.
+type WebhookSubscription struct {
+	UUID        string    `gorm:"column:uuid;primary_key"`
+	URL         string    `gorm:"column:url"`
+	Secret      string    `gorm:"column:secret"`
+	KitchenUUID string    `gorm:"column:kitchen_uuid"` // empty for every kitchen
+	EventTypes  string    `gorm:"column:event_types"`  // comma separated, empty for every event type
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+// WebhookDelivery is an event sent to a webhook subscription record.
+type WebhookDelivery struct {
+	UUID             string     `gorm:"column:uuid;primary_key"`
+	SubscriptionUUID string     `gorm:"column:subscription_uuid"` // FK on WebhookSubscriptions
+	EventType        string     `gorm:"column:event_type"`
+	OrderUUID        string     `gorm:"column:order_uuid"`
+	Payload          string     `gorm:"column:payload"`
+	Status           string     `gorm:"column:status"` // "pending", "delivered", "failed"
+	Attempts         int        `gorm:"column:attempts"`
+	NextAttemptAt    time.Time  `gorm:"column:next_attempt_at"`
+	LastStatusCode   int        `gorm:"column:last_status_code"`
+	LastError        string     `gorm:"column:last_error"`
+	LastAttemptAt    *time.Time `gorm:"column:last_attempt_at"`
+	DeliveredAt      *time.Time `gorm:"column:delivered_at"`
+	Version          int        `gorm:"column:version"` // Used for optimistic locking.
+	CreatedAt        time.Time  `gorm:"column:created_at"`
+	UpdatedAt        time.Time  `gorm:"column:updated_at"`
+}
@@ -6,18 +6,42 @@ import (
 
 // Repositories stores MySQL DB drivers.
 type Repositories struct {
-	Order      OrderRepository
-	ShelfOrder ShelfOrderRepository
+	Order          OrderRepository
+	ShelfOrder     ShelfOrderRepository
+	Pickup         PickupRepository
+	OrderEvent     OrderEventRepository
+	Delivery       DeliveryRepository
+	MenuItem       MenuItemRepository
+	ParentOrder    ParentOrderRepository
+	ScheduledOrder ScheduledOrderRepository
+	Webhook        WebhookRepository
+	Outbox         OutboxRepository
 }
 
 // InitializeRepositories initializes repositories.
 func InitializeRepositories(db *gorm.DB) Repositories {
 	orderRepository := NewOrderRepository(db)
 	shelfOrderRepository := NewShelfOrderRepository(db)
+	pickupRepository := NewPickupRepository(db)
+	orderEventRepository := NewOrderEventRepository(db)
+	deliveryRepository := NewDeliveryRepository(db)
+	menuItemRepository := NewMenuItemRepository(db)
+	parentOrderRepository := NewParentOrderRepository(db)
+	scheduledOrderRepository := NewScheduledOrderRepository(db)
+	webhookRepository := NewWebhookRepository(db)
+	outboxRepository := NewOutboxRepository(db)
 
 	repositories := Repositories{
-		Order:      orderRepository,
-		ShelfOrder: shelfOrderRepository,
+		Order:          orderRepository,
+		ShelfOrder:     shelfOrderRepository,
+		Pickup:         pickupRepository,
+		OrderEvent:     orderEventRepository,
+		Delivery:       deliveryRepository,
+		MenuItem:       menuItemRepository,
+		ParentOrder:    parentOrderRepository,
+		ScheduledOrder: scheduledOrderRepository,
+		Webhook:        webhookRepository,
+		Outbox:         outboxRepository,
 	}
 
 	return repositories
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedQuery is a query sent to the recording driver with its arguments.
+type recordedQuery struct {
+	Query string
+	Args  []driver.Value
+}
+
+// recordingDriver is a database driver that records every query and returns no rows unless told to,
+// so we can verify the conditions a repository queries with without a database.
+type recordingDriver struct {
+	mu           sync.Mutex
+	queries      []recordedQuery
+	execErr      func(query string) error                                     // fails matching statements, ex: a duplicate entry
+	rowsAffected int64                                                        // rows every statement affects
+	queryRows    func(query string) (columns []string, rows [][]driver.Value) // rows returned by matching queries
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+func (d *recordingDriver) record(query string, args []driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, recordedQuery{Query: query, Args: args})
+}
+
+// getQueries returns every query recorded so far.
+func (d *recordingDriver) getQueries() []recordedQuery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queries
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return &recordingTx{driver: c.driver}, nil
+}
+
+type recordingTx struct {
+	driver *recordingDriver
+}
+
+func (t *recordingTx) Commit() error {
+	t.driver.record("COMMIT", nil)
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.driver.record("ROLLBACK", nil)
+	return nil
+}
+
+type recordingStmt struct {
+	driver *recordingDriver
+	query  string
+}
+
+func (s *recordingStmt) Close() error {
+	return nil
+}
+
+func (s *recordingStmt) NumInput() int {
+	return -1
+}
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.record(s.query, args)
+	if s.driver.execErr != nil {
+		if err := s.driver.execErr(s.query); err != nil {
+			return nil, err
+		}
+	}
+
+	return driver.RowsAffected(s.driver.rowsAffected), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.record(s.query, args)
+	if s.driver.queryRows != nil {
+		columns, rows := s.driver.queryRows(s.query)
+		return &recordingRows{columns: columns, rows: rows}, nil
+	}
+
+	return &recordingRows{}, nil
+}
+
+type recordingRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string {
+	return r.columns
+}
+
+func (r *recordingRows) Close() error {
+	return nil
+}
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// recorder records the queries of every database opened with the recording driver.
+var recorder = &recordingDriver{}
+
+func init() {
+	sql.Register("recording", recorder)
+}
+
+// newRecordingDB returns a gorm database on top of the recording driver
+// with the queries and failures of previous tests cleared.
+func newRecordingDB(t *testing.T) *gorm.DB {
+	recorder.mu.Lock()
+	recorder.queries = nil
+	recorder.execErr = nil
+	recorder.queryRows = nil
+	recorder.rowsAffected = 1
+	recorder.mu.Unlock()
+
+	sqlDB, err := sql.Open("recording", "")
+	assert.Nil(t, err)
+
+	db, err := gorm.Open("mysql", sqlDB)
+	assert.Nil(t, err)
+
+	return db
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ScheduledOrderRepository is the scheduled order repository interface.
+type ScheduledOrderRepository interface {
+	CreateScheduledOrder(scheduledOrder entity.ScheduledOrder, orderEvent entity.OrderEvent) error
+	GetScheduledOrder(orderUUID guuid.UUID) (*entity.ScheduledOrder, error)
+	GetScheduledOrders(kitchenUUID guuid.UUID) ([]*entity.ScheduledOrder, error)
+	GetDueScheduledOrders(now time.Time) ([]*entity.ScheduledOrder, error)
+	UpdateScheduledOrderStatus(scheduledOrder entity.ScheduledOrder, orderEvent entity.OrderEvent) error
+	RevertScheduledOrderStatus(scheduledOrder entity.ScheduledOrder, orderStatus entity.OrderStatus) error
+}
+
+type scheduledOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledOrderRepository is a new scheduled order repository.
+func NewScheduledOrderRepository(db *gorm.DB) ScheduledOrderRepository {
+	return &scheduledOrderRepository{
+		db: db,
+	}
+}
+
+// CreateScheduledOrder holds an order off of the order queue
+// and records the order event of it being scheduled.
+func (s *scheduledOrderRepository) CreateScheduledOrder(scheduledOrder entity.ScheduledOrder, orderEvent entity.OrderEvent) error {
+	record := mapper.ScheduledOrderToRecord(scheduledOrder)
+
+	// Begin DB transaction.
+	tx := s.db.Begin()
+	err := tx.Create(&record).Error
+
+	// We ensure idempotency on DB create.
+	if isDuplicateEntry(err) {
+		tx.Rollback()
+		return nil
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(exception.ErrDatabase, "failed to schedule order - err: %s", err)
+	}
+
+	err = createOrderEvent(tx, scheduledOrder.KitchenUUID, "", orderEvent)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// GetScheduledOrder returns the schedule of an order.
+func (s *scheduledOrderRepository) GetScheduledOrder(orderUUID guuid.UUID) (*entity.ScheduledOrder, error) {
+	var scheduledOrderRecord record.ScheduledOrder
+
+	err := s.db.
+		Where("order_uuid = ?", orderUUID.String()).
+		First(&scheduledOrderRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	scheduledOrder, err := mapper.RecordToScheduledOrder(scheduledOrderRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to scheduled order %+v - err: %s", scheduledOrderRecord, err.Error())
+	}
+
+	return scheduledOrder, nil
+}
+
+// GetScheduledOrders returns the orders of a kitchen that are not released yet, soonest ready first.
+func (s *scheduledOrderRepository) GetScheduledOrders(kitchenUUID guuid.UUID) ([]*entity.ScheduledOrder, error) {
+	var scheduledOrderRecords []*record.ScheduledOrder
+
+	err := s.db.
+		Where("kitchen_uuid = ?", kitchenUUID.String()).
+		Where("order_status = ?", string(entity.OrderStatusScheduled)).
+		Order("ready_at asc").
+		Find(&scheduledOrderRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	scheduledOrders, err := mapper.RecordsToScheduledOrders(scheduledOrderRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to scheduled order - err: %s", err.Error())
+	}
+
+	return scheduledOrders, nil
+}
+
+// GetDueScheduledOrders returns orders of every kitchen that are due to be released.
+func (s *scheduledOrderRepository) GetDueScheduledOrders(now time.Time) ([]*entity.ScheduledOrder, error) {
+	var scheduledOrderRecords []*record.ScheduledOrder
+
+	err := s.db.
+		Where("order_status = ?", string(entity.OrderStatusScheduled)).
+		Where("release_at <= ?", now).
+		Order("release_at asc").
+		Find(&scheduledOrderRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	scheduledOrders, err := mapper.RecordsToScheduledOrders(scheduledOrderRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to scheduled order - err: %s", err.Error())
+	}
+
+	return scheduledOrders, nil
+}
+
+// UpdateScheduledOrderStatus updates a scheduled order's status to the status of
+// an order event and records the order event.
+func (s *scheduledOrderRepository) UpdateScheduledOrderStatus(scheduledOrder entity.ScheduledOrder, orderEvent entity.OrderEvent) error {
+	newVersion := scheduledOrder.Version + 1 // increment version number - optimistic locking
+
+	conditions := make(map[string]interface{})
+	conditions["order_status"] = string(orderEvent.ToStatus)
+	conditions["version"] = newVersion
+
+	// We start db transaction master instance.
+	tx := s.db.Begin()
+
+	updateOperation := tx.Model(&record.ScheduledOrder{}).
+		Where("order_uuid = ?", scheduledOrder.OrderUUID.String()).
+		Where("version = ?", scheduledOrder.Version).
+		Updates(conditions)
+	if updateOperation.Error != nil {
+		tx.Rollback()
+		return errors.Wrapf(exception.ErrDatabase, "failed to update scheduled order - err: %s", updateOperation.Error)
+	}
+
+	// If we do not update anything then the scheduled order was
+	// released or cancelled underneath us.
+	if updateOperation.RowsAffected == 0 {
+		tx.Rollback()
+		return exception.ErrVersionInvalid
+	}
+
+	err := createOrderEvent(tx, scheduledOrder.KitchenUUID, "", orderEvent)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// If everything is successful we commit the txn.
+	tx.Commit()
+	return nil
+}
+
+// RevertScheduledOrderStatus puts a scheduled order back in a status it was in before.
+// No order event is recorded, as an order enters each status at most once.
+func (s *scheduledOrderRepository) RevertScheduledOrderStatus(
+	scheduledOrder entity.ScheduledOrder, orderStatus entity.OrderStatus) error {
+	newVersion := scheduledOrder.Version + 1 // increment version number - optimistic locking
+
+	conditions := make(map[string]interface{})
+	conditions["order_status"] = string(orderStatus)
+	conditions["version"] = newVersion
+
+	updateOperation := s.db.Model(&record.ScheduledOrder{}).
+		Where("order_uuid = ?", scheduledOrder.OrderUUID.String()).
+		Where("version = ?", scheduledOrder.Version).
+		Updates(conditions)
+	if updateOperation.Error != nil {
+		return errors.Wrapf(exception.ErrDatabase, "failed to revert scheduled order - err: %s", updateOperation.Error)
+	}
+
+	// If we do not update anything then the scheduled order changed underneath us.
+	if updateOperation.RowsAffected == 0 {
+		return exception.ErrVersionInvalid
+	}
+
+	return nil
+}
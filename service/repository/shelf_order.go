@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/kitchen-delivery/entity"
@@ -8,19 +10,27 @@ import (
 	"github.com/kitchen-delivery/mapper"
 	"github.com/kitchen-delivery/service/repository/record"
 
-	"github.com/VividCortex/mysqlerr"
-	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
 )
 
 // ShelfOrderRepository is the shelf order repository interface.
 type ShelfOrderRepository interface {
-	AddOrderToShelf(shelfOrder entity.ShelfOrder) error
-	CountOrdersOnShelf(shelfType entity.ShelfType) (int, error)
-	UpdateOrderStatus(shelfOrder entity.ShelfOrder, orderStatus entity.OrderStatus) error
-	GetOpenOrder() (*entity.ShelfOrder, error)
+	AddOrderToShelf(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) error
+	AddOrderToShelfAndUpdate(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent, shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) error
+	CountOrdersOnShelf(kitchenUUID guuid.UUID, shelfType entity.ShelfType) (int, error)
+	CountOrdersOnShelves(kitchenUUID guuid.UUID) (map[entity.ShelfType]int, error)
+	UpdateOrderStatus(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) error
+	UpdateOrderStatuses(shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) error
+	GetOpenOrder(kitchenUUID guuid.UUID) (*entity.ShelfOrder, error)
+	GetFirstOpenOrder(kitchenUUID guuid.UUID) (*entity.ShelfOrder, error)
+	GetOpenOrderByOrderUUID(orderUUID guuid.UUID) (*entity.ShelfOrder, error)
+	GetShelfOrderByOrderUUID(orderUUID guuid.UUID) (*entity.ShelfOrder, error)
+	GetShelfOrdersByParentOrderUUID(parentOrderUUID guuid.UUID) ([]*entity.ShelfOrder, error)
 	GetExpiredOrders() ([]*entity.ShelfOrder, error)
+	GetOrderToEvict(kitchenUUID guuid.UUID, shelfType entity.ShelfType, priority entity.OrderPriority) (*entity.ShelfOrder, error)
+	GetShelvedOrders(kitchenUUID guuid.UUID, filter entity.ShelfOrderFilter) ([]*entity.ShelvedOrder, error)
 }
 
 type shelfRepository struct {
@@ -34,20 +44,42 @@ func NewShelfOrderRepository(db *gorm.DB) ShelfOrderRepository {
 	}
 }
 
-// AddOrderToShelf adds an order to a designated shelf.
-func (s *shelfRepository) AddOrderToShelf(shelfOrder entity.ShelfOrder) error {
+// AddOrderToShelf adds an order to a designated shelf
+// and records the order event of it being added.
+func (s *shelfRepository) AddOrderToShelf(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) error {
+	return s.AddOrderToShelfAndUpdate(shelfOrder, orderEvent, nil, nil)
+}
+
+// AddOrderToShelfAndUpdate adds an order to a designated shelf and updates the status of
+// every other shelf order to the status of its order event in one transaction, ex: an item
+// of a parent order is cancelled before it reaches a shelf and its siblings leave the shelf.
+// Nothing is stored if any shelf order was updated underneath us, and an ErrInvalidResourceState
+// is returned if the order already has a shelf order, ex: it was cancelled while it was placed.
+func (s *shelfRepository) AddOrderToShelfAndUpdate(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent, shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) error {
+	if len(shelfOrders) != len(orderEvents) {
+		return errors.Wrapf(exception.ErrInvalidInput,
+			"%d shelf orders do not match %d order events", len(shelfOrders), len(orderEvents))
+	}
+
+	// Stored shelf orders stay readable once their shelf is removed from the shelf catalog,
+	// only new shelf orders have to be on a shelf of the catalog.
+	err := shelfOrder.ValidateShelfType()
+	if err != nil {
+		return errors.Wrapf(exception.ErrInvalidInput, "shelf order failed validation - err: %s", err)
+	}
+
 	record := mapper.ShelfOrderToRecord(shelfOrder)
 
 	// Begin DB transaction.
 	tx := s.db.Begin()
-	err := tx.Create(&record).Error
+	err = tx.Create(&record).Error
 
-	// We ensure idempotency on DB create
-	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
-		if mysqlErr.Number == mysqlerr.ER_DUP_ENTRY {
-			tx.Rollback()
-			return nil
-		}
+	// An order has at most one shelf order, a worker placing it and a customer
+	// cancelling it at the same time can not both store one.
+	if isDuplicateEntry(err) {
+		tx.Rollback()
+		return errors.Wrapf(exception.ErrInvalidResourceState,
+			"order %s already has a shelf order", shelfOrder.OrderUUID.String())
 	}
 
 	if err != nil {
@@ -55,17 +87,38 @@ func (s *shelfRepository) AddOrderToShelf(shelfOrder entity.ShelfOrder) error {
 		return errors.Wrapf(exception.ErrDatabase, "failed to add order to shelf - err: %s", err)
 	}
 
+	// Cancelled and evicted orders are stored as shelf orders that never take up shelf space.
+	shelfType := shelfOrder.ShelfType
+	if shelfOrder.OrderStatus != entity.OrderStatusReadyForPickup {
+		shelfType = ""
+	}
+
+	err = createOrderEvent(tx, shelfOrder.KitchenUUID, shelfType, orderEvent)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range shelfOrders {
+		err = updateOrderStatus(tx, shelfOrders[i], orderEvents[i])
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	tx.Commit()
 	return nil
 }
 
-// CountOrdersOnShelf counts shelf orders.
-func (s *shelfRepository) CountOrdersOnShelf(shelfType entity.ShelfType) (int, error) {
+// CountOrdersOnShelf counts shelf orders on a kitchen's shelf.
+func (s *shelfRepository) CountOrdersOnShelf(kitchenUUID guuid.UUID, shelfType entity.ShelfType) (int, error) {
 	// Check count of orders in "hot" w/ status of ready for pick up.
 	count := 0
 
 	orderStatus := entity.OrderStatusReadyForPickup
 	err := s.db.Model(&record.ShelfOrder{}).
+		Where("kitchen_uuid = ?", kitchenUUID.String()).
 		Where("shelf_type = ?", string(shelfType)).
 		Where("order_status = ?", string(orderStatus)).
 		Count(&count).
@@ -79,21 +132,77 @@ func (s *shelfRepository) CountOrdersOnShelf(shelfType entity.ShelfType) (int, e
 	return count, nil
 }
 
-// UpdateOrderStatus updates a shelf order's status.
-func (s *shelfRepository) UpdateOrderStatus(shelfOrder entity.ShelfOrder, orderStatus entity.OrderStatus) error {
+// CountOrdersOnShelves returns how many orders are ready for pickup on every shelf of a kitchen,
+// shelves without orders are left out.
+func (s *shelfRepository) CountOrdersOnShelves(kitchenUUID guuid.UUID) (map[entity.ShelfType]int, error) {
+	var shelfCounts []struct {
+		ShelfType string
+		Count     int
+	}
+
+	err := s.db.Model(&record.ShelfOrder{}).
+		Select("shelf_type, count(*) as count").
+		Where("kitchen_uuid = ?", kitchenUUID.String()).
+		Where("order_status = ?", string(entity.OrderStatusReadyForPickup)).
+		Group("shelf_type").
+		Scan(&shelfCounts).
+		Error
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDatabase, "failed to count shelf orders - err: %s", err.Error())
+	}
+
+	counts := make(map[entity.ShelfType]int)
+	for _, shelfCount := range shelfCounts {
+		counts[entity.ShelfType(shelfCount.ShelfType)] = shelfCount.Count
+	}
+
+	return counts, nil
+}
+
+// UpdateOrderStatus updates a shelf order's status to the status of
+// an order event and records the order event.
+func (s *shelfRepository) UpdateOrderStatus(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) error {
+	return s.UpdateOrderStatuses([]entity.ShelfOrder{shelfOrder}, []entity.OrderEvent{orderEvent})
+}
+
+// UpdateOrderStatuses updates the status of every shelf order to the status of
+// its order event in one transaction, ex: items of a parent order picked up together.
+// Nothing is updated if any shelf order was updated underneath us.
+func (s *shelfRepository) UpdateOrderStatuses(shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) error {
+	if len(shelfOrders) != len(orderEvents) {
+		return errors.Wrapf(exception.ErrInvalidInput,
+			"%d shelf orders do not match %d order events", len(shelfOrders), len(orderEvents))
+	}
+
+	// We start db transaction master instance.
+	tx := s.db.Begin()
+
+	for i := range shelfOrders {
+		err := updateOrderStatus(tx, shelfOrders[i], orderEvents[i])
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// If everything is successful we commit the txn.
+	tx.Commit()
+	return nil
+}
+
+// updateOrderStatus updates a shelf order's status within a transaction.
+func updateOrderStatus(tx *gorm.DB, shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) error {
 	// We set up map of conditions to update a request with.
 	newVersion := shelfOrder.Version + 1 // increment version number - optimistic locking
 
 	conditions := make(map[string]interface{})
-	conditions["order_status"] = string(orderStatus)
+	conditions["order_status"] = string(orderEvent.ToStatus)
 	conditions["version"] = newVersion
 
 	// We map user entity to user record.
 	record := mapper.ShelfOrderToRecord(shelfOrder)
 
-	// We start db transaction master instance.
-	tx := s.db.Begin()
-
 	// We update the row that matches the set of conditions.
 	updateOperation := tx.Model(&record).
 		Where("uuid = ?", shelfOrder.UUID.String()).
@@ -101,31 +210,29 @@ func (s *shelfRepository) UpdateOrderStatus(shelfOrder entity.ShelfOrder, orderS
 		Updates(conditions)
 
 	if updateOperation.Error != nil {
-		tx.Rollback()
 		return updateOperation.Error
 	}
 
-	// If we do not update anything then we do not commit the transaction.
-	// We also do not return an error to support idempotency.
+	// If we do not update anything then the caller does not commit the transaction.
 	// If an update operation fails because of a database issue
 	// we would have caught it in the error check above.
 	if updateOperation.RowsAffected == 0 {
-		tx.Rollback()
 		return exception.ErrVersionInvalid
 	}
 
-	// If everything is successful we commit the txn.
-	tx.Commit()
-	return nil
+	return createOrderEvent(tx, shelfOrder.KitchenUUID, shelfOrder.ShelfType, orderEvent)
 }
 
-// GetOpenOrder returns an order ready for pickup w/ the most soon expiration date.
-func (s *shelfRepository) GetOpenOrder() (*entity.ShelfOrder, error) {
+// GetOpenOrder returns an order ready for pickup at a kitchen w/ the most soon expiration date.
+func (s *shelfRepository) GetOpenOrder(kitchenUUID guuid.UUID) (*entity.ShelfOrder, error) {
 	var shelfOrderRecord record.ShelfOrder
 
 	err := s.db.
+		Where("kitchen_uuid = ?", kitchenUUID.String()).
 		// Only return orders ready for pick up.
 		Where("order_status = ?", string(entity.OrderStatusReadyForPickup)).
+		// Items of a parent order are only picked up together.
+		Where("parent_order_uuid = ?", "").
 		// We want to optimize for minimizing waste.
 		Order("expires_at asc").
 		First(&shelfOrderRecord).Error
@@ -145,6 +252,200 @@ func (s *shelfRepository) GetOpenOrder() (*entity.ShelfOrder, error) {
 	return shelfOrder, nil
 }
 
+// GetFirstOpenOrder returns the order that has been ready for pickup at a kitchen the longest.
+func (s *shelfRepository) GetFirstOpenOrder(kitchenUUID guuid.UUID) (*entity.ShelfOrder, error) {
+	var shelfOrderRecord record.ShelfOrder
+
+	err := s.db.
+		Where("kitchen_uuid = ?", kitchenUUID.String()).
+		// Only return orders ready for pick up.
+		Where("order_status = ?", string(entity.OrderStatusReadyForPickup)).
+		// Items of a parent order are only picked up together.
+		Where("parent_order_uuid = ?", "").
+		// First in, first out.
+		Order("created_at asc").
+		First(&shelfOrderRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	shelfOrder, err := mapper.RecordToShelfOrder(shelfOrderRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to shelf order %+v - err: %s", shelfOrderRecord, err.Error())
+	}
+
+	return shelfOrder, nil
+}
+
+// GetOpenOrderByOrderUUID returns the shelf order of a specific order if it is ready for pickup
+// and is not an item of a parent order.
+func (s *shelfRepository) GetOpenOrderByOrderUUID(orderUUID guuid.UUID) (*entity.ShelfOrder, error) {
+	var shelfOrderRecord record.ShelfOrder
+
+	err := s.db.
+		Where("order_uuid = ?", orderUUID.String()).
+		// Only return orders ready for pick up.
+		Where("order_status = ?", string(entity.OrderStatusReadyForPickup)).
+		// Items of a parent order are only picked up together.
+		Where("parent_order_uuid = ?", "").
+		First(&shelfOrderRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	shelfOrder, err := mapper.RecordToShelfOrder(shelfOrderRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to shelf order %+v - err: %s", shelfOrderRecord, err.Error())
+	}
+
+	return shelfOrder, nil
+}
+
+// GetShelfOrderByOrderUUID returns the shelf order of a specific order regardless of status.
+func (s *shelfRepository) GetShelfOrderByOrderUUID(orderUUID guuid.UUID) (*entity.ShelfOrder, error) {
+	var shelfOrderRecord record.ShelfOrder
+
+	err := s.db.
+		Where("order_uuid = ?", orderUUID.String()).
+		Order("created_at desc").
+		First(&shelfOrderRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	shelfOrder, err := mapper.RecordToShelfOrder(shelfOrderRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to shelf order %+v - err: %s", shelfOrderRecord, err.Error())
+	}
+
+	return shelfOrder, nil
+}
+
+// GetShelfOrdersByParentOrderUUID returns the shelf orders of every item of a parent order regardless of status.
+func (s *shelfRepository) GetShelfOrdersByParentOrderUUID(parentOrderUUID guuid.UUID) ([]*entity.ShelfOrder, error) {
+	var shelfOrderRecords []*record.ShelfOrder
+
+	err := s.db.
+		Where("parent_order_uuid = ?", parentOrderUUID.String()).
+		Order("created_at asc").
+		Find(&shelfOrderRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	shelfOrders, err := mapper.RecordsToShelfOrders(shelfOrderRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to shelf order - err: %s", err.Error())
+	}
+
+	return shelfOrders, nil
+}
+
+// GetOrderToEvict returns the order of a priority on a kitchen's shelf that expires soonest
+// so making room for a higher priority order wastes as little as possible.
+func (s *shelfRepository) GetOrderToEvict(kitchenUUID guuid.UUID, shelfType entity.ShelfType, priority entity.OrderPriority) (*entity.ShelfOrder, error) {
+	var shelfOrderRecord record.ShelfOrder
+
+	err := s.db.
+		Where("kitchen_uuid = ?", kitchenUUID.String()).
+		Where("shelf_type = ?", string(shelfType)).
+		// Only orders taking up shelf space can be evicted.
+		Where("order_status = ?", string(entity.OrderStatusReadyForPickup)).
+		Where("priority = ?", string(priority)).
+		// Items of a parent order are only picked up together, evicting one would
+		// take every item off of the shelf to make room for a single order.
+		Where("parent_order_uuid = ?", "").
+		Order("expires_at asc").
+		First(&shelfOrderRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	shelfOrder, err := mapper.RecordToShelfOrder(shelfOrderRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to shelf order %+v - err: %s", shelfOrderRecord, err.Error())
+	}
+
+	return shelfOrder, nil
+}
+
+// GetShelvedOrders returns the shelf orders of a kitchen that match a filter
+// together with their orders, soonest to expire first.
+func (s *shelfRepository) GetShelvedOrders(kitchenUUID guuid.UUID, filter entity.ShelfOrderFilter) ([]*entity.ShelvedOrder, error) {
+	var shelvedOrderRecords []*record.ShelvedOrder
+
+	// Orders are read in the same query, their columns are prefixed so they do not clash with the shelf order's.
+	columns := []string{"shelf_orders.*"}
+	for _, field := range s.db.NewScope(&record.Order{}).GetModelStruct().StructFields {
+		columns = append(columns, fmt.Sprintf("orders.%s AS orders_%s", field.DBName, field.DBName))
+	}
+
+	query := s.db.
+		Select(strings.Join(columns, ", ")).
+		Joins("JOIN orders ON orders.uuid = shelf_orders.order_uuid").
+		Where("shelf_orders.kitchen_uuid = ?", kitchenUUID.String())
+	if filter.ShelfType != "" {
+		query = query.Where("shelf_orders.shelf_type = ?", string(filter.ShelfType))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		query = query.Where("shelf_orders.order_status IN (?)", statuses)
+	}
+	if len(filter.Temps) > 0 {
+		temps := make([]string, 0, len(filter.Temps))
+		for _, temp := range filter.Temps {
+			temps = append(temps, string(temp))
+		}
+		query = query.Where("orders.temp IN (?)", temps)
+	}
+
+	err := query.
+		Order("shelf_orders.expires_at asc").
+		Find(&shelvedOrderRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	var shelvedOrders []*entity.ShelvedOrder
+	for _, shelvedOrderRecord := range shelvedOrderRecords {
+		shelfOrder, err := mapper.RecordToShelfOrder(shelvedOrderRecord.ShelfOrder)
+		if err != nil {
+			return nil, errors.Wrapf(
+				exception.ErrDataCorrupted, "failed to map record to shelf order %+v - err: %s", shelvedOrderRecord.ShelfOrder, err.Error())
+		}
+
+		order, err := mapper.RecordToOrder(shelvedOrderRecord.Order)
+		if err != nil {
+			return nil, errors.Wrapf(
+				exception.ErrDataCorrupted, "failed to map record to order %+v - err: %s", shelvedOrderRecord.Order, err.Error())
+		}
+
+		shelvedOrders = append(shelvedOrders, &entity.ShelvedOrder{Order: *order, ShelfOrder: *shelfOrder})
+	}
+
+	return shelvedOrders, nil
+}
+
 // GetExpiredOrders returns orders that have expired.
 func (s *shelfRepository) GetExpiredOrders() ([]*entity.ShelfOrder, error) {
 	var shelfOrderRecords []*record.ShelfOrder
@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/kitchen-delivery/entity"
+	go_uuid "github.com/satori/go.uuid"
 )
 
 // MockShelfOrderRepository is a mock of ShelfOrderRepository interface
@@ -35,53 +36,142 @@ func (m *MockShelfOrderRepository) EXPECT() *MockShelfOrderRepositoryMockRecorde
 }
 
 // AddOrderToShelf mocks base method
-func (m *MockShelfOrderRepository) AddOrderToShelf(shelfOrder entity.ShelfOrder) error {
-	ret := m.ctrl.Call(m, "AddOrderToShelf", shelfOrder)
+func (m *MockShelfOrderRepository) AddOrderToShelf(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) error {
+	ret := m.ctrl.Call(m, "AddOrderToShelf", shelfOrder, orderEvent)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddOrderToShelf indicates an expected call of AddOrderToShelf
-func (mr *MockShelfOrderRepositoryMockRecorder) AddOrderToShelf(shelfOrder interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrderToShelf", reflect.TypeOf((*MockShelfOrderRepository)(nil).AddOrderToShelf), shelfOrder)
+func (mr *MockShelfOrderRepositoryMockRecorder) AddOrderToShelf(shelfOrder, orderEvent interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrderToShelf", reflect.TypeOf((*MockShelfOrderRepository)(nil).AddOrderToShelf), shelfOrder, orderEvent)
+}
+
+// AddOrderToShelfAndUpdate mocks base method
+func (m *MockShelfOrderRepository) AddOrderToShelfAndUpdate(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent, shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) error {
+	ret := m.ctrl.Call(m, "AddOrderToShelfAndUpdate", shelfOrder, orderEvent, shelfOrders, orderEvents)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOrderToShelfAndUpdate indicates an expected call of AddOrderToShelfAndUpdate
+func (mr *MockShelfOrderRepositoryMockRecorder) AddOrderToShelfAndUpdate(shelfOrder, orderEvent, shelfOrders, orderEvents interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrderToShelfAndUpdate", reflect.TypeOf((*MockShelfOrderRepository)(nil).AddOrderToShelfAndUpdate), shelfOrder, orderEvent, shelfOrders, orderEvents)
 }
 
 // CountOrdersOnShelf mocks base method
-func (m *MockShelfOrderRepository) CountOrdersOnShelf(shelfType entity.ShelfType) (int, error) {
-	ret := m.ctrl.Call(m, "CountOrdersOnShelf", shelfType)
+func (m *MockShelfOrderRepository) CountOrdersOnShelf(kitchenUUID go_uuid.UUID, shelfType entity.ShelfType) (int, error) {
+	ret := m.ctrl.Call(m, "CountOrdersOnShelf", kitchenUUID, shelfType)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CountOrdersOnShelf indicates an expected call of CountOrdersOnShelf
-func (mr *MockShelfOrderRepositoryMockRecorder) CountOrdersOnShelf(shelfType interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOrdersOnShelf", reflect.TypeOf((*MockShelfOrderRepository)(nil).CountOrdersOnShelf), shelfType)
+func (mr *MockShelfOrderRepositoryMockRecorder) CountOrdersOnShelf(kitchenUUID, shelfType interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOrdersOnShelf", reflect.TypeOf((*MockShelfOrderRepository)(nil).CountOrdersOnShelf), kitchenUUID, shelfType)
+}
+
+// CountOrdersOnShelves mocks base method
+func (m *MockShelfOrderRepository) CountOrdersOnShelves(kitchenUUID go_uuid.UUID) (map[entity.ShelfType]int, error) {
+	ret := m.ctrl.Call(m, "CountOrdersOnShelves", kitchenUUID)
+	ret0, _ := ret[0].(map[entity.ShelfType]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOrdersOnShelves indicates an expected call of CountOrdersOnShelves
+func (mr *MockShelfOrderRepositoryMockRecorder) CountOrdersOnShelves(kitchenUUID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOrdersOnShelves", reflect.TypeOf((*MockShelfOrderRepository)(nil).CountOrdersOnShelves), kitchenUUID)
 }
 
 // UpdateOrderStatus mocks base method
-func (m *MockShelfOrderRepository) UpdateOrderStatus(shelfOrder entity.ShelfOrder, orderStatus entity.OrderStatus) error {
-	ret := m.ctrl.Call(m, "UpdateOrderStatus", shelfOrder, orderStatus)
+func (m *MockShelfOrderRepository) UpdateOrderStatus(shelfOrder entity.ShelfOrder, orderEvent entity.OrderEvent) error {
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", shelfOrder, orderEvent)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateOrderStatus indicates an expected call of UpdateOrderStatus
-func (mr *MockShelfOrderRepositoryMockRecorder) UpdateOrderStatus(shelfOrder, orderStatus interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockShelfOrderRepository)(nil).UpdateOrderStatus), shelfOrder, orderStatus)
+func (mr *MockShelfOrderRepositoryMockRecorder) UpdateOrderStatus(shelfOrder, orderEvent interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockShelfOrderRepository)(nil).UpdateOrderStatus), shelfOrder, orderEvent)
+}
+
+// UpdateOrderStatuses mocks base method
+func (m *MockShelfOrderRepository) UpdateOrderStatuses(shelfOrders []entity.ShelfOrder, orderEvents []entity.OrderEvent) error {
+	ret := m.ctrl.Call(m, "UpdateOrderStatuses", shelfOrders, orderEvents)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderStatuses indicates an expected call of UpdateOrderStatuses
+func (mr *MockShelfOrderRepositoryMockRecorder) UpdateOrderStatuses(shelfOrders, orderEvents interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatuses", reflect.TypeOf((*MockShelfOrderRepository)(nil).UpdateOrderStatuses), shelfOrders, orderEvents)
 }
 
 // GetOpenOrder mocks base method
-func (m *MockShelfOrderRepository) GetOpenOrder() (*entity.ShelfOrder, error) {
-	ret := m.ctrl.Call(m, "GetOpenOrder")
+func (m *MockShelfOrderRepository) GetOpenOrder(kitchenUUID go_uuid.UUID) (*entity.ShelfOrder, error) {
+	ret := m.ctrl.Call(m, "GetOpenOrder", kitchenUUID)
 	ret0, _ := ret[0].(*entity.ShelfOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOpenOrder indicates an expected call of GetOpenOrder
-func (mr *MockShelfOrderRepositoryMockRecorder) GetOpenOrder() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenOrder", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetOpenOrder))
+func (mr *MockShelfOrderRepositoryMockRecorder) GetOpenOrder(kitchenUUID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenOrder", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetOpenOrder), kitchenUUID)
+}
+
+// GetFirstOpenOrder mocks base method
+func (m *MockShelfOrderRepository) GetFirstOpenOrder(kitchenUUID go_uuid.UUID) (*entity.ShelfOrder, error) {
+	ret := m.ctrl.Call(m, "GetFirstOpenOrder", kitchenUUID)
+	ret0, _ := ret[0].(*entity.ShelfOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFirstOpenOrder indicates an expected call of GetFirstOpenOrder
+func (mr *MockShelfOrderRepositoryMockRecorder) GetFirstOpenOrder(kitchenUUID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstOpenOrder", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetFirstOpenOrder), kitchenUUID)
+}
+
+// GetOpenOrderByOrderUUID mocks base method
+func (m *MockShelfOrderRepository) GetOpenOrderByOrderUUID(orderUUID go_uuid.UUID) (*entity.ShelfOrder, error) {
+	ret := m.ctrl.Call(m, "GetOpenOrderByOrderUUID", orderUUID)
+	ret0, _ := ret[0].(*entity.ShelfOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenOrderByOrderUUID indicates an expected call of GetOpenOrderByOrderUUID
+func (mr *MockShelfOrderRepositoryMockRecorder) GetOpenOrderByOrderUUID(orderUUID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenOrderByOrderUUID", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetOpenOrderByOrderUUID), orderUUID)
+}
+
+// GetShelfOrderByOrderUUID mocks base method
+func (m *MockShelfOrderRepository) GetShelfOrderByOrderUUID(orderUUID go_uuid.UUID) (*entity.ShelfOrder, error) {
+	ret := m.ctrl.Call(m, "GetShelfOrderByOrderUUID", orderUUID)
+	ret0, _ := ret[0].(*entity.ShelfOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShelfOrderByOrderUUID indicates an expected call of GetShelfOrderByOrderUUID
+func (mr *MockShelfOrderRepositoryMockRecorder) GetShelfOrderByOrderUUID(orderUUID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShelfOrderByOrderUUID", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetShelfOrderByOrderUUID), orderUUID)
+}
+
+// GetShelfOrdersByParentOrderUUID mocks base method
+func (m *MockShelfOrderRepository) GetShelfOrdersByParentOrderUUID(parentOrderUUID go_uuid.UUID) ([]*entity.ShelfOrder, error) {
+	ret := m.ctrl.Call(m, "GetShelfOrdersByParentOrderUUID", parentOrderUUID)
+	ret0, _ := ret[0].([]*entity.ShelfOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShelfOrdersByParentOrderUUID indicates an expected call of GetShelfOrdersByParentOrderUUID
+func (mr *MockShelfOrderRepositoryMockRecorder) GetShelfOrdersByParentOrderUUID(parentOrderUUID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShelfOrdersByParentOrderUUID", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetShelfOrdersByParentOrderUUID), parentOrderUUID)
 }
 
 // GetExpiredOrders mocks base method
@@ -96,3 +186,29 @@ func (m *MockShelfOrderRepository) GetExpiredOrders() ([]*entity.ShelfOrder, err
 func (mr *MockShelfOrderRepositoryMockRecorder) GetExpiredOrders() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredOrders", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetExpiredOrders))
 }
+
+// GetOrderToEvict mocks base method
+func (m *MockShelfOrderRepository) GetOrderToEvict(kitchenUUID go_uuid.UUID, shelfType entity.ShelfType, priority entity.OrderPriority) (*entity.ShelfOrder, error) {
+	ret := m.ctrl.Call(m, "GetOrderToEvict", kitchenUUID, shelfType, priority)
+	ret0, _ := ret[0].(*entity.ShelfOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderToEvict indicates an expected call of GetOrderToEvict
+func (mr *MockShelfOrderRepositoryMockRecorder) GetOrderToEvict(kitchenUUID, shelfType, priority interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderToEvict", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetOrderToEvict), kitchenUUID, shelfType, priority)
+}
+
+// GetShelvedOrders mocks base method
+func (m *MockShelfOrderRepository) GetShelvedOrders(kitchenUUID go_uuid.UUID, filter entity.ShelfOrderFilter) ([]*entity.ShelvedOrder, error) {
+	ret := m.ctrl.Call(m, "GetShelvedOrders", kitchenUUID, filter)
+	ret0, _ := ret[0].([]*entity.ShelvedOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShelvedOrders indicates an expected call of GetShelvedOrders
+func (mr *MockShelfOrderRepositoryMockRecorder) GetShelvedOrders(kitchenUUID, filter interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShelvedOrders", reflect.TypeOf((*MockShelfOrderRepository)(nil).GetShelvedOrders), kitchenUUID, filter)
+}
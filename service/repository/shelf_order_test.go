@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrderToEvict_SkipsParentOrderItems(t *testing.T) {
+	db := newRecordingDB(t)
+	shelfOrderRepository := NewShelfOrderRepository(db)
+
+	_, err := shelfOrderRepository.GetOrderToEvict(entity.DefaultKitchenUUID, entity.HotShelf, entity.OrderPriorityStandard)
+	assert.Equal(t, exception.ErrNotFound, errors.Cause(err))
+
+	// Items of a parent order are only picked up together, so they are never evicted one at a time.
+	queries := recorder.getQueries()
+	assert.Len(t, queries, 1)
+	assert.True(t, strings.Contains(queries[0].Query, "parent_order_uuid = ?"), queries[0].Query)
+	assert.Contains(t, queries[0].Args, driver.Value(""))
+}
+
+func TestAddOrderToShelf_OrderAlreadyHasShelfOrder(t *testing.T) {
+	db := newRecordingDB(t)
+	shelfOrderRepository := NewShelfOrderRepository(db)
+
+	// The order was cancelled while a worker placed it, its shelf order already exists.
+	recorder.execErr = func(query string) error {
+		if strings.HasPrefix(query, "INSERT INTO `shelf_orders`") {
+			return &mysql.MySQLError{Number: mysqlerr.ER_DUP_ENTRY, Message: "Duplicate entry"}
+		}
+		return nil
+	}
+
+	shelfOrder := entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		ShelfType:   entity.HotShelf,
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+	orderEvent, err := entity.NewOrderEvent(
+		shelfOrder.OrderUUID, entity.OrderStatusQueued, entity.OrderStatusReadyForPickup, "placed on hot shelf")
+	assert.Nil(t, err)
+
+	err = shelfOrderRepository.AddOrderToShelf(shelfOrder, *orderEvent)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+
+	// Nothing else is stored.
+	queries := recorder.getQueries()
+	assert.Equal(t, "ROLLBACK", queries[len(queries)-1].Query)
+	for _, query := range queries {
+		assert.False(t, strings.Contains(query.Query, "order_events"), query.Query)
+	}
+}
+
+func TestAddOrderToShelf_ShelfNotInCatalog(t *testing.T) {
+	db := newRecordingDB(t)
+	shelfOrderRepository := NewShelfOrderRepository(db)
+
+	shelfOrder := entity.ShelfOrder{
+		UUID:        guuid.NewV4(),
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		ShelfType:   entity.ShelfType("warm-holding"),
+		OrderStatus: entity.OrderStatusReadyForPickup,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+	orderEvent, err := entity.NewOrderEvent(
+		shelfOrder.OrderUUID, entity.OrderStatusCooking, entity.OrderStatusReadyForPickup, "placed on warm-holding shelf")
+	assert.Nil(t, err)
+
+	// New orders are only placed on shelves of the shelf catalog.
+	err = shelfOrderRepository.AddOrderToShelf(shelfOrder, *orderEvent)
+	assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err))
+	assert.Empty(t, recorder.getQueries())
+}
+
+func TestGetShelvedOrders_ReadsOrdersInOneQuery(t *testing.T) {
+	db := newRecordingDB(t)
+	shelfOrderRepository := NewShelfOrderRepository(db)
+
+	now := time.Now()
+	shelfOrderUUID := guuid.NewV4()
+	orderUUID := guuid.NewV4()
+	recorder.queryRows = func(query string) ([]string, [][]driver.Value) {
+		columns := []string{
+			"uuid", "order_uuid", "kitchen_uuid", "parent_order_uuid", "priority", "shelf_type", "order_status",
+			"version", "expires_at", "created_at", "updated_at",
+			"orders_uuid", "orders_kitchen_uuid", "orders_menu_item_uuid", "orders_parent_order_uuid", "orders_name",
+			"orders_temp", "orders_priority", "orders_shelf_life", "orders_decay_rate", "orders_prep_time",
+			"orders_ready_at", "orders_created_at",
+		}
+		row := []driver.Value{
+			shelfOrderUUID.String(), orderUUID.String(), entity.DefaultKitchenUUID.String(), "", "standard", "hot",
+			"ready_for_pickup", int64(1), now.Add(time.Minute), now, now,
+			orderUUID.String(), entity.DefaultKitchenUUID.String(), "", "", "Cheeze Pizza",
+			"hot", "standard", int64(300), 0.45, int64(0),
+			nil, now,
+		}
+		return columns, [][]driver.Value{row}
+	}
+
+	shelvedOrders, err := shelfOrderRepository.GetShelvedOrders(entity.DefaultKitchenUUID, entity.ShelfOrderFilter{})
+	assert.Nil(t, err)
+	assert.Len(t, shelvedOrders, 1)
+	assert.Equal(t, shelfOrderUUID, shelvedOrders[0].ShelfOrder.UUID)
+	assert.Equal(t, orderUUID, shelvedOrders[0].Order.UUID)
+	assert.Equal(t, "Cheeze Pizza", shelvedOrders[0].Order.Name)
+
+	// Orders are joined to their shelf orders instead of being fetched in a second query.
+	queries := recorder.getQueries()
+	assert.Len(t, queries, 1)
+	assert.True(t, strings.Contains(queries[0].Query, "orders.uuid AS orders_uuid"), queries[0].Query)
+}
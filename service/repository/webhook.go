@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository/record"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// maxWebhookDeliveries is the max number of deliveries returned in a delivery log.
+const maxWebhookDeliveries = 100
+
+// WebhookRepository is the webhook subscription and delivery repository interface.
+type WebhookRepository interface {
+	CreateSubscription(webhookSubscription entity.WebhookSubscription) error
+	GetSubscription(subscriptionUUID guuid.UUID) (*entity.WebhookSubscription, error)
+	GetSubscriptions() ([]*entity.WebhookSubscription, error)
+	DeleteSubscription(subscriptionUUID guuid.UUID) error
+	CreateDeliveries(webhookDeliveries []entity.WebhookDelivery) error
+	GetDelivery(deliveryUUID guuid.UUID) (*entity.WebhookDelivery, error)
+	GetDeliveries(subscriptionUUID guuid.UUID) ([]*entity.WebhookDelivery, error)
+	GetDueDeliveries(now time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	UpdateDelivery(webhookDelivery entity.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository is a new webhook repository.
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// CreateSubscription stores a webhook subscription into the webhook_subscriptions table.
+func (w *webhookRepository) CreateSubscription(webhookSubscription entity.WebhookSubscription) error {
+	record := mapper.WebhookSubscriptionToRecord(webhookSubscription)
+
+	// Begin DB transaction.
+	tx := w.db.Begin()
+	err := tx.Create(&record).Error
+
+	// We ensure idempotency on DB create.
+	if isDuplicateEntry(err) {
+		tx.Rollback()
+		return nil
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(exception.ErrDatabase, "failed to store webhook subscription - err: %s", err)
+	}
+
+	// Commit DB transaction.
+	tx.Commit()
+	return nil
+}
+
+// GetSubscription returns a specific webhook subscription.
+func (w *webhookRepository) GetSubscription(subscriptionUUID guuid.UUID) (*entity.WebhookSubscription, error) {
+	var webhookSubscriptionRecord record.WebhookSubscription
+
+	err := w.db.
+		Where("uuid = ?", subscriptionUUID.String()).
+		First(&webhookSubscriptionRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	webhookSubscription, err := mapper.RecordToWebhookSubscription(webhookSubscriptionRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to webhook subscription %s - err: %s",
+			webhookSubscriptionRecord.UUID, err.Error())
+	}
+
+	return webhookSubscription, nil
+}
+
+// GetSubscriptions returns every webhook subscription, oldest first.
+func (w *webhookRepository) GetSubscriptions() ([]*entity.WebhookSubscription, error) {
+	var webhookSubscriptionRecords []record.WebhookSubscription
+
+	err := w.db.
+		Order("created_at asc").
+		Find(&webhookSubscriptionRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	var webhookSubscriptions []*entity.WebhookSubscription
+	for _, webhookSubscriptionRecord := range webhookSubscriptionRecords {
+		webhookSubscription, err := mapper.RecordToWebhookSubscription(webhookSubscriptionRecord)
+		if err != nil {
+			return nil, errors.Wrapf(
+				exception.ErrDataCorrupted, "failed to map record to webhook subscription %s - err: %s",
+				webhookSubscriptionRecord.UUID, err.Error())
+		}
+		webhookSubscriptions = append(webhookSubscriptions, webhookSubscription)
+	}
+
+	return webhookSubscriptions, nil
+}
+
+// DeleteSubscription removes a webhook subscription along with its delivery log.
+func (w *webhookRepository) DeleteSubscription(subscriptionUUID guuid.UUID) error {
+	deleteOperation := w.db.
+		Where("uuid = ?", subscriptionUUID.String()).
+		Delete(&record.WebhookSubscription{})
+	if deleteOperation.Error != nil {
+		return errors.Wrapf(
+			exception.ErrDatabase, "failed to delete webhook subscription - err: %s", deleteOperation.Error)
+	}
+
+	if deleteOperation.RowsAffected == 0 {
+		return exception.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateDeliveries stores the deliveries of an event to every subscription it matches,
+// deliveries that are already stored are skipped.
+func (w *webhookRepository) CreateDeliveries(webhookDeliveries []entity.WebhookDelivery) error {
+	// Begin DB transaction.
+	tx := w.db.Begin()
+
+	for _, webhookDelivery := range webhookDeliveries {
+		record := mapper.WebhookDeliveryToRecord(webhookDelivery)
+
+		err := tx.Create(&record).Error
+
+		// We ensure idempotency on DB create as events are delivered at least once.
+		if isDuplicateEntry(err) {
+			continue
+		}
+
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrapf(exception.ErrDatabase, "failed to store webhook delivery - err: %s", err)
+		}
+	}
+
+	// Commit DB transaction.
+	tx.Commit()
+	return nil
+}
+
+// GetDelivery returns a specific webhook delivery.
+func (w *webhookRepository) GetDelivery(deliveryUUID guuid.UUID) (*entity.WebhookDelivery, error) {
+	var webhookDeliveryRecord record.WebhookDelivery
+
+	err := w.db.
+		Where("uuid = ?", deliveryUUID.String()).
+		First(&webhookDeliveryRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, exception.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	webhookDelivery, err := mapper.RecordToWebhookDelivery(webhookDeliveryRecord)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to webhook delivery %+v - err: %s", webhookDeliveryRecord, err.Error())
+	}
+
+	return webhookDelivery, nil
+}
+
+// GetDeliveries returns the delivery log of a webhook subscription, newest first.
+func (w *webhookRepository) GetDeliveries(subscriptionUUID guuid.UUID) ([]*entity.WebhookDelivery, error) {
+	var webhookDeliveryRecords []*record.WebhookDelivery
+
+	err := w.db.
+		Where("subscription_uuid = ?", subscriptionUUID.String()).
+		Order("created_at desc").
+		Limit(maxWebhookDeliveries).
+		Find(&webhookDeliveryRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	webhookDeliveries, err := mapper.RecordsToWebhookDeliveries(webhookDeliveryRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to webhook delivery - err: %s", err.Error())
+	}
+
+	return webhookDeliveries, nil
+}
+
+// GetDueDeliveries returns pending deliveries of every subscription that are due to be attempted.
+func (w *webhookRepository) GetDueDeliveries(now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	var webhookDeliveryRecords []*record.WebhookDelivery
+
+	err := w.db.
+		Where("status = ?", string(entity.WebhookDeliveryStatusPending)).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&webhookDeliveryRecords).Error
+	if err != nil {
+		return nil, errors.Wrap(exception.ErrDatabase, err.Error())
+	}
+
+	webhookDeliveries, err := mapper.RecordsToWebhookDeliveries(webhookDeliveryRecords)
+	if err != nil {
+		return nil, errors.Wrapf(
+			exception.ErrDataCorrupted, "failed to map record to webhook delivery - err: %s", err.Error())
+	}
+
+	return webhookDeliveries, nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt.
+func (w *webhookRepository) UpdateDelivery(webhookDelivery entity.WebhookDelivery) error {
+	newVersion := webhookDelivery.Version + 1 // increment version number - optimistic locking
+	record := mapper.WebhookDeliveryToRecord(webhookDelivery)
+
+	conditions := make(map[string]interface{})
+	conditions["status"] = record.Status
+	conditions["attempts"] = record.Attempts
+	conditions["next_attempt_at"] = record.NextAttemptAt
+	conditions["last_status_code"] = record.LastStatusCode
+	conditions["last_error"] = record.LastError
+	conditions["last_attempt_at"] = record.LastAttemptAt
+	conditions["delivered_at"] = record.DeliveredAt
+	conditions["version"] = newVersion
+
+	updateOperation := w.db.Model(&record).
+		Where("version = ?", webhookDelivery.Version).
+		Updates(conditions)
+	if updateOperation.Error != nil {
+		return errors.Wrapf(exception.ErrDatabase, "failed to update webhook delivery - err: %s", updateOperation.Error)
+	}
+
+	// If we do not update anything then another worker
+	// claimed the delivery underneath us.
+	if updateOperation.RowsAffected == 0 {
+		return exception.ErrVersionInvalid
+	}
+
+	return nil
+}
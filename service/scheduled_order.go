@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ScheduledOrderService is scheduled order service interface.
+type ScheduledOrderService interface {
+	ScheduleOrder(order entity.Order) (*entity.ScheduledOrder, error)
+	GetScheduledOrders(kitchenUUID guuid.UUID) ([]*entity.ScheduledOrder, error)
+	GetDueScheduledOrders() ([]*entity.ScheduledOrder, error)
+	ReleaseScheduledOrder(scheduledOrder entity.ScheduledOrder) error
+	RevertScheduledOrderRelease(scheduledOrder entity.ScheduledOrder) error
+	CancelScheduledOrder(orderUUID guuid.UUID) error
+}
+
+type scheduledOrderService struct {
+	cfg                      config.AppConfig
+	kitchenService           KitchenService
+	scheduledOrderRepository repository.ScheduledOrderRepository
+	eventBus                 EventBus
+}
+
+// NewScheduledOrderService returns a new scheduled order service
+// holding orders off of the order queue until they are due.
+func NewScheduledOrderService(cfg config.AppConfig, kitchenService KitchenService, scheduledOrderRepository repository.ScheduledOrderRepository, eventBus EventBus) ScheduledOrderService {
+	return &scheduledOrderService{
+		cfg:                      cfg,
+		kitchenService:           kitchenService,
+		scheduledOrderRepository: scheduledOrderRepository,
+		eventBus:                 eventBus,
+	}
+}
+
+// ScheduleOrder holds an order off of the order queue until it must be cooked
+// to be ready on time, so its shelf life does not start hours early.
+// Orders whose release time already passed are released right away.
+func (s *scheduledOrderService) ScheduleOrder(order entity.Order) (*entity.ScheduledOrder, error) {
+	if !order.IsScheduled() {
+		return nil, errors.Wrapf(exception.ErrInvalidInput, "order %s has no ready time", order.UUID.String())
+	}
+
+	kitchen, err := s.kitchenService.GetKitchen(order.KitchenUUID)
+	if errors.Cause(err) == exception.ErrNotFound {
+		return nil, errors.Wrapf(exception.ErrInvalidInput, "order %s kitchen does not exist - err: %s", order.UUID.String(), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	leadTime := time.Second * time.Duration(s.cfg.Scheduling.LeadTime)
+	scheduledOrder := entity.NewScheduledOrder(order, kitchen.GetPrepTime(order), leadTime)
+
+	reason := fmt.Sprintf("ready at %s", scheduledOrder.ReadyAt.Format(time.RFC3339))
+	orderEvent, err := entity.NewOrderEvent(order.UUID, entity.OrderStatusReceived, entity.OrderStatusScheduled, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scheduledOrderRepository.CreateScheduledOrder(scheduledOrder, *orderEvent)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to schedule order %s", order.UUID.String())
+	}
+
+	return &scheduledOrder, nil
+}
+
+// GetScheduledOrders returns the orders of a kitchen that are not released yet.
+func (s *scheduledOrderService) GetScheduledOrders(kitchenUUID guuid.UUID) ([]*entity.ScheduledOrder, error) {
+	scheduledOrders, err := s.scheduledOrderRepository.GetScheduledOrders(kitchenUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch scheduled orders")
+	}
+
+	return scheduledOrders, nil
+}
+
+// GetDueScheduledOrders returns the orders of every kitchen that are due to be released.
+func (s *scheduledOrderService) GetDueScheduledOrders() ([]*entity.ScheduledOrder, error) {
+	scheduledOrders, err := s.scheduledOrderRepository.GetDueScheduledOrders(time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch due scheduled orders")
+	}
+
+	return scheduledOrders, nil
+}
+
+// ReleaseScheduledOrder records that a scheduled order is released to the order queue.
+// A caller places the order on the order queue only once it is released.
+func (s *scheduledOrderService) ReleaseScheduledOrder(scheduledOrder entity.ScheduledOrder) error {
+	orderEvent, err := entity.NewOrderEvent(
+		scheduledOrder.OrderUUID, scheduledOrder.OrderStatus, entity.OrderStatusQueued, "released to order queue")
+	if err != nil {
+		return err
+	}
+
+	err = s.scheduledOrderRepository.UpdateScheduledOrderStatus(scheduledOrder, *orderEvent)
+	if err != nil {
+		return errors.Wrapf(err, "failed to release scheduled order %s", scheduledOrder.OrderUUID.String())
+	}
+
+	return nil
+}
+
+// RevertScheduledOrderRelease puts an order back on the schedule after it was released but could not
+// be placed on the order queue, so it is released again once the scheduler polls. The scheduled order
+// is the one that was released. The order event of the release is kept and not recorded again.
+func (s *scheduledOrderService) RevertScheduledOrderRelease(scheduledOrder entity.ScheduledOrder) error {
+	releasedScheduledOrder := scheduledOrder
+	releasedScheduledOrder.OrderStatus = entity.OrderStatusQueued
+	releasedScheduledOrder.Version++
+
+	err := s.scheduledOrderRepository.RevertScheduledOrderStatus(releasedScheduledOrder, scheduledOrder.OrderStatus)
+	if err != nil {
+		return errors.Wrapf(err, "failed to revert release of scheduled order %s", scheduledOrder.OrderUUID.String())
+	}
+
+	return nil
+}
+
+// CancelScheduledOrder cancels an order before it is released to the order queue.
+// Released orders return an ErrInvalidResourceState and are cancelled like any other order.
+func (s *scheduledOrderService) CancelScheduledOrder(orderUUID guuid.UUID) error {
+	var err error
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var scheduledOrder *entity.ScheduledOrder
+		scheduledOrder, err = s.scheduledOrderRepository.GetScheduledOrder(orderUUID)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch scheduled order")
+		}
+
+		// Cancellation is idempotent.
+		if scheduledOrder.OrderStatus == entity.OrderStatusCancelled {
+			return nil
+		}
+		if scheduledOrder.OrderStatus != entity.OrderStatusScheduled {
+			return errors.Wrapf(
+				exception.ErrInvalidResourceState, "order %s was already released", orderUUID.String())
+		}
+
+		var orderEvent *entity.OrderEvent
+		orderEvent, err = entity.NewOrderEvent(
+			orderUUID, scheduledOrder.OrderStatus, entity.OrderStatusCancelled, "cancelled by customer")
+		if err != nil {
+			return err
+		}
+
+		// The order may be released underneath us, so we check again.
+		err = s.scheduledOrderRepository.UpdateScheduledOrderStatus(*scheduledOrder, *orderEvent)
+		if errors.Cause(err) == exception.ErrVersionInvalid {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to cancel scheduled order %s", orderUUID.String())
+		}
+
+		publishOrderEvent(s.eventBus, scheduledOrder.KitchenUUID, "", *orderEvent)
+		return nil
+	}
+
+	return errors.Wrapf(err, "failed to cancel scheduled order %s", orderUUID.String())
+}
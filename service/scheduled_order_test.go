@@ -0,0 +1,147 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	scheduledOrderService := s.newScheduledOrderService()
+
+	order := newTestOrder()
+	order.ReadyAt = time.Now().Add(2 * time.Hour)
+
+	s.scheduledOrderRepository.EXPECT().CreateScheduledOrder(gomock.Any(), &orderEventMatcher{entity.OrderEvent{
+		OrderUUID:  order.UUID,
+		FromStatus: entity.OrderStatusReceived,
+		ToStatus:   entity.OrderStatusScheduled,
+	}}).Return(nil)
+
+	scheduledOrder, err := scheduledOrderService.ScheduleOrder(order)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.OrderStatusScheduled, scheduledOrder.OrderStatus)
+	assert.Equal(t, order.ReadyAt, scheduledOrder.ReadyAt)
+
+	// Hot orders are released ahead of their ready time by the hot prep time and the lead time.
+	leadTime := time.Duration(s.cfg.Cooking.PrepTimes["hot"]+s.cfg.Scheduling.LeadTime) * time.Second
+	assert.Equal(t, order.ReadyAt.Add(-leadTime), scheduledOrder.ReleaseAt)
+}
+
+func TestScheduleOrder_NotScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	scheduledOrderService := s.newScheduledOrderService()
+
+	order := newTestOrder()
+
+	_, err := scheduledOrderService.ScheduleOrder(order)
+	assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err))
+}
+
+func TestCancelScheduledOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	scheduledOrderService := s.newScheduledOrderService()
+
+	scheduledOrder := &entity.ScheduledOrder{
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderStatus: entity.OrderStatusScheduled,
+		ReadyAt:     time.Now().Add(2 * time.Hour),
+		ReleaseAt:   time.Now().Add(time.Hour),
+	}
+	cancelledEvent := &orderEventMatcher{entity.OrderEvent{
+		OrderUUID:  scheduledOrder.OrderUUID,
+		FromStatus: entity.OrderStatusScheduled,
+		ToStatus:   entity.OrderStatusCancelled,
+	}}
+
+	// The first attempt loses a race with the scheduler and is retried.
+	gomock.InOrder(
+		s.scheduledOrderRepository.EXPECT().GetScheduledOrder(scheduledOrder.OrderUUID).Return(scheduledOrder, nil),
+		s.scheduledOrderRepository.EXPECT().UpdateScheduledOrderStatus(*scheduledOrder, cancelledEvent).
+			Return(exception.ErrVersionInvalid),
+		s.scheduledOrderRepository.EXPECT().GetScheduledOrder(scheduledOrder.OrderUUID).Return(scheduledOrder, nil),
+		s.scheduledOrderRepository.EXPECT().UpdateScheduledOrderStatus(*scheduledOrder, cancelledEvent).Return(nil),
+	)
+
+	err := scheduledOrderService.CancelScheduledOrder(scheduledOrder.OrderUUID)
+	assert.Nil(t, err)
+}
+
+func TestCancelScheduledOrder_Released(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	scheduledOrderService := s.newScheduledOrderService()
+
+	releasedOrder := &entity.ScheduledOrder{
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderStatus: entity.OrderStatusQueued,
+	}
+	cancelledOrder := &entity.ScheduledOrder{
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderStatus: entity.OrderStatusCancelled,
+	}
+	unscheduledOrderUUID := guuid.NewV4()
+
+	s.scheduledOrderRepository.EXPECT().GetScheduledOrder(releasedOrder.OrderUUID).Return(releasedOrder, nil)
+	s.scheduledOrderRepository.EXPECT().GetScheduledOrder(cancelledOrder.OrderUUID).Return(cancelledOrder, nil)
+	s.scheduledOrderRepository.EXPECT().GetScheduledOrder(unscheduledOrderUUID).Return(nil, exception.ErrNotFound)
+
+	// Released orders are cancelled like any other order.
+	err := scheduledOrderService.CancelScheduledOrder(releasedOrder.OrderUUID)
+	assert.Equal(t, exception.ErrInvalidResourceState, errors.Cause(err))
+
+	// Cancellation is idempotent.
+	err = scheduledOrderService.CancelScheduledOrder(cancelledOrder.OrderUUID)
+	assert.Nil(t, err)
+
+	err = scheduledOrderService.CancelScheduledOrder(unscheduledOrderUUID)
+	assert.Equal(t, exception.ErrNotFound, errors.Cause(err))
+}
+
+func TestRevertScheduledOrderRelease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	scheduledOrderService := s.newScheduledOrderService()
+
+	scheduledOrder := entity.ScheduledOrder{
+		OrderUUID:   guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderStatus: entity.OrderStatusScheduled,
+		ReleaseAt:   time.Now(),
+		Version:     1,
+	}
+
+	// The order is reverted from the version its release left it at.
+	releasedScheduledOrder := scheduledOrder
+	releasedScheduledOrder.OrderStatus = entity.OrderStatusQueued
+	releasedScheduledOrder.Version = 2
+	s.scheduledOrderRepository.EXPECT().
+		RevertScheduledOrderStatus(releasedScheduledOrder, entity.OrderStatusScheduled).Return(nil)
+
+	err := scheduledOrderService.RevertScheduledOrderRelease(scheduledOrder)
+	assert.Nil(t, err)
+}
@@ -7,14 +7,64 @@ import (
 
 // Services contains service layer.
 type Services struct {
-	Order OrderService
+	Kitchen        KitchenService
+	EventBus       EventBus
+	Order          OrderService
+	Pickup         PickupService
+	Delivery       DeliveryService
+	Menu           MenuService
+	ParentOrder    ParentOrderService
+	ScheduledOrder ScheduledOrderService
+	Shelf          ShelfService
+	Driver         DriverService
+	Webhook        WebhookService
+	Outbox         OutboxService
+	Job            JobService
+	Worker         WorkerService
 }
 
 // InitializeServices initializes service layer.
-func InitializeServices(cfg config.AppConfig, repositories repository.Repositories) Services {
-	orderService := NewOrderService(cfg, repositories.Order, repositories.ShelfOrder)
+func InitializeServices(cfg config.AppConfig, repositories repository.Repositories) (Services, error) {
+	kitchenService, err := NewKitchenService(cfg)
+	if err != nil {
+		return Services{}, err
+	}
+	eventBus := NewEventBus(cfg)
+	orderService := NewOrderService(
+		cfg, kitchenService, repositories.Order, repositories.ShelfOrder, repositories.OrderEvent, eventBus)
+	pickupService, err := NewPickupService(cfg, repositories.Order, repositories.ShelfOrder, repositories.Pickup, eventBus)
+	if err != nil {
+		return Services{}, err
+	}
+	deliveryService := NewDeliveryService(cfg, kitchenService, repositories.Order, repositories.ShelfOrder, repositories.Delivery)
+	menuService := NewMenuService(cfg, repositories.MenuItem)
+	parentOrderService := NewParentOrderService(
+		cfg, kitchenService, repositories.ParentOrder, repositories.ShelfOrder, repositories.OrderEvent, eventBus)
+	scheduledOrderService := NewScheduledOrderService(cfg, kitchenService, repositories.ScheduledOrder, eventBus)
+	shelfService := NewShelfService(cfg, kitchenService, repositories.ShelfOrder)
+	driverService, err := NewDriverService(cfg)
+	if err != nil {
+		return Services{}, err
+	}
+	webhookService := NewWebhookService(cfg, repositories.Webhook)
+	outboxService := NewOutboxService(cfg, repositories.Outbox)
+	jobService := NewJobService(cfg)
+	workerService := NewWorkerService(cfg)
 
 	return Services{
-		Order: orderService,
-	}
+		Kitchen:        kitchenService,
+		EventBus:       eventBus,
+		Order:          orderService,
+		Pickup:         pickupService,
+		Delivery:       deliveryService,
+		Menu:           menuService,
+		ParentOrder:    parentOrderService,
+		ScheduledOrder: scheduledOrderService,
+		Shelf:          shelfService,
+		Driver:         driverService,
+		Webhook:        webhookService,
+		Outbox:         outboxService,
+		Job:            jobService,
+		Worker:         workerService,
+	}, nil
 }
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/golang/mock/gomock"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// testServices holds the app config and mock repositories services are built from in tests.
+type testServices struct {
+	t   *testing.T
+	cfg config.AppConfig
+
+	orderRepository          *repository.MockOrderRepository
+	shelfOrderRepository     *repository.MockShelfOrderRepository
+	orderEventRepository     *repository.MockOrderEventRepository
+	parentOrderRepository    *repository.MockParentOrderRepository
+	scheduledOrderRepository *repository.MockScheduledOrderRepository
+	pickupRepository         *repository.MockPickupRepository
+	deliveryRepository       *repository.MockDeliveryRepository
+	menuItemRepository       *repository.MockMenuItemRepository
+	webhookRepository        *repository.MockWebhookRepository
+	outboxRepository         *repository.MockOutboxRepository
+
+	kitchenService KitchenService
+	eventBus       EventBus
+}
+
+// newTestServices loads the development config and mocks every repository.
+// Tests can change cfg before they build their first service.
+func newTestServices(t *testing.T, ctrl *gomock.Controller) *testServices {
+	cfg := config.AppConfig{}
+	cfg.LoadConfig("../config/development.yaml")
+
+	return &testServices{
+		t:                        t,
+		cfg:                      cfg,
+		orderRepository:          repository.NewMockOrderRepository(ctrl),
+		shelfOrderRepository:     repository.NewMockShelfOrderRepository(ctrl),
+		orderEventRepository:     repository.NewMockOrderEventRepository(ctrl),
+		parentOrderRepository:    repository.NewMockParentOrderRepository(ctrl),
+		scheduledOrderRepository: repository.NewMockScheduledOrderRepository(ctrl),
+		pickupRepository:         repository.NewMockPickupRepository(ctrl),
+		deliveryRepository:       repository.NewMockDeliveryRepository(ctrl),
+		menuItemRepository:       repository.NewMockMenuItemRepository(ctrl),
+		webhookRepository:        repository.NewMockWebhookRepository(ctrl),
+		outboxRepository:         repository.NewMockOutboxRepository(ctrl),
+	}
+}
+
+// getKitchenService returns the kitchen service every service of the test shares.
+func (s *testServices) getKitchenService() KitchenService {
+	if s.kitchenService == nil {
+		kitchenService, err := NewKitchenService(s.cfg)
+		assert.Nil(s.t, err)
+		s.kitchenService = kitchenService
+	}
+
+	return s.kitchenService
+}
+
+// getEventBus returns the event bus every service of the test publishes to.
+func (s *testServices) getEventBus() EventBus {
+	if s.eventBus == nil {
+		s.eventBus = NewEventBus(s.cfg)
+	}
+
+	return s.eventBus
+}
+
+func (s *testServices) newOrderService() OrderService {
+	return NewOrderService(
+		s.cfg, s.getKitchenService(), s.orderRepository, s.shelfOrderRepository, s.orderEventRepository, s.getEventBus())
+}
+
+func (s *testServices) newParentOrderService() ParentOrderService {
+	return NewParentOrderService(
+		s.cfg, s.getKitchenService(), s.parentOrderRepository, s.shelfOrderRepository, s.orderEventRepository, s.getEventBus())
+}
+
+func (s *testServices) newScheduledOrderService() ScheduledOrderService {
+	return NewScheduledOrderService(s.cfg, s.getKitchenService(), s.scheduledOrderRepository, s.getEventBus())
+}
+
+func (s *testServices) newPickupService() (PickupService, error) {
+	return NewPickupService(s.cfg, s.orderRepository, s.shelfOrderRepository, s.pickupRepository, s.getEventBus())
+}
+
+func (s *testServices) newDeliveryService() DeliveryService {
+	return NewDeliveryService(
+		s.cfg, s.getKitchenService(), s.orderRepository, s.shelfOrderRepository, s.deliveryRepository)
+}
+
+func (s *testServices) newShelfService() ShelfService {
+	return NewShelfService(s.cfg, s.getKitchenService(), s.shelfOrderRepository)
+}
+
+func (s *testServices) newMenuService() MenuService {
+	return NewMenuService(s.cfg, s.menuItemRepository)
+}
+
+func (s *testServices) newWebhookService() WebhookService {
+	return NewWebhookService(s.cfg, s.webhookRepository)
+}
+
+func (s *testServices) newOutboxService() OutboxService {
+	return NewOutboxService(s.cfg, s.outboxRepository)
+}
+
+// newTestOrder returns a hot order at the default kitchen.
+func newTestOrder() entity.Order {
+	return entity.Order{
+		UUID:        guuid.NewV4(),
+		KitchenUUID: entity.DefaultKitchenUUID,
+		Name:        "Cheeze Pizza",
+		Temp:        entity.OrderTempHot,
+		ShelfLife:   300,
+		DecayRate:   0.45,
+	}
+}
+
+// newTestParentOrder returns a parent order with a hot and a cold item.
+func newTestParentOrder() entity.ParentOrder {
+	parentOrderUUID := guuid.NewV4()
+	pizza := newTestOrder()
+	pizza.ParentOrderUUID = parentOrderUUID
+
+	return entity.ParentOrder{
+		UUID:        parentOrderUUID,
+		KitchenUUID: entity.DefaultKitchenUUID,
+		Items: []entity.Order{
+			pizza,
+			{
+				UUID:            guuid.NewV4(),
+				KitchenUUID:     entity.DefaultKitchenUUID,
+				ParentOrderUUID: parentOrderUUID,
+				Name:            "Yogurt",
+				Temp:            entity.OrderTempCold,
+				ShelfLife:       263,
+				DecayRate:       0.37,
+			},
+		},
+	}
+}
+
+// newTestShelvedOrder returns an order placed on a shelf orderAge ago.
+func newTestShelvedOrder(name string, temp entity.OrderTemp, shelfType entity.ShelfType, orderAge time.Duration) *entity.ShelvedOrder {
+	order := newTestOrder()
+	order.Name = name
+	order.Temp = temp
+	order.DecayRate = 0.5
+
+	return &entity.ShelvedOrder{
+		Order: order,
+		ShelfOrder: entity.ShelfOrder{
+			UUID:        guuid.NewV4(),
+			OrderUUID:   order.UUID,
+			KitchenUUID: entity.DefaultKitchenUUID,
+			Priority:    entity.OrderPriorityStandard,
+			ShelfType:   shelfType,
+			OrderStatus: entity.OrderStatusReadyForPickup,
+			ExpiresAt:   time.Now().Add(200*time.Second - orderAge),
+			CreatedAt:   time.Now().Add(-orderAge),
+		},
+	}
+}
+
+// newTestEvent returns an order shelved event at a kitchen.
+func newTestEvent(kitchenUUID guuid.UUID, orderUUID guuid.UUID) entity.Event {
+	return entity.Event{
+		Type:        entity.EventTypeOrderShelved,
+		KitchenUUID: kitchenUUID,
+		OrderUUID:   orderUUID,
+		ShelfType:   entity.HotShelf,
+		ToStatus:    entity.OrderStatusReadyForPickup,
+	}
+}
+
+// newTestWebhookDelivery returns a pending delivery of an order shelved event to a subscription.
+func newTestWebhookDelivery(subscriptionUUID guuid.UUID) entity.WebhookDelivery {
+	event := newTestEvent(entity.DefaultKitchenUUID, guuid.NewV4())
+	event.ID = 7
+	return entity.NewWebhookDelivery(subscriptionUUID, event, guuid.NewV4(), `{"id":7,"type":"order.shelved"}`, time.Now())
+}
+
+// shelfOrderMatcher holds shelf order matchers.
+type shelfOrderMatcher struct {
+	ShelfOrder entity.ShelfOrder
+}
+
+func (s *shelfOrderMatcher) String() string {
+	return "shelf order matches expected parameters"
+}
+
+func (s *shelfOrderMatcher) Matches(x interface{}) bool {
+	shelfOrder := x.(entity.ShelfOrder)
+	doesMatch := (s.ShelfOrder.OrderUUID == shelfOrder.OrderUUID &&
+		s.ShelfOrder.ShelfType == shelfOrder.ShelfType &&
+		s.ShelfOrder.OrderStatus == shelfOrder.OrderStatus &&
+		s.ShelfOrder.Version == shelfOrder.Version)
+
+	now := time.Now()
+	isExpiresAtInFuture := shelfOrder.ExpiresAt.After(now)
+	return doesMatch && isExpiresAtInFuture
+}
+
+// orderEventMatcher holds order event matchers.
+type orderEventMatcher struct {
+	OrderEvent entity.OrderEvent
+}
+
+func (o *orderEventMatcher) String() string {
+	return "order event matches expected parameters"
+}
+
+// Matches compares the fields that are set on the expected order event.
+func (o *orderEventMatcher) Matches(x interface{}) bool {
+	orderEvent := x.(entity.OrderEvent)
+	nullUUID := guuid.NullUUID{}
+
+	doesOrderMatch := o.OrderEvent.OrderUUID == nullUUID.UUID || o.OrderEvent.OrderUUID == orderEvent.OrderUUID
+	doesFromStatusMatch := o.OrderEvent.FromStatus == "" || o.OrderEvent.FromStatus == orderEvent.FromStatus
+	return doesOrderMatch && doesFromStatusMatch && o.OrderEvent.ToStatus == orderEvent.ToStatus
+}
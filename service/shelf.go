@@ -0,0 +1,96 @@
+package service
+
+import (
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// ShelfService is shelf service interface.
+type ShelfService interface {
+	GetShelves(kitchenUUID guuid.UUID, filter entity.ShelfOrderFilter, sortOrder entity.ShelfSortOrder) ([]*entity.ShelfSnapshot, error)
+	GetShelf(kitchenUUID guuid.UUID, filter entity.ShelfOrderFilter, sortOrder entity.ShelfSortOrder) (*entity.ShelfSnapshot, error)
+}
+
+type shelfService struct {
+	cfg                  config.AppConfig
+	kitchenService       KitchenService
+	shelfOrderRepository repository.ShelfOrderRepository
+}
+
+// NewShelfService returns a new shelf service
+// reporting what is on the shelves of a kitchen.
+func NewShelfService(cfg config.AppConfig, kitchenService KitchenService, shelfOrderRepository repository.ShelfOrderRepository) ShelfService {
+	return &shelfService{
+		cfg:                  cfg,
+		kitchenService:       kitchenService,
+		shelfOrderRepository: shelfOrderRepository,
+	}
+}
+
+// GetShelves returns a snapshot of every shelf of a kitchen in placement order.
+// The filter narrows the orders listed on each shelf, occupancy always
+// counts every order ready for pickup.
+func (s *shelfService) GetShelves(kitchenUUID guuid.UUID, filter entity.ShelfOrderFilter, sortOrder entity.ShelfSortOrder) ([]*entity.ShelfSnapshot, error) {
+	kitchen, err := s.kitchenService.GetKitchen(kitchenUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	shelves := kitchen.ShelfCatalog.Shelves
+	if filter.ShelfType != "" {
+		shelf, ok := kitchen.ShelfCatalog.GetShelf(filter.ShelfType)
+		if !ok {
+			return nil, errors.Wrapf(
+				exception.ErrNotFound, "kitchen %s has no %s shelf", kitchenUUID.String(), filter.ShelfType)
+		}
+		shelves = []entity.Shelf{*shelf}
+	}
+
+	occupancies, err := s.shelfOrderRepository.CountOrdersOnShelves(kitchenUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count orders on shelves")
+	}
+
+	shelvedOrders, err := s.shelfOrderRepository.GetShelvedOrders(kitchenUUID, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch orders on shelves")
+	}
+
+	shelvedOrdersByShelf := make(map[entity.ShelfType][]*entity.ShelvedOrder)
+	for _, shelvedOrder := range shelvedOrders {
+		shelfType := shelvedOrder.ShelfOrder.ShelfType
+		shelvedOrdersByShelf[shelfType] = append(shelvedOrdersByShelf[shelfType], shelvedOrder)
+	}
+
+	// Every order on every shelf is valued at the same point in time.
+	now := time.Now()
+	shelfSnapshots := make([]*entity.ShelfSnapshot, 0, len(shelves))
+	for _, shelf := range shelves {
+		shelfSnapshot := entity.NewShelfSnapshot(shelf, occupancies[shelf.Type], shelvedOrdersByShelf[shelf.Type], now)
+		shelfSnapshot.Sort(sortOrder)
+		shelfSnapshots = append(shelfSnapshots, &shelfSnapshot)
+	}
+
+	return shelfSnapshots, nil
+}
+
+// GetShelf returns a snapshot of the shelf of a kitchen named by the filter.
+func (s *shelfService) GetShelf(kitchenUUID guuid.UUID, filter entity.ShelfOrderFilter, sortOrder entity.ShelfSortOrder) (*entity.ShelfSnapshot, error) {
+	if filter.ShelfType == "" {
+		return nil, errors.Wrap(exception.ErrInvalidInput, "shelf type is required")
+	}
+
+	shelfSnapshots, err := s.GetShelves(kitchenUUID, filter, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return shelfSnapshots[0], nil
+}
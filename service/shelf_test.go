@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetShelves(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	// Orders on the overflow shelf decay twice as fast.
+	for i := range s.cfg.Shelves {
+		if s.cfg.Shelves[i].Name == "overflow" {
+			s.cfg.Shelves[i].DecayModifier = 2
+		}
+	}
+	shelfService := s.newShelfService()
+
+	pizza := newTestShelvedOrder("Cheeze Pizza", entity.OrderTempHot, entity.HotShelf, 100*time.Second)
+	soup := newTestShelvedOrder("Tomato Soup", entity.OrderTempHot, entity.HotShelf, 10*time.Second)
+	yogurt := newTestShelvedOrder("Yogurt", entity.OrderTempCold, entity.OverflowShelf, 100*time.Second)
+
+	filter := entity.ShelfOrderFilter{Statuses: []entity.OrderStatus{entity.OrderStatusReadyForPickup}}
+	s.shelfOrderRepository.EXPECT().CountOrdersOnShelves(entity.DefaultKitchenUUID).Return(
+		map[entity.ShelfType]int{entity.HotShelf: 15, entity.OverflowShelf: 1}, nil)
+	s.shelfOrderRepository.EXPECT().GetShelvedOrders(entity.DefaultKitchenUUID, filter).Return(
+		[]*entity.ShelvedOrder{pizza, soup, yogurt}, nil)
+
+	shelfSnapshots, err := shelfService.GetShelves(
+		entity.DefaultKitchenUUID, filter, entity.ShelfSortOrder{Field: entity.ShelfSortByValue, Descending: true})
+	assert.Nil(t, err)
+
+	// Every shelf of the kitchen is listed in placement order, even empty ones.
+	assert.Equal(t, 4, len(shelfSnapshots))
+	hotShelf, frozenShelf, overflowShelf := shelfSnapshots[0], shelfSnapshots[2], shelfSnapshots[3]
+	assert.Equal(t, entity.HotShelf, hotShelf.Shelf.Type)
+	assert.Equal(t, 15, hotShelf.Occupancy)
+	assert.Equal(t, 0, hotShelf.GetAvailable())
+	assert.Equal(t, 0, frozenShelf.Occupancy)
+	assert.Equal(t, 0, len(frozenShelf.Orders))
+
+	// The freshest order is listed first.
+	assert.Equal(t, 2, len(hotShelf.Orders))
+	assert.Equal(t, "Tomato Soup", hotShelf.Orders[0].Order.Name)
+	assert.Equal(t, "Cheeze Pizza", hotShelf.Orders[1].Order.Name)
+
+	// value = (shelfLife - orderAge) - (decayRate * decayModifier * orderAge)
+	assert.InDelta(t, 150.0, hotShelf.Orders[1].Value, 1)
+	assert.InDelta(t, 0.5, hotShelf.Orders[1].NormalizedValue, 0.01)
+	assert.Equal(t, 1, len(overflowShelf.Orders))
+	assert.InDelta(t, 100.0, overflowShelf.Orders[0].Value, 1)
+}
+
+func TestGetShelf_UnknownShelf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	shelfService := s.newShelfService()
+
+	_, err := shelfService.GetShelf(
+		entity.DefaultKitchenUUID, entity.ShelfOrderFilter{ShelfType: entity.ShelfType("warm-holding")}, entity.DefaultShelfSortOrder)
+	assert.Equal(t, exception.ErrNotFound, errors.Cause(err))
+}
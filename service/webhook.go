@@ -0,0 +1,297 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/exception"
+	"github.com/kitchen-delivery/mapper"
+	"github.com/kitchen-delivery/service/repository"
+
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	WebhookSignatureHeader = "X-Kitchen-Signature" // "t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">"
+	WebhookEventHeader     = "X-Kitchen-Event"     // ex: "order.shelved"
+	WebhookDeliveryHeader  = "X-Kitchen-Delivery"  // delivery uuid, the same across retries
+)
+
+// dueWebhookDeliveriesLimit is the max number of due deliveries fetched at once.
+const dueWebhookDeliveriesLimit = 100
+
+// maxWebhookErrorLength is the max length of an error kept in the delivery log.
+const maxWebhookErrorLength = 1024
+
+// WebhookService is webhook service interface.
+type WebhookService interface {
+	CreateSubscription(webhookSubscription entity.WebhookSubscription) error
+	GetSubscription(subscriptionUUID guuid.UUID) (*entity.WebhookSubscription, error)
+	GetSubscriptions() ([]*entity.WebhookSubscription, error)
+	DeleteSubscription(subscriptionUUID guuid.UUID) error
+	EnqueueDeliveries(outboxEvent entity.OutboxEvent) error
+	GetDueDeliveries() ([]*entity.WebhookDelivery, error)
+	Deliver(webhookDelivery entity.WebhookDelivery) error
+	GetDeliveries(subscriptionUUID guuid.UUID) ([]*entity.WebhookDelivery, error)
+	ReplayDelivery(subscriptionUUID guuid.UUID, deliveryUUID guuid.UUID) (*entity.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	cfg               config.AppConfig
+	webhookRepository repository.WebhookRepository
+	client            *http.Client
+}
+
+// NewWebhookService returns a new webhook service calling partner endpoints back on order status changes.
+func NewWebhookService(cfg config.AppConfig, webhookRepository repository.WebhookRepository) WebhookService {
+	return &webhookService{
+		cfg:               cfg,
+		webhookRepository: webhookRepository,
+		client:            &http.Client{Timeout: time.Duration(cfg.Webhooks.Timeout) * time.Second},
+	}
+}
+
+// CreateSubscription subscribes a partner endpoint to order status changes.
+func (w *webhookService) CreateSubscription(webhookSubscription entity.WebhookSubscription) error {
+	err := webhookSubscription.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = w.webhookRepository.CreateSubscription(webhookSubscription)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store webhook subscription %s", webhookSubscription.UUID.String())
+	}
+
+	return nil
+}
+
+// GetSubscription returns a specific webhook subscription.
+func (w *webhookService) GetSubscription(subscriptionUUID guuid.UUID) (*entity.WebhookSubscription, error) {
+	webhookSubscription, err := w.webhookRepository.GetSubscription(subscriptionUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch webhook subscription %s", subscriptionUUID.String())
+	}
+
+	return webhookSubscription, nil
+}
+
+// GetSubscriptions returns every webhook subscription.
+func (w *webhookService) GetSubscriptions() ([]*entity.WebhookSubscription, error) {
+	webhookSubscriptions, err := w.webhookRepository.GetSubscriptions()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch webhook subscriptions")
+	}
+
+	return webhookSubscriptions, nil
+}
+
+// DeleteSubscription unsubscribes a partner endpoint, pending deliveries are dropped with it.
+func (w *webhookService) DeleteSubscription(subscriptionUUID guuid.UUID) error {
+	err := w.webhookRepository.DeleteSubscription(subscriptionUUID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete webhook subscription %s", subscriptionUUID.String())
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries stores a pending delivery of an outbox event for every subscription it matches,
+// deliveries are sent by the webhook workers so slow endpoints never hold up orders.
+func (w *webhookService) EnqueueDeliveries(outboxEvent entity.OutboxEvent) error {
+	event := outboxEvent.Event
+
+	webhookSubscriptions, err := w.webhookRepository.GetSubscriptions()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch webhook subscriptions")
+	}
+
+	payload, err := json.Marshal(mapper.EventToJSON(event))
+	if err != nil {
+		return errors.Wrapf(exception.ErrUnhandledException, "failed to encode event %d - err: %s", event.ID, err)
+	}
+
+	now := time.Now()
+	var webhookDeliveries []entity.WebhookDelivery
+	for _, webhookSubscription := range webhookSubscriptions {
+		if !webhookSubscription.Matches(event) {
+			continue
+		}
+
+		webhookDeliveries = append(
+			webhookDeliveries, entity.NewWebhookDelivery(webhookSubscription.UUID, event, outboxEvent.DedupID, string(payload), now))
+	}
+	if len(webhookDeliveries) == 0 {
+		return nil
+	}
+
+	err = w.webhookRepository.CreateDeliveries(webhookDeliveries)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store webhook deliveries of event %d", event.ID)
+	}
+
+	return nil
+}
+
+// GetDueDeliveries returns pending deliveries that are due to be attempted.
+func (w *webhookService) GetDueDeliveries() ([]*entity.WebhookDelivery, error) {
+	webhookDeliveries, err := w.webhookRepository.GetDueDeliveries(time.Now(), dueWebhookDeliveriesLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch due webhook deliveries")
+	}
+
+	return webhookDeliveries, nil
+}
+
+// Deliver attempts a pending delivery and records the outcome in the delivery log.
+// Failed attempts are retried with exponential backoff until they run out of attempts.
+// Deliveries claimed by another worker are skipped.
+func (w *webhookService) Deliver(webhookDelivery entity.WebhookDelivery) error {
+	if webhookDelivery.Status != entity.WebhookDeliveryStatusPending {
+		return errors.Wrapf(
+			exception.ErrInvalidResourceState, "webhook delivery %s is %s", webhookDelivery.UUID.String(), webhookDelivery.Status)
+	}
+
+	// Claim the delivery so it is not picked up again while it is in flight,
+	// if this worker dies the delivery is attempted again once the claim runs out.
+	now := time.Now()
+	webhookDelivery.NextAttemptAt = now.Add(2 * w.client.Timeout)
+	err := w.webhookRepository.UpdateDelivery(webhookDelivery)
+	if err != nil {
+		return errors.Wrapf(err, "failed to claim webhook delivery %s", webhookDelivery.UUID.String())
+	}
+	webhookDelivery.Version++
+
+	webhookDelivery.Attempts++
+	webhookDelivery.LastAttemptAt = now
+
+	webhookSubscription, err := w.webhookRepository.GetSubscription(webhookDelivery.SubscriptionUUID)
+	switch errors.Cause(err) {
+	case nil:
+		webhookDelivery.LastStatusCode, err = w.post(*webhookSubscription, webhookDelivery, now)
+	case exception.ErrNotFound:
+		// The subscription was deleted while the delivery was pending.
+		webhookDelivery.Status = entity.WebhookDeliveryStatusFailed
+		webhookDelivery.LastError = "webhook subscription was deleted"
+		return w.updateDelivery(webhookDelivery)
+	default:
+		return errors.Wrapf(err, "failed to fetch webhook subscription of delivery %s", webhookDelivery.UUID.String())
+	}
+
+	if err == nil {
+		webhookDelivery.Status = entity.WebhookDeliveryStatusDelivered
+		webhookDelivery.LastError = ""
+		webhookDelivery.DeliveredAt = time.Now()
+		return w.updateDelivery(webhookDelivery)
+	}
+
+	webhookDelivery.LastError = truncate(err.Error(), maxWebhookErrorLength)
+	if webhookDelivery.Attempts >= w.cfg.Webhooks.MaxAttempts {
+		webhookDelivery.Status = entity.WebhookDeliveryStatusFailed
+		return w.updateDelivery(webhookDelivery)
+	}
+
+	webhookDelivery.NextAttemptAt = now.Add(entity.GetWebhookBackoff(
+		webhookDelivery.Attempts,
+		time.Duration(w.cfg.Webhooks.InitialBackoff)*time.Second,
+		time.Duration(w.cfg.Webhooks.MaxBackoff)*time.Second,
+	))
+	return w.updateDelivery(webhookDelivery)
+}
+
+// post sends a signed delivery to a subscription's url and returns the response status code.
+// Anything but a 2xx response is an error.
+func (w *webhookService) post(
+	webhookSubscription entity.WebhookSubscription, webhookDelivery entity.WebhookDelivery, now time.Time) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, webhookSubscription.URL, bytes.NewBufferString(webhookDelivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	signature := entity.SignWebhookPayload(webhookSubscription.Secret, timestamp, webhookDelivery.Payload)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookSignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, signature))
+	request.Header.Set(WebhookEventHeader, string(webhookDelivery.EventType))
+	request.Header.Set(WebhookDeliveryHeader, webhookDelivery.UUID.String())
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	// Drain the body so the connection is reused.
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, errors.Errorf("subscription responded with %s", response.Status)
+	}
+
+	return response.StatusCode, nil
+}
+
+// updateDelivery records the outcome of a delivery attempt.
+func (w *webhookService) updateDelivery(webhookDelivery entity.WebhookDelivery) error {
+	err := w.webhookRepository.UpdateDelivery(webhookDelivery)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update webhook delivery %s", webhookDelivery.UUID.String())
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the delivery log of a webhook subscription.
+func (w *webhookService) GetDeliveries(subscriptionUUID guuid.UUID) ([]*entity.WebhookDelivery, error) {
+	// The delivery log of a subscription that does not exist is not found rather than empty.
+	_, err := w.GetSubscription(subscriptionUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookDeliveries, err := w.webhookRepository.GetDeliveries(subscriptionUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch webhook deliveries of subscription %s", subscriptionUUID.String())
+	}
+
+	return webhookDeliveries, nil
+}
+
+// ReplayDelivery sends a delivery of a subscription again as a new delivery,
+// whether the original delivery succeeded or not.
+func (w *webhookService) ReplayDelivery(subscriptionUUID guuid.UUID, deliveryUUID guuid.UUID) (*entity.WebhookDelivery, error) {
+	webhookDelivery, err := w.webhookRepository.GetDelivery(deliveryUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch webhook delivery %s", deliveryUUID.String())
+	}
+	if webhookDelivery.SubscriptionUUID != subscriptionUUID {
+		return nil, errors.Wrapf(exception.ErrNotFound,
+			"webhook delivery %s does not belong to subscription %s", deliveryUUID.String(), subscriptionUUID.String())
+	}
+
+	replayedDelivery := webhookDelivery.Replay(time.Now())
+	err = w.webhookRepository.CreateDeliveries([]entity.WebhookDelivery{replayedDelivery})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to store replay of webhook delivery %s", deliveryUUID.String())
+	}
+
+	return &replayedDelivery, nil
+}
+
+// truncate shortens a string to a max length.
+func truncate(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+
+	return s[:maxLength]
+}
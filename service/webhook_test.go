@@ -0,0 +1,267 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kitchen-delivery/entity"
+	"github.com/kitchen-delivery/entity/endpoint"
+	"github.com/kitchen-delivery/entity/exception"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	guuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSubscription_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	webhookService := s.newWebhookService()
+
+	invalidSubscriptions := []entity.WebhookSubscription{
+		{UUID: guuid.NewV4(), URL: "ftp://partner.example.com", Secret: "secret"},
+		{UUID: guuid.NewV4(), URL: "/callbacks", Secret: "secret"},
+		{UUID: guuid.NewV4(), URL: "https://partner.example.com", Secret: " "},
+		{UUID: guuid.NewV4(), URL: "https://partner.example.com", Secret: "secret",
+			EventTypes: []entity.EventType{"order.burnt"}},
+	}
+	for _, webhookSubscription := range invalidSubscriptions {
+		err := webhookService.CreateSubscription(webhookSubscription)
+		assert.Equal(t, exception.ErrInvalidInput, errors.Cause(err), webhookSubscription.String())
+	}
+}
+
+func TestEnqueueDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	webhookService := s.newWebhookService()
+
+	everyEvent := entity.WebhookSubscription{UUID: guuid.NewV4()}
+	otherKitchen := entity.WebhookSubscription{UUID: guuid.NewV4(), KitchenUUID: guuid.NewV4()}
+	otherEventType := entity.WebhookSubscription{
+		UUID: guuid.NewV4(), EventTypes: []entity.EventType{entity.EventTypeOrderWasted}}
+	s.webhookRepository.EXPECT().GetSubscriptions().Return(
+		[]*entity.WebhookSubscription{&everyEvent, &otherKitchen, &otherEventType}, nil)
+
+	event := entity.Event{
+		ID:          42,
+		Type:        entity.EventTypeOrderPickedUp,
+		KitchenUUID: entity.DefaultKitchenUUID,
+		OrderUUID:   guuid.NewV4(),
+		FromStatus:  entity.OrderStatusReadyForPickup,
+		ToStatus:    entity.OrderStatusPickedUp,
+	}
+
+	orderEvent, err := entity.NewOrderEvent(
+		event.OrderUUID, entity.OrderStatusReadyForPickup, entity.OrderStatusPickedUp, "picked up by courier")
+	assert.Nil(t, err)
+	outboxEvent := entity.OutboxEvent{DedupID: orderEvent.UUID, Event: event}
+
+	// Only subscriptions matching the event's kitchen and type get a delivery.
+	var deliveryUUID guuid.UUID
+	s.webhookRepository.EXPECT().CreateDeliveries(gomock.Any()).Do(func(webhookDeliveries []entity.WebhookDelivery) {
+		assert.Len(t, webhookDeliveries, 1)
+		deliveryUUID = webhookDeliveries[0].UUID
+		assert.Equal(t, everyEvent.UUID, webhookDeliveries[0].SubscriptionUUID)
+		assert.Equal(t, entity.WebhookDeliveryStatusPending, webhookDeliveries[0].Status)
+		assert.Equal(t, event.OrderUUID, webhookDeliveries[0].OrderUUID)
+
+		payload := endpoint.EventJSON{}
+		assert.Nil(t, json.Unmarshal([]byte(webhookDeliveries[0].Payload), &payload))
+		assert.Equal(t, uint64(42), payload.ID)
+		assert.Equal(t, string(entity.OrderStatusPickedUp), payload.ToStatus)
+	}).Return(nil).Times(2)
+
+	err = webhookService.EnqueueDeliveries(outboxEvent)
+	assert.Nil(t, err)
+	firstDeliveryUUID := deliveryUUID
+
+	// An event relayed again is given the same delivery, which is only stored once.
+	s.webhookRepository.EXPECT().GetSubscriptions().Return(
+		[]*entity.WebhookSubscription{&everyEvent, &otherKitchen, &otherEventType}, nil)
+
+	err = webhookService.EnqueueDeliveries(outboxEvent)
+	assert.Nil(t, err)
+	assert.Equal(t, firstDeliveryUUID, deliveryUUID)
+}
+
+func TestDeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	webhookService := s.newWebhookService()
+
+	webhookSubscription := entity.WebhookSubscription{UUID: guuid.NewV4(), Secret: "partner-secret"}
+	webhookDelivery := newTestWebhookDelivery(webhookSubscription.UUID)
+
+	// The partner verifies the signature of the timestamp and body.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, webhookDelivery.Payload, string(body))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, string(entity.EventTypeOrderShelved), r.Header.Get(WebhookEventHeader))
+		assert.Equal(t, webhookDelivery.UUID.String(), r.Header.Get(WebhookDeliveryHeader))
+
+		var timestamp int64
+		var signature string
+		_, err = fmt.Sscanf(r.Header.Get(WebhookSignatureHeader), "t=%d,v1=%s", &timestamp, &signature)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.SignWebhookPayload("partner-secret", timestamp, string(body)), signature)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	webhookSubscription.URL = server.URL
+
+	var updatedDelivery entity.WebhookDelivery
+	gomock.InOrder(
+		// The delivery is claimed first.
+		s.webhookRepository.EXPECT().UpdateDelivery(gomock.Any()).Do(func(claimedDelivery entity.WebhookDelivery) {
+			assert.Equal(t, 0, claimedDelivery.Version)
+			assert.True(t, claimedDelivery.NextAttemptAt.After(time.Now()))
+		}).Return(nil),
+		s.webhookRepository.EXPECT().UpdateDelivery(gomock.Any()).Do(func(webhookDelivery entity.WebhookDelivery) {
+			updatedDelivery = webhookDelivery
+		}).Return(nil),
+	)
+	s.webhookRepository.EXPECT().GetSubscription(webhookSubscription.UUID).Return(&webhookSubscription, nil)
+
+	err := webhookService.Deliver(webhookDelivery)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.WebhookDeliveryStatusDelivered, updatedDelivery.Status)
+	assert.Equal(t, 1, updatedDelivery.Attempts)
+	assert.Equal(t, 1, updatedDelivery.Version)
+	assert.Equal(t, http.StatusNoContent, updatedDelivery.LastStatusCode)
+	assert.False(t, updatedDelivery.DeliveredAt.IsZero())
+}
+
+func TestDeliver_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	webhookService := s.newWebhookService()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhookSubscription := entity.WebhookSubscription{UUID: guuid.NewV4(), URL: server.URL, Secret: "partner-secret"}
+	s.webhookRepository.EXPECT().GetSubscription(webhookSubscription.UUID).Return(&webhookSubscription, nil).AnyTimes()
+
+	// Every failed attempt waits twice as long as the one before it, up to the max backoff.
+	expectedBackoffs := map[int]time.Duration{
+		1: 5 * time.Second,
+		2: 10 * time.Second,
+		3: 20 * time.Second,
+		7: 320 * time.Second,
+	}
+	for attempts, expectedBackoff := range expectedBackoffs {
+		webhookDelivery := newTestWebhookDelivery(webhookSubscription.UUID)
+		webhookDelivery.Attempts = attempts - 1
+
+		var updatedDelivery entity.WebhookDelivery
+		gomock.InOrder(
+			s.webhookRepository.EXPECT().UpdateDelivery(gomock.Any()).Return(nil),
+			s.webhookRepository.EXPECT().UpdateDelivery(gomock.Any()).Do(func(webhookDelivery entity.WebhookDelivery) {
+				updatedDelivery = webhookDelivery
+			}).Return(nil),
+		)
+
+		err := webhookService.Deliver(webhookDelivery)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.WebhookDeliveryStatusPending, updatedDelivery.Status)
+		assert.Equal(t, attempts, updatedDelivery.Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, updatedDelivery.LastStatusCode)
+		assert.Contains(t, updatedDelivery.LastError, "503")
+		assert.Equal(t, expectedBackoff, updatedDelivery.NextAttemptAt.Sub(updatedDelivery.LastAttemptAt))
+	}
+	assert.Equal(t, time.Hour, entity.GetWebhookBackoff(20, 5*time.Second, time.Hour))
+}
+
+func TestDeliver_OutOfAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	webhookService := s.newWebhookService()
+
+	// Nothing listens on the subscription's url.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	webhookSubscription := entity.WebhookSubscription{UUID: guuid.NewV4(), URL: server.URL, Secret: "partner-secret"}
+	webhookDelivery := newTestWebhookDelivery(webhookSubscription.UUID)
+	webhookDelivery.Attempts = s.cfg.Webhooks.MaxAttempts - 1
+
+	var updatedDelivery entity.WebhookDelivery
+	gomock.InOrder(
+		s.webhookRepository.EXPECT().UpdateDelivery(gomock.Any()).Return(nil),
+		s.webhookRepository.EXPECT().UpdateDelivery(gomock.Any()).Do(func(webhookDelivery entity.WebhookDelivery) {
+			updatedDelivery = webhookDelivery
+		}).Return(nil),
+	)
+	s.webhookRepository.EXPECT().GetSubscription(webhookSubscription.UUID).Return(&webhookSubscription, nil)
+
+	err := webhookService.Deliver(webhookDelivery)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.WebhookDeliveryStatusFailed, updatedDelivery.Status)
+	assert.Equal(t, s.cfg.Webhooks.MaxAttempts, updatedDelivery.Attempts)
+	assert.Equal(t, 0, updatedDelivery.LastStatusCode)
+	assert.NotEmpty(t, updatedDelivery.LastError)
+}
+
+func TestDeliver_ClaimedByAnotherWorker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	webhookService := s.newWebhookService()
+
+	// The delivery is never sent twice.
+	s.webhookRepository.EXPECT().UpdateDelivery(gomock.Any()).Return(exception.ErrVersionInvalid)
+
+	err := webhookService.Deliver(newTestWebhookDelivery(guuid.NewV4()))
+	assert.Equal(t, exception.ErrVersionInvalid, errors.Cause(err))
+}
+
+func TestReplayDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestServices(t, ctrl)
+	webhookService := s.newWebhookService()
+
+	subscriptionUUID := guuid.NewV4()
+	webhookDelivery := newTestWebhookDelivery(subscriptionUUID)
+	webhookDelivery.Status = entity.WebhookDeliveryStatusFailed
+	webhookDelivery.Attempts = s.cfg.Webhooks.MaxAttempts
+	s.webhookRepository.EXPECT().GetDelivery(webhookDelivery.UUID).Return(&webhookDelivery, nil).Times(2)
+
+	// Deliveries are only replayed through their own subscription.
+	_, err := webhookService.ReplayDelivery(guuid.NewV4(), webhookDelivery.UUID)
+	assert.Equal(t, exception.ErrNotFound, errors.Cause(err))
+
+	s.webhookRepository.EXPECT().CreateDeliveries(gomock.Any()).Return(nil)
+
+	replayedDelivery, err := webhookService.ReplayDelivery(subscriptionUUID, webhookDelivery.UUID)
+	assert.Nil(t, err)
+	assert.NotEqual(t, webhookDelivery.UUID, replayedDelivery.UUID)
+	assert.Equal(t, entity.WebhookDeliveryStatusPending, replayedDelivery.Status)
+	assert.Equal(t, 0, replayedDelivery.Attempts)
+	assert.Equal(t, webhookDelivery.Payload, replayedDelivery.Payload)
+	assert.Equal(t, webhookDelivery.OrderUUID, replayedDelivery.OrderUUID)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kitchen-delivery/config"
+	"github.com/kitchen-delivery/entity"
+)
+
+// Worker metrics are served with the other expvar metrics at /debug/vars, keyed by worker name.
+var (
+	workerCrashesMetric            = expvar.NewMap("worker_crashes")
+	workerRestartsMetric           = expvar.NewMap("worker_restarts")
+	workerConsecutiveCrashesMetric = expvar.NewMap("worker_consecutive_crashes")
+)
+
+// WorkerService is worker service interface.
+type WorkerService interface {
+	RecordCrash(name string, crash string) entity.WorkerStatus
+	RecordRestart(name string)
+	RecordStable(name string)
+	GetWorkerStatuses() []entity.WorkerStatus
+	GetCrashLoopingWorkers() []entity.WorkerStatus
+}
+
+type workerService struct {
+	cfg config.AppConfig
+
+	lock           sync.RWMutex
+	workerStatuses map[string]*entity.WorkerStatus
+}
+
+// NewWorkerService returns a new in memory record of how this instance's supervised workers have been crashing.
+func NewWorkerService(cfg config.AppConfig) WorkerService {
+	return &workerService{
+		cfg:            cfg,
+		workerStatuses: make(map[string]*entity.WorkerStatus),
+	}
+}
+
+// RecordCrash records a panic recovered from a worker and returns the worker's status.
+func (w *workerService) RecordCrash(name string, crash string) entity.WorkerStatus {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	workerStatus := w.getWorkerStatus(name)
+	workerStatus.Crashes++
+	workerStatus.ConsecutiveCrashes++
+	workerStatus.LastCrash = crash
+	workerStatus.LastCrashAt = time.Now()
+	workerStatus.CrashLooping = workerStatus.ConsecutiveCrashes >= w.cfg.Supervisor.CrashLoopThreshold
+
+	workerCrashesMetric.Add(name, 1)
+	w.setConsecutiveCrashesMetric(*workerStatus)
+	return *workerStatus
+}
+
+// RecordRestart records that a worker was restarted after a crash.
+func (w *workerService) RecordRestart(name string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.getWorkerStatus(name).Restarts++
+	workerRestartsMetric.Add(name, 1)
+}
+
+// RecordStable records that a worker ran long enough since its last crash to be considered stable.
+func (w *workerService) RecordStable(name string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	workerStatus, ok := w.workerStatuses[name]
+	if !ok || workerStatus.ConsecutiveCrashes == 0 {
+		return
+	}
+
+	workerStatus.ConsecutiveCrashes = 0
+	workerStatus.CrashLooping = false
+	w.setConsecutiveCrashesMetric(*workerStatus)
+}
+
+// GetWorkerStatuses returns the status of every worker that crashed, sorted by name.
+func (w *workerService) GetWorkerStatuses() []entity.WorkerStatus {
+	return w.filterWorkerStatuses(func(workerStatus entity.WorkerStatus) bool {
+		return true
+	})
+}
+
+// GetCrashLoopingWorkers returns the status of every worker that crashed too many times in a row.
+func (w *workerService) GetCrashLoopingWorkers() []entity.WorkerStatus {
+	return w.filterWorkerStatuses(func(workerStatus entity.WorkerStatus) bool {
+		return workerStatus.CrashLooping
+	})
+}
+
+func (w *workerService) filterWorkerStatuses(include func(workerStatus entity.WorkerStatus) bool) []entity.WorkerStatus {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	var workerStatuses []entity.WorkerStatus
+	for _, workerStatus := range w.workerStatuses {
+		if include(*workerStatus) {
+			workerStatuses = append(workerStatuses, *workerStatus)
+		}
+	}
+	sort.Slice(workerStatuses, func(a, b int) bool {
+		return workerStatuses[a].Name < workerStatuses[b].Name
+	})
+
+	return workerStatuses
+}
+
+// getWorkerStatus returns a worker's status, adding it on its first crash.
+func (w *workerService) getWorkerStatus(name string) *entity.WorkerStatus {
+	workerStatus, ok := w.workerStatuses[name]
+	if !ok {
+		workerStatus = &entity.WorkerStatus{Name: name}
+		w.workerStatuses[name] = workerStatus
+	}
+
+	return workerStatus
+}
+
+func (w *workerService) setConsecutiveCrashesMetric(workerStatus entity.WorkerStatus) {
+	consecutiveCrashes := new(expvar.Int)
+	consecutiveCrashes.Set(int64(workerStatus.ConsecutiveCrashes))
+	workerConsecutiveCrashesMetric.Set(workerStatus.Name, consecutiveCrashes)
+}